@@ -207,6 +207,13 @@ func printSpliceInsertCommand(insert scte35.SpliceInsertCommand) {
 func printPmt(pn int, pmt psi.PMT) {
 	fmt.Printf("Program #%v PMT\n", pn)
 	fmt.Printf("\tPIDs %v\n", pmt.Pids())
+	fmt.Printf("\tPCR PID %v\n", pmt.PCRPid())
+	if len(pmt.ProgramDescriptors()) > 0 {
+		fmt.Println("\tProgram Descriptors")
+		for _, d := range pmt.ProgramDescriptors() {
+			fmt.Printf("\t\t%+v\n", d)
+		}
+	}
 	fmt.Println("\tElementary Streams")
 
 	for _, es := range pmt.ElementaryStreams() {
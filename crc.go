@@ -0,0 +1,127 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package gots
+
+import (
+	"encoding/binary"
+	"hash"
+)
+
+// CRC32Size is the size of a CRC-32 checksum in bytes.
+const CRC32Size = 4
+
+// crc32Init is the initial value of the CRC-32/MPEG-2 register.
+const crc32Init = 0xffffffff
+
+// crc32Poly is the CRC-32/MPEG-2 polynomial. Unlike the IEEE CRC-32 in
+// hash/crc32 it is processed most significant bit first, without reflection
+// or a final XOR.
+const crc32Poly = 0x04c11db7
+
+// crc32Tables holds the slice-by-8 lookup tables. crc32Tables[0] is the
+// classic byte-wise table and crc32Tables[k] advances a byte k positions
+// further through the register.
+var crc32Tables = makeCRC32Tables()
+
+func makeCRC32Tables() *[8][256]uint32 {
+	t := new([8][256]uint32)
+	for i := 0; i < 256; i++ {
+		crc := uint32(i) << 24
+		for j := 0; j < 8; j++ {
+			if crc&0x80000000 != 0 {
+				crc = crc<<1 ^ crc32Poly
+			} else {
+				crc <<= 1
+			}
+		}
+		t[0][i] = crc
+	}
+	for i := 0; i < 256; i++ {
+		crc := t[0][i]
+		for k := 1; k < 8; k++ {
+			crc = crc<<8 ^ t[0][crc>>24]
+			t[k][i] = crc
+		}
+	}
+	return t
+}
+
+// CRC32 returns the CRC-32/MPEG-2 checksum of data as used by PSI and
+// SCTE-35 sections.
+func CRC32(data []byte) uint32 {
+	return UpdateCRC32(crc32Init, data)
+}
+
+// UpdateCRC32 returns the result of adding the bytes in data to crc, which
+// is the checksum of the data preceding it. CRC32(nil) is the checksum of no
+// data, so UpdateCRC32(CRC32(a), b) equals CRC32(append(a, b...)).
+func UpdateCRC32(crc uint32, data []byte) uint32 {
+	t := crc32Tables
+	for len(data) >= 8 {
+		crc ^= binary.BigEndian.Uint32(data)
+		crc = t[7][crc>>24] ^ t[6][crc>>16&0xff] ^ t[5][crc>>8&0xff] ^ t[4][crc&0xff] ^
+			t[3][data[4]] ^ t[2][data[5]] ^ t[1][data[6]] ^ t[0][data[7]]
+		data = data[8:]
+	}
+	for _, b := range data {
+		crc = crc<<8 ^ t[0][byte(crc>>24)^b]
+	}
+	return crc
+}
+
+type crc32Digest struct {
+	crc uint32
+}
+
+// NewCRC32 creates a new hash.Hash32 computing the CRC-32/MPEG-2 checksum.
+// Sum appends the checksum in big endian order, as it is stored in sections.
+func NewCRC32() hash.Hash32 {
+	return &crc32Digest{crc: crc32Init}
+}
+
+func (d *crc32Digest) Write(p []byte) (int, error) {
+	d.crc = UpdateCRC32(d.crc, p)
+	return len(p), nil
+}
+
+func (d *crc32Digest) Sum(b []byte) []byte {
+	return binary.BigEndian.AppendUint32(b, d.crc)
+}
+
+func (d *crc32Digest) Sum32() uint32 {
+	return d.crc
+}
+
+func (d *crc32Digest) Reset() {
+	d.crc = crc32Init
+}
+
+func (d *crc32Digest) Size() int {
+	return CRC32Size
+}
+
+func (d *crc32Digest) BlockSize() int {
+	return 1
+}
@@ -0,0 +1,357 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package demux
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+
+	"github.com/Comcast/gots/v3"
+	"github.com/Comcast/gots/v3/ebp"
+	"github.com/Comcast/gots/v3/packet"
+	"github.com/Comcast/gots/v3/packet/adaptationfield"
+	"github.com/Comcast/gots/v3/pes"
+	"github.com/Comcast/gots/v3/psi"
+	"github.com/Comcast/gots/v3/scte35"
+)
+
+// PATEvent is delivered when a PAT is found for the first time or when
+// it changes.
+type PATEvent struct {
+	PAT psi.PAT
+}
+
+// PMTEvent is delivered when the PMT of a program is found for the first
+// time or when it changes.
+type PMTEvent struct {
+	ProgramNumber int
+	PID           int
+	PMT           psi.PMT
+}
+
+// PESEvent is delivered when a complete PES packet has been reassembled.
+// Header is nil if the PES header could not be parsed.
+type PESEvent struct {
+	PID    int
+	Header pes.PESHeader
+	Data   []byte
+}
+
+// SCTE35Event is delivered when a complete SCTE35 section has been parsed.
+type SCTE35Event struct {
+	PID    int
+	SCTE35 scte35.SCTE35
+}
+
+// EBPEvent is delivered when a packet carrying an EBP is found.
+type EBPEvent struct {
+	PID int
+	EBP ebp.EncoderBoundaryPoint
+}
+
+// Demuxer dispatches the packets of a transport stream by PID. It tracks
+// the PAT and the PMTs of every program with a psi.Monitor and follows the
+// PMTs to the elementary streams, reassembling sections and PES packets along
+// the way. Malformed tables and sections are skipped.
+// Demuxer is not thread safe.
+type Demuxer interface {
+	// OnPAT registers a handler called when a new PAT is found.
+	OnPAT(func(PATEvent))
+	// OnPMT registers a handler called when a new PMT or PMT version is found.
+	OnPMT(func(PMTEvent))
+	// OnPES registers a handler called for every complete PES packet.
+	OnPES(func(PESEvent))
+	// OnSCTE35 registers a handler called for every SCTE35 section.
+	OnSCTE35(func(SCTE35Event))
+	// OnEBP registers a handler called for every EBP.
+	OnEBP(func(EBPEvent))
+	// WritePacket processes a single packet. It allows the demuxer to be fed
+	// packets directly instead of reading them with Run.
+	WritePacket(*packet.Packet) (int, error)
+	// Flush delivers any PES packets whose end has not yet been signalled
+	// by the start of the next PES packet.
+	Flush()
+	// Run syncs to the first packet of the reader and processes packets until
+	// EOF, then flushes. Only errors other than EOF are returned.
+	Run() error
+}
+
+// Kinds of elementary streams tracked by the demuxer
+const (
+	kindPES = iota
+	kindSCTE35
+)
+
+type stream struct {
+	kind          int
+	programNumber int
+	acc           packet.Accumulator
+	// pending is true when the accumulator holds an unterminated PES packet
+	pending bool
+}
+
+type demuxer struct {
+	r       io.Reader
+	monitor psi.Monitor
+	streams map[int]*stream
+
+	patHandlers    []func(PATEvent)
+	pmtHandlers    []func(PMTEvent)
+	pesHandlers    []func(PESEvent)
+	scte35Handlers []func(SCTE35Event)
+	ebpHandlers    []func(EBPEvent)
+}
+
+// NewDemuxer creates a new Demuxer reading from r. r may be nil if packets
+// are only provided through WritePacket.
+func NewDemuxer(r io.Reader) Demuxer {
+	d := &demuxer{
+		r:       r,
+		monitor: psi.NewMonitor(),
+		streams: make(map[int]*stream),
+	}
+	d.monitor.OnPATChange(d.handlePATChange)
+	d.monitor.OnPMTChange(d.handlePMTChange)
+	return d
+}
+
+func newSectionStream(kind, programNumber int) *stream {
+	return &stream{
+		kind:          kind,
+		programNumber: programNumber,
+		acc:           packet.NewAccumulator(psi.PmtAccumulatorDoneFunc),
+	}
+}
+
+func newPESStream(programNumber int) *stream {
+	return &stream{
+		kind:          kindPES,
+		programNumber: programNumber,
+		acc:           packet.NewAccumulator(pesAccumulatorDoneFunc),
+	}
+}
+
+// pesAccumulatorDoneFunc is done when a PES packet with a specified length
+// has been fully received. PES packets with an unspecified length (zero) are
+// terminated by the start of the next PES packet instead.
+func pesAccumulatorDoneFunc(b []byte) (bool, error) {
+	if len(b) < 6 {
+		return false, nil
+	}
+	length := int(binary.BigEndian.Uint16(b[4:6]))
+	return length != 0 && len(b) >= 6+length, nil
+}
+
+func (d *demuxer) OnPAT(h func(PATEvent)) {
+	d.patHandlers = append(d.patHandlers, h)
+}
+
+func (d *demuxer) OnPMT(h func(PMTEvent)) {
+	d.pmtHandlers = append(d.pmtHandlers, h)
+}
+
+func (d *demuxer) OnPES(h func(PESEvent)) {
+	d.pesHandlers = append(d.pesHandlers, h)
+}
+
+func (d *demuxer) OnSCTE35(h func(SCTE35Event)) {
+	d.scte35Handlers = append(d.scte35Handlers, h)
+}
+
+func (d *demuxer) OnEBP(h func(EBPEvent)) {
+	d.ebpHandlers = append(d.ebpHandlers, h)
+}
+
+func (d *demuxer) Run() error {
+	r := bufio.NewReader(d.r)
+	if _, err := packet.Sync(r); err != nil {
+		return err
+	}
+
+	var pkt packet.Packet
+	for {
+		if _, err := io.ReadFull(r, pkt[:]); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				d.Flush()
+				return nil
+			}
+			return err
+		}
+		if _, err := d.WritePacket(&pkt); err != nil {
+			return err
+		}
+	}
+}
+
+func (d *demuxer) WritePacket(pkt *packet.Packet) (int, error) {
+	pid := packet.Pid(pkt)
+
+	if len(d.ebpHandlers) > 0 {
+		d.checkEBP(pid, pkt)
+	}
+
+	if _, err := d.monitor.WritePacket(pkt); err != nil {
+		return packet.PacketSize, err
+	}
+
+	s, ok := d.streams[pid]
+	if !ok {
+		return packet.PacketSize, nil
+	}
+
+	if s.kind == kindPES {
+		d.writePES(pid, s, pkt)
+		return packet.PacketSize, nil
+	}
+
+	_, err := s.acc.WritePacket(pkt)
+	if err != gots.ErrAccumulatorDone {
+		// Nothing is complete yet, or the section is broken and
+		// accumulation restarts on the next PUSI.
+		return packet.PacketSize, nil
+	}
+	b := s.acc.Bytes()
+	s.acc.Reset()
+	d.handleSCTE35(pid, b)
+	return packet.PacketSize, nil
+}
+
+func (d *demuxer) Flush() {
+	for pid, s := range d.streams {
+		if s.kind == kindPES && s.pending {
+			d.emitPES(pid, s.acc.Bytes())
+			s.acc.Reset()
+			s.pending = false
+		}
+	}
+}
+
+func (d *demuxer) checkEBP(pid int, pkt *packet.Packet) {
+	ebpBytes, err := adaptationfield.EncoderBoundaryPoint(pkt)
+	if err != nil {
+		return
+	}
+	boundaryPoint, err := ebp.ReadEncoderBoundaryPoint(ebpBytes)
+	if err != nil {
+		return
+	}
+	for _, h := range d.ebpHandlers {
+		h(EBPEvent{PID: pid, EBP: boundaryPoint})
+	}
+}
+
+func (d *demuxer) writePES(pid int, s *stream, pkt *packet.Packet) {
+	if len(d.pesHandlers) == 0 {
+		return
+	}
+
+	// The start of a new PES packet terminates the previous one.
+	if packet.PayloadUnitStartIndicator(pkt) && s.pending {
+		d.emitPES(pid, s.acc.Bytes())
+		s.acc.Reset()
+		s.pending = false
+	}
+
+	_, err := s.acc.WritePacket(pkt)
+	switch err {
+	case nil:
+		s.pending = true
+	case gots.ErrAccumulatorDone:
+		b := s.acc.Bytes()
+		length := 6 + int(binary.BigEndian.Uint16(b[4:6]))
+		d.emitPES(pid, b[:length])
+		s.acc.Reset()
+		s.pending = false
+	}
+}
+
+func (d *demuxer) emitPES(pid int, b []byte) {
+	header, err := pes.NewPESHeader(b)
+	if err != nil {
+		header = nil
+	}
+	for _, h := range d.pesHandlers {
+		h(PESEvent{PID: pid, Header: header, Data: b})
+	}
+}
+
+func (d *demuxer) handlePATChange(c psi.PATChange) {
+	for _, h := range d.patHandlers {
+		h(PATEvent{PAT: c.New})
+	}
+}
+
+// isPSIPid returns true for the PAT PID and the PMT PIDs of the current PAT.
+func (d *demuxer) isPSIPid(pid int) bool {
+	if pid == psi.PatPid {
+		return true
+	}
+	if pat := d.monitor.PAT(); pat != nil {
+		for _, pmtPid := range pat.ProgramMap() {
+			if pmtPid == pid {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (d *demuxer) handlePMTChange(c psi.PMTChange) {
+	for _, es := range c.Diff.Removed {
+		delete(d.streams, es.ElementaryPid())
+	}
+	if c.New == nil {
+		return
+	}
+	for _, es := range c.New.ElementaryStreams() {
+		esPid := es.ElementaryPid()
+		if d.isPSIPid(esPid) {
+			// never let an elementary stream shadow a PSI PID
+			continue
+		}
+		if es.IsSCTE35Content() {
+			if s, ok := d.streams[esPid]; !ok || s.kind != kindSCTE35 {
+				d.streams[esPid] = newSectionStream(kindSCTE35, c.ProgramNumber)
+			}
+		} else if s, ok := d.streams[esPid]; !ok || s.kind != kindPES {
+			d.streams[esPid] = newPESStream(c.ProgramNumber)
+		}
+	}
+
+	for _, h := range d.pmtHandlers {
+		h(PMTEvent{ProgramNumber: c.ProgramNumber, PID: c.PID, PMT: c.New})
+	}
+}
+
+func (d *demuxer) handleSCTE35(pid int, b []byte) {
+	msg, err := scte35.NewSCTE35(b)
+	if err != nil {
+		return
+	}
+	for _, h := range d.scte35Handlers {
+		h(SCTE35Event{PID: pid, SCTE35: msg})
+	}
+}
@@ -0,0 +1,184 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package demux
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Comcast/gots/v3/packet"
+	"github.com/Comcast/gots/v3/scte35"
+)
+
+var comcastEBPBytes = []byte{
+	0xA9,                   // Tag
+	0x0E,                   // Length
+	0xBD,                   // Flags
+	0x01,                   // Extensions
+	0x02,                   // SAP
+	0x03,                   // Grouping
+	0xD6, 0xEE, 0x7B, 0xD8, // Time Second
+	0x8D, 0xC7, 0x14, 0xFC, // Time Fraction
+	0x04, 0x05} // Reserved
+
+func pesPacket(pid int, cc uint8, pusi bool) *packet.Packet {
+	pkt := packet.Create(pid, packet.WithHasPayloadFlag)
+	if pusi {
+		packet.WithPES(pkt, 900000)
+		pkt.SetPayloadUnitStartIndicator(true)
+	}
+	return packet.SetCC(pkt, cc)
+}
+
+func scte35Packet(pid int) *packet.Packet {
+	pay := append([]byte{0x00}, scte35.CreateSCTE35().UpdateData()...)
+	pkt := packet.CreatePacketWithPayload(pid, 0, pay)
+	pkt.SetPayloadUnitStartIndicator(true)
+	for i := 4 + len(pay); i < packet.PacketSize; i++ {
+		pkt[i] = 0xFF
+	}
+	return pkt
+}
+
+func ebpPacket(t *testing.T, pid int) *packet.Packet {
+	pkt := packet.New()
+	pkt.SetPID(pid)
+	if err := pkt.SetAdaptationFieldControl(packet.AdaptationFieldFlag); err != nil {
+		t.Fatal(err)
+	}
+	af, err := pkt.AdaptationField()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := af.SetHasTransportPrivateData(true); err != nil {
+		t.Fatal(err)
+	}
+	if err := af.SetTransportPrivateData(comcastEBPBytes); err != nil {
+		t.Fatal(err)
+	}
+	return pkt
+}
+
+func TestDemuxerRun(t *testing.T) {
+	pat := packet.TestPatPacket
+	pmt := packet.TestPmtPacket
+	packets := []*packet.Packet{
+		&pat,
+		&pmt,
+		pesPacket(101, 0, true),
+		pesPacket(101, 1, false),
+		scte35Packet(110),
+		ebpPacket(t, 102),
+		pesPacket(101, 2, true),
+		&pat, // repeated tables must not produce new events
+		&pmt,
+	}
+	var buf bytes.Buffer
+	for _, pkt := range packets {
+		buf.Write(pkt[:])
+	}
+
+	d := NewDemuxer(&buf)
+	var pats, pmts, scte35s, ebps int
+	var pesSizes []int
+	d.OnPAT(func(e PATEvent) {
+		pats++
+		if e.PAT.ProgramMap()[1] != 100 {
+			t.Errorf("Unexpected program map %v", e.PAT.ProgramMap())
+		}
+	})
+	d.OnPMT(func(e PMTEvent) {
+		pmts++
+		if e.PID != 100 || e.ProgramNumber != 1 {
+			t.Errorf("Unexpected PMT event PID %d program %d", e.PID, e.ProgramNumber)
+		}
+	})
+	d.OnPES(func(e PESEvent) {
+		if e.PID != 101 {
+			t.Errorf("Unexpected PES on PID %d", e.PID)
+		}
+		if e.Header == nil || !e.Header.HasPTS() || e.Header.PTS() != 900000 {
+			t.Errorf("PES header not parsed correctly")
+		}
+		pesSizes = append(pesSizes, len(e.Data))
+	})
+	d.OnSCTE35(func(e SCTE35Event) {
+		scte35s++
+		if e.PID != 110 {
+			t.Errorf("Unexpected SCTE35 on PID %d", e.PID)
+		}
+	})
+	d.OnEBP(func(e EBPEvent) {
+		ebps++
+		if e.PID != 102 {
+			t.Errorf("Unexpected EBP on PID %d", e.PID)
+		}
+	})
+
+	if err := d.Run(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if pats != 1 {
+		t.Errorf("Expected 1 PAT event, got %d", pats)
+	}
+	if pmts != 1 {
+		t.Errorf("Expected 1 PMT event, got %d", pmts)
+	}
+	if scte35s != 1 {
+		t.Errorf("Expected 1 SCTE35 event, got %d", scte35s)
+	}
+	if ebps != 1 {
+		t.Errorf("Expected 1 EBP event, got %d", ebps)
+	}
+	// The first PES spans two packets and is terminated by the next PUSI,
+	// the second is terminated by the flush at EOF.
+	if len(pesSizes) != 2 || pesSizes[0] != 2*184 || pesSizes[1] != 184 {
+		t.Errorf("Unexpected PES packet sizes %v", pesSizes)
+	}
+}
+
+func TestDemuxerBoundedPES(t *testing.T) {
+	pat := packet.TestPatPacket
+	pmt := packet.TestPmtPacket
+	d := NewDemuxer(nil)
+
+	var got [][]byte
+	d.OnPES(func(e PESEvent) {
+		got = append(got, e.Data)
+	})
+	d.WritePacket(&pat)
+	d.WritePacket(&pmt)
+
+	pkt := pesPacket(101, 0, true)
+	// PES_packet_length of 20 bytes after the length field
+	pkt[8] = 0x00
+	pkt[9] = 20
+	d.WritePacket(pkt)
+
+	if len(got) != 1 || len(got[0]) != 26 {
+		t.Fatalf("Expected a single 26 byte PES packet, got %d packets", len(got))
+	}
+}
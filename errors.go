@@ -24,7 +24,10 @@ SOFTWARE.
 
 package gots
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// ErrBadSyncByte is returned when the sync byte (first byte of packet) is not valid
@@ -80,8 +83,34 @@ var (
 	ErrNoPayloadUnitStartIndicator = errors.New("packet does not have payload unit start indicator")
 	// ErrUnknownTableID is returned when PSI is parsed with an unknown table id
 	ErrUnknownTableID = errors.New("Unknown table id received")
+	// ErrCRCMismatch is returned when the CRC of a section does not match its
+	// contents. The error returned by parsers is a *CRCMismatchError wrapping it.
+	ErrCRCMismatch = errors.New("CRC mismatch")
+	// ErrUnsupportedStringCompression is returned when a string uses a compression or mode that is not supported
+	ErrUnsupportedStringCompression = errors.New("unsupported string compression")
+	// ErrNoTimeReference is returned when no time table has been correlated with the PCR yet
+	ErrNoTimeReference = errors.New("no time reference")
+	// ErrDescriptorNotFound is returned when an expected descriptor is not present
+	ErrDescriptorNotFound = errors.New("descriptor not found")
+	// ErrInvalidSection is returned when bytes do not hold a long form PSI section
+	ErrInvalidSection = errors.New("not a valid long form PSI section")
+	// ErrNoNetworkPID is returned when a PAT does not list a network PID
+	ErrNoNetworkPID = errors.New("PAT does not contain a network PID")
+	// ErrSectionTooLong is returned when a PSI section can not be created because
+	// its content exceeds the maximum section length
+	ErrSectionTooLong = errors.New("section exceeds the maximum section length")
+	// ErrMixedSections is returned when the sections given to a table constructor
+	// belong to different tables
+	ErrMixedSections = errors.New("sections belong to different tables")
+	// ErrFieldOutOfRange is returned when a PSI table can not be created because
+	// a field value does not fit in the bits of the field
+	ErrFieldOutOfRange = errors.New("field value is out of range")
+	// ErrInvalidDescriptor is returned when descriptor fields are out of range or too long to be encoded
+	ErrInvalidDescriptor = errors.New("descriptor fields cannot be encoded")
 	// ErrTableHeaderShort is returned when a PSI table header is too short to parse
 	ErrShortPayload = errors.New("provided data is too short to parse")
+	// ErrInvalidSCTE18Descriptor is returned when the descriptor loop of a SCTE18 emergency alert is malformed
+	ErrInvalidSCTE18Descriptor = errors.New("malformed descriptor in SCTE18 emergency alert")
 	// ErrInvalidSCTE35Length is returned when a SCTE35 cue cannot be parsed because there are not enough bytes
 	ErrInvalidSCTE35Length = errors.New("too few bytes to parse SCTE35")
 	// ErrSCTE35EncryptionUnsupported is returned when a scte35 cue cannot be parsed because it is encrypted
@@ -104,6 +133,15 @@ var (
 	// ErrSyncByteNotFound is returned when a packet sync byte could not be found
 	// when reading.
 	ErrSyncByteNotFound = errors.New("Sync-byte not found.")
+	// ErrFramingNotDetected is returned when the packet framing of a stream
+	// could not be determined.
+	ErrFramingNotDetected = errors.New("Packet framing not detected.")
+	// ErrUnknownFraming is returned when a packet framing is not one of the
+	// supported framings.
+	ErrUnknownFraming = errors.New("Unknown packet framing.")
+	// ErrInvalidFrameExtra is returned when the extra bytes written with a
+	// packet do not match the size required by the framing.
+	ErrInvalidFrameExtra = errors.New("Invalid number of extra framing bytes.")
 	// ErrVSSSignalIdNotFound is returned when we do not find SignalID in the VSS signal's MID.
 	ErrVSSSignalIdNotFound = errors.New("VSS Signal ID not found in the VSS signal received.")
 	// ErrPIDNotInPMT
@@ -113,3 +151,19 @@ var (
 	// ErrInvalidState should be unreachable but is returned if the accumulator has reached an invalid state
 	ErrAccumulatorInvalidState = errors.New("Accumulator is in an invalid state.")
 )
+
+// CRCMismatchError carries the CRC found in a section and the CRC computed
+// from its contents. It matches ErrCRCMismatch with errors.Is.
+type CRCMismatchError struct {
+	Expected uint32
+	Actual   uint32
+}
+
+func (e *CRCMismatchError) Error() string {
+	return fmt.Sprintf("%v: expected 0x%08X, computed 0x%08X", ErrCRCMismatch, e.Expected, e.Actual)
+}
+
+// Unwrap returns ErrCRCMismatch.
+func (e *CRCMismatchError) Unwrap() error {
+	return ErrCRCMismatch
+}
@@ -0,0 +1,45 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package fields
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Comcast/gots/v3"
+)
+
+func TestLengthPrefixed(t *testing.T) {
+	field, rest, err := LengthPrefixed([]byte{2, 'a', 'b', 'c'})
+	if err != nil || !bytes.Equal(field, []byte("ab")) || !bytes.Equal(rest, []byte("c")) {
+		t.Errorf("Unexpected field %q, remainder %q, error %v", field, rest, err)
+	}
+	if _, _, err := LengthPrefixed([]byte{3, 'a', 'b'}); err != gots.ErrShortPayload {
+		t.Errorf("Expected ErrShortPayload, got %v", err)
+	}
+	if _, _, err := LengthPrefixed(nil); err != gots.ErrShortPayload {
+		t.Errorf("Expected ErrShortPayload, got %v", err)
+	}
+}
@@ -0,0 +1,217 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package packet
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// ContinuityResult describes the outcome of checking a packet's continuity counter.
+type ContinuityResult int
+
+const (
+	// ContinuityOK means the continuity counter was as expected or could not be checked.
+	ContinuityOK ContinuityResult = iota
+	// ContinuityLoss means one or more packets are missing before this packet.
+	ContinuityLoss
+	// ContinuityDuplicate means the packet repeats the previous continuity
+	// counter and payload. A single duplicate packet is allowed by ISO/IEC
+	// 13818-1. A repeated continuity counter with a different payload is
+	// reported as ContinuityLoss.
+	ContinuityDuplicate
+	// ContinuityIllegalRepeat means the continuity counter was repeated more
+	// than once in a row.
+	ContinuityIllegalRepeat
+	// ContinuityDiscontinuity means the continuity counter jumped on a packet
+	// whose discontinuity_indicator is set. This is not an error.
+	ContinuityDiscontinuity
+)
+
+var continuityResultNames = map[ContinuityResult]string{
+	ContinuityOK:            "ContinuityOK",
+	ContinuityLoss:          "ContinuityLoss",
+	ContinuityDuplicate:     "ContinuityDuplicate",
+	ContinuityIllegalRepeat: "ContinuityIllegalRepeat",
+	ContinuityDiscontinuity: "ContinuityDiscontinuity",
+}
+
+func (r ContinuityResult) String() string {
+	if name, ok := continuityResultNames[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("ContinuityResult(%d)", int(r))
+}
+
+// ContinuityEvent is the result of checking the continuity counter of a
+// single packet.
+type ContinuityEvent struct {
+	PID    int
+	Result ContinuityResult
+	// Expected is the continuity counter that was expected.
+	Expected uint8
+	// Got is the continuity counter of the packet.
+	Got uint8
+	// Missing is the estimated number of lost packets when Result is
+	// ContinuityLoss. Since the counter is 4 bits, multiples of 16 packets
+	// cannot be detected.
+	Missing int
+}
+
+// ContinuityStats holds the continuity counters accumulated for a PID.
+type ContinuityStats struct {
+	Packets         uint64
+	Losses          uint64
+	LostPackets     uint64
+	Duplicates      uint64
+	IllegalRepeats  uint64
+	Discontinuities uint64
+}
+
+// ContinuityTracker follows the continuity counter of every PID in a stream
+// and reports lost, duplicated and repeated packets.
+// ContinuityTracker is not thread safe.
+type ContinuityTracker interface {
+	// Update checks the continuity counter of pkt against the previous
+	// packet on the same PID and returns the result. Null packets and packets
+	// without a payload are not checked since their continuity counter does
+	// not increment.
+	Update(pkt *Packet) ContinuityEvent
+	// Stats returns the continuity statistics gathered for a PID.
+	Stats(pid int) ContinuityStats
+	// Reset forgets the state of all PIDs.
+	Reset()
+}
+
+type pidContinuity struct {
+	cc         uint8
+	payload    []byte
+	duplicates int
+	stats      ContinuityStats
+}
+
+type continuityTracker struct {
+	pids map[int]*pidContinuity
+}
+
+// NewContinuityTracker creates a new ContinuityTracker.
+func NewContinuityTracker() ContinuityTracker {
+	return &continuityTracker{pids: make(map[int]*pidContinuity)}
+}
+
+func (t *continuityTracker) Update(pkt *Packet) ContinuityEvent {
+	pid := Pid(pkt)
+	cc := ContinuityCounter(pkt)
+	event := ContinuityEvent{PID: pid, Result: ContinuityOK, Expected: cc, Got: cc}
+
+	if IsNull(pkt) {
+		return event
+	}
+
+	state, ok := t.pids[pid]
+	if !ok {
+		state = &pidContinuity{cc: cc}
+		t.pids[pid] = state
+		if ContainsPayload(pkt) {
+			state.stats.Packets++
+			state.setPayload(pkt)
+		}
+		return event
+	}
+
+	// The continuity counter shall not be incremented when the adaptation
+	// field control is 00 or 10.
+	if !ContainsPayload(pkt) {
+		return event
+	}
+	state.stats.Packets++
+
+	event.Expected = increment4BitInt(state.cc)
+	switch {
+	case isDiscontinuous(pkt):
+		if cc != event.Expected {
+			event.Result = ContinuityDiscontinuity
+			state.stats.Discontinuities++
+		}
+		state.duplicates = 0
+	case cc == event.Expected:
+		state.duplicates = 0
+	case cc == state.cc && state.samePayload(pkt):
+		state.duplicates++
+		if state.duplicates == 1 {
+			event.Result = ContinuityDuplicate
+			state.stats.Duplicates++
+		} else {
+			event.Result = ContinuityIllegalRepeat
+			state.stats.IllegalRepeats++
+		}
+	default:
+		event.Result = ContinuityLoss
+		event.Missing = int((cc - event.Expected) & 0x0f)
+		state.stats.Losses++
+		state.stats.LostPackets += uint64(event.Missing)
+		state.duplicates = 0
+	}
+	state.cc = cc
+	state.setPayload(pkt)
+
+	return event
+}
+
+// setPayload remembers the payload of pkt to detect duplicate packets.
+func (state *pidContinuity) setPayload(pkt *Packet) {
+	payload, _ := Payload(pkt)
+	state.payload = append(state.payload[:0], payload...)
+}
+
+// samePayload returns true if pkt carries the same payload as the previous
+// packet. Only the payload is compared since a duplicate packet may carry a
+// different PCR.
+func (state *pidContinuity) samePayload(pkt *Packet) bool {
+	payload, err := Payload(pkt)
+	return err == nil && bytes.Equal(payload, state.payload)
+}
+
+func (t *continuityTracker) Stats(pid int) ContinuityStats {
+	if state, ok := t.pids[pid]; ok {
+		return state.stats
+	}
+	return ContinuityStats{}
+}
+
+func (t *continuityTracker) Reset() {
+	t.pids = make(map[int]*pidContinuity)
+}
+
+// isDiscontinuous returns true if the packet has an adaptation field with the
+// discontinuity_indicator set.
+func isDiscontinuous(pkt *Packet) bool {
+	af, err := pkt.AdaptationField()
+	if err != nil {
+		return false
+	}
+	discontinuous, err := af.Discontinuity()
+	return err == nil && discontinuous
+}
@@ -0,0 +1,107 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package packet
+
+import "testing"
+
+func discontinuousPacket(pid int, cc uint8) *Packet {
+	pkt := SetCC(Create(pid, WithHasPayloadFlag, WithHasAdaptationFieldFlag), cc)
+	pkt[4] = 1 // adaptation field length
+	pkt[5] = 0x80
+	return pkt
+}
+
+func TestContinuityTracker(t *testing.T) {
+	tracker := NewContinuityTracker()
+
+	tests := []struct {
+		pkt     *Packet
+		want    ContinuityResult
+		missing int
+	}{
+		{CreateTestPacket(100, 14, true, true), ContinuityOK, 0},
+		{CreateTestPacket(100, 15, false, true), ContinuityOK, 0},
+		{CreateTestPacket(100, 0, false, true), ContinuityOK, 0}, // wrap around
+		{CreateTestPacket(100, 0, false, true), ContinuityDuplicate, 0},
+		{CreateTestPacket(100, 0, false, true), ContinuityIllegalRepeat, 0},
+		{CreateTestPacket(100, 1, false, true), ContinuityOK, 0},
+		{CreateTestPacket(100, 4, false, true), ContinuityLoss, 2},
+		{CreateTestPacket(100, 9, false, false), ContinuityOK, 0}, // no payload, not checked
+		{CreateTestPacket(100, 5, false, true), ContinuityOK, 0},
+		{discontinuousPacket(100, 11), ContinuityDiscontinuity, 0},
+		{CreateTestPacket(100, 12, false, true), ContinuityOK, 0},
+		{CreateTestPacket(NullPacketPid, 3, false, true), ContinuityOK, 0},
+		{CreateTestPacket(NullPacketPid, 9, false, true), ContinuityOK, 0},
+		{CreateTestPacket(100, 12, false, true), ContinuityDuplicate, 0},
+		{CreateTestPacket(100, 0, false, true), ContinuityLoss, 3},
+	}
+
+	for i, test := range tests {
+		event := tracker.Update(test.pkt)
+		if event.Result != test.want {
+			t.Errorf("Packet %d: wanted %s, got %s", i, test.want, event.Result)
+		}
+		if event.Missing != test.missing {
+			t.Errorf("Packet %d: wanted %d missing packets, got %d", i, test.missing, event.Missing)
+		}
+	}
+
+	stats := tracker.Stats(100)
+	want := ContinuityStats{
+		Packets:         12,
+		Losses:          2,
+		LostPackets:     5,
+		Duplicates:      2,
+		IllegalRepeats:  1,
+		Discontinuities: 1,
+	}
+	if stats != want {
+		t.Errorf("Unexpected stats\nwant: %+v\n got: %+v", want, stats)
+	}
+	if stats := tracker.Stats(NullPacketPid); stats.Packets != 0 {
+		t.Errorf("Null packets should not be tracked")
+	}
+
+	tracker.Reset()
+	if stats := tracker.Stats(100); stats.Packets != 0 {
+		t.Errorf("Reset did not clear stats")
+	}
+}
+
+func TestContinuityRepeatWithDifferentPayload(t *testing.T) {
+	tracker := NewContinuityTracker()
+	tracker.Update(CreateTestPacket(100, 5, false, true))
+
+	pkt := CreateTestPacket(100, 5, false, true)
+	payload, _ := Payload(pkt)
+	payload[0] ^= 0xFF
+	event := tracker.Update(pkt)
+	if event.Result != ContinuityLoss || event.Missing != 15 {
+		t.Errorf("wanted ContinuityLoss with 15 missing packets, got %v with %d", event.Result, event.Missing)
+	}
+	if ContinuityDuplicate.String() != "ContinuityDuplicate" {
+		t.Errorf("unexpected name %q", ContinuityDuplicate.String())
+	}
+}
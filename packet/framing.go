@@ -0,0 +1,330 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package packet
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/Comcast/gots/v3"
+)
+
+// Framing is the size in bytes of each packet in a stream including any
+// bytes added around the transport packet.
+type Framing int
+
+const (
+	// Framing188 is the plain 188 byte transport packet.
+	Framing188 Framing = PacketSize
+	// Framing192 is the M2TS (Blu-ray) framing, where each packet is preceded
+	// by a 4 byte header carrying the copy permission indicator and an
+	// arrival time stamp.
+	Framing192 Framing = PacketSize + M2TSHeaderSize
+	// Framing204 is the DVB framing, where each packet is followed by 16
+	// Reed-Solomon parity bytes.
+	Framing204 Framing = PacketSize + ReedSolomonParitySize
+)
+
+const (
+	// M2TSHeaderSize is the size of the header preceding each M2TS packet.
+	M2TSHeaderSize = 4
+	// ReedSolomonParitySize is the number of parity bytes following each
+	// packet in a 204 byte framing.
+	ReedSolomonParitySize = 16
+
+	// framingProbes is the number of consecutive sync bytes that must be
+	// found at the framing stride for DetectFraming to select it.
+	framingProbes = 4
+)
+
+// Framings lists the supported framings in the order they are probed.
+var Framings = []Framing{Framing188, Framing192, Framing204}
+
+// FramingNames maps framings to their common names.
+var FramingNames = map[Framing]string{
+	Framing188: "TS",
+	Framing192: "M2TS",
+	Framing204: "TS+RS",
+}
+
+// String returns the common name of the framing.
+func (f Framing) String() string {
+	if name, ok := FramingNames[f]; ok {
+		return name
+	}
+	return "unknown framing"
+}
+
+// Valid returns whether f is a supported framing.
+func (f Framing) Valid() bool {
+	_, ok := FramingNames[f]
+	return ok
+}
+
+// prefixLen returns the number of bytes preceding the transport packet.
+func (f Framing) prefixLen() int {
+	if f == Framing192 {
+		return M2TSHeaderSize
+	}
+	return 0
+}
+
+// ExtraLen returns the number of bytes in each frame that are not part of
+// the transport packet.
+func (f Framing) ExtraLen() int {
+	return int(f) - PacketSize
+}
+
+// DetectFraming probes the stride between sync bytes at the start of r to
+// determine its packet framing. It does not advance the reader. The reader
+// does not need to be synced; use SyncFraming afterwards to find the start of
+// the first frame.
+func DetectFraming(r Peeker) (Framing, error) {
+	window := int(Framing204) * (framingProbes + 1)
+	b, err := r.Peek(window)
+	if err != nil && len(b) == 0 {
+		if err == io.EOF {
+			return 0, gots.ErrFramingNotDetected
+		}
+		return 0, err
+	}
+
+	for i := 0; i < len(b); i++ {
+		if b[i] != SyncByte {
+			continue
+		}
+		for _, f := range Framings {
+			if probeStride(b[i:], int(f)) {
+				return f, nil
+			}
+		}
+	}
+	return 0, gots.ErrFramingNotDetected
+}
+
+// probeStride returns whether b contains framingProbes sync bytes at the
+// given stride. At least two sync bytes are required when b is short.
+func probeStride(b []byte, stride int) bool {
+	found := 0
+	for off := 0; off < len(b) && found < framingProbes; off += stride {
+		if b[off] != SyncByte {
+			return false
+		}
+		found++
+	}
+	return found > 1
+}
+
+// SyncFraming finds the start of the next frame of the given framing and
+// advances the reader to it. For M2TS the frame starts at the 4 byte header
+// preceding the sync byte. It returns the offset of the frame relative to
+// the original reader position.
+//
+// A position is considered synced when the packet header passes the checks
+// of IsSynced and, when enough data is available, the next frame also starts
+// with a sync byte.
+func SyncFraming(r PeekScanner, f Framing) (off int64, err error) {
+	if !f.Valid() {
+		return 0, gots.ErrUnknownFraming
+	}
+	prefix := f.prefixLen()
+	for {
+		b, err := r.Peek(int(f) + prefix + 1)
+		if len(b) < prefix+4 {
+			if err == nil || err == io.EOF {
+				return off, gots.ErrSyncByteNotFound
+			}
+			return off, err
+		}
+		if isHeader(b[prefix:]) && (len(b) <= int(f)+prefix || b[int(f)+prefix] == SyncByte) {
+			return off, nil
+		}
+		if _, err = r.ReadByte(); err != nil {
+			return off, err
+		}
+		off++
+	}
+}
+
+// M2TSHeader is the 4 byte TP_extra_header preceding each packet of an M2TS
+// stream.
+type M2TSHeader uint32
+
+// NewM2TSHeader creates a M2TS header with the given 2 bit copy permission
+// indicator and 30 bit arrival time stamp.
+func NewM2TSHeader(copyPermission uint8, arrivalTimeStamp uint32) M2TSHeader {
+	return M2TSHeader(uint32(copyPermission&0x3)<<30 | arrivalTimeStamp&0x3fffffff)
+}
+
+// M2TSHeaderFromBytes parses the M2TS header at the start of b.
+func M2TSHeaderFromBytes(b []byte) (M2TSHeader, error) {
+	if len(b) < M2TSHeaderSize {
+		return 0, gots.ErrShortPayload
+	}
+	return M2TSHeader(binary.BigEndian.Uint32(b)), nil
+}
+
+// CopyPermissionIndicator returns the 2 bit copy permission indicator.
+func (h M2TSHeader) CopyPermissionIndicator() uint8 {
+	return uint8(h >> 30)
+}
+
+// ArrivalTimeStamp returns the 30 bit arrival time stamp, in 27MHz ticks.
+func (h M2TSHeader) ArrivalTimeStamp() uint32 {
+	return uint32(h) & 0x3fffffff
+}
+
+// Bytes returns the header as it is written in the stream.
+func (h M2TSHeader) Bytes() []byte {
+	b := make([]byte, M2TSHeaderSize)
+	binary.BigEndian.PutUint32(b, uint32(h))
+	return b
+}
+
+// FrameReader reads packets from a stream with a given framing.
+type FrameReader interface {
+	// Framing returns the framing of the stream.
+	Framing() Framing
+	// ReadPacket reads the next frame into p, stripping any extra bytes.
+	ReadPacket(p *Packet) error
+	// Extra returns the bytes of the last frame read that are not part of
+	// the transport packet: the M2TS header for Framing192 or the
+	// Reed-Solomon parity for Framing204. The slice is only valid until the
+	// next call to ReadPacket.
+	Extra() []byte
+	// M2TSHeader returns the M2TS header of the last frame read. It returns
+	// an error if the stream is not using Framing192.
+	M2TSHeader() (M2TSHeader, error)
+}
+
+type frameReader struct {
+	r       io.Reader
+	framing Framing
+	buf     []byte
+	extra   []byte
+}
+
+// NewFrameReader creates a FrameReader reading frames of the given framing
+// from r. The reader must be synced to the start of a frame, see SyncFraming.
+func NewFrameReader(r io.Reader, f Framing) (FrameReader, error) {
+	if !f.Valid() {
+		return nil, gots.ErrUnknownFraming
+	}
+	return &frameReader{r: r, framing: f, buf: make([]byte, f)}, nil
+}
+
+func (fr *frameReader) Framing() Framing {
+	return fr.framing
+}
+
+func (fr *frameReader) ReadPacket(p *Packet) error {
+	if _, err := io.ReadFull(fr.r, fr.buf); err != nil {
+		return err
+	}
+	prefix := fr.framing.prefixLen()
+	copy(p[:], fr.buf[prefix:prefix+PacketSize])
+	if prefix > 0 {
+		fr.extra = fr.buf[:prefix]
+	} else {
+		fr.extra = fr.buf[PacketSize:]
+	}
+	if p[0] != SyncByte {
+		return gots.ErrBadSyncByte
+	}
+	return nil
+}
+
+func (fr *frameReader) Extra() []byte {
+	return fr.extra
+}
+
+func (fr *frameReader) M2TSHeader() (M2TSHeader, error) {
+	if fr.framing != Framing192 {
+		return 0, gots.ErrUnknownFraming
+	}
+	return M2TSHeaderFromBytes(fr.extra)
+}
+
+// FrameWriter writes packets to a stream with a given framing.
+type FrameWriter interface {
+	PacketWriter
+	// Framing returns the framing of the stream.
+	Framing() Framing
+	// WriteFrame writes p with the given extra bytes, which must be
+	// Framing.ExtraLen bytes long. If extra is nil the extra bytes are
+	// generated as they are for WritePacket.
+	WriteFrame(p *Packet, extra []byte) (n int, err error)
+	// SetM2TSHeader sets the M2TS header used by WritePacket.
+	SetM2TSHeader(h M2TSHeader)
+}
+
+type frameWriter struct {
+	w       io.Writer
+	framing Framing
+	header  M2TSHeader
+	buf     []byte
+}
+
+// NewFrameWriter creates a FrameWriter writing frames of the given framing
+// to w. For Framing192, WritePacket prefixes packets with the header set by
+// SetM2TSHeader. For Framing204, WritePacket appends the Reed-Solomon parity
+// of the packet.
+func NewFrameWriter(w io.Writer, f Framing) (FrameWriter, error) {
+	if !f.Valid() {
+		return nil, gots.ErrUnknownFraming
+	}
+	return &frameWriter{w: w, framing: f, buf: make([]byte, f)}, nil
+}
+
+func (fw *frameWriter) Framing() Framing {
+	return fw.framing
+}
+
+func (fw *frameWriter) SetM2TSHeader(h M2TSHeader) {
+	fw.header = h
+}
+
+func (fw *frameWriter) WritePacket(p *Packet) (int, error) {
+	return fw.WriteFrame(p, nil)
+}
+
+func (fw *frameWriter) WriteFrame(p *Packet, extra []byte) (int, error) {
+	if extra != nil && len(extra) != fw.framing.ExtraLen() {
+		return 0, gots.ErrInvalidFrameExtra
+	}
+	prefix := fw.framing.prefixLen()
+	copy(fw.buf[prefix:], p[:])
+	switch {
+	case extra != nil && prefix > 0:
+		copy(fw.buf, extra)
+	case extra != nil:
+		copy(fw.buf[PacketSize:], extra)
+	case fw.framing == Framing192:
+		binary.BigEndian.PutUint32(fw.buf, uint32(fw.header))
+	case fw.framing == Framing204:
+		ReedSolomonParity(p, fw.buf[PacketSize:])
+	}
+	return fw.w.Write(fw.buf)
+}
@@ -0,0 +1,166 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package packet
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/Comcast/gots/v3"
+)
+
+func framedStream(t *testing.T, f Framing, garbage int, count int) []byte {
+	var buf bytes.Buffer
+	buf.Write(bytes.Repeat([]byte{0xff}, garbage))
+	w, err := NewFrameWriter(&buf, f)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i := 0; i < count; i++ {
+		w.SetM2TSHeader(NewM2TSHeader(0, uint32(i*1000)))
+		pkt := CreateTestPacket(100, uint8(i), i == 0, true)
+		if n, err := w.WritePacket(pkt); err != nil || n != int(f) {
+			t.Fatalf("WritePacket returned %d, %v", n, err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestDetectFraming(t *testing.T) {
+	for _, f := range Framings {
+		for _, garbage := range []int{0, 3, 200} {
+			r := bufio.NewReader(bytes.NewReader(framedStream(t, f, garbage, 10)))
+			detected, err := DetectFraming(r)
+			if err != nil {
+				t.Errorf("%v: unexpected error: %v", f, err)
+			}
+			if detected != f {
+				t.Errorf("Expected framing %v, got %v", f, detected)
+			}
+		}
+	}
+
+	r := bufio.NewReader(bytes.NewReader(bytes.Repeat([]byte{0xff}, 1000)))
+	if _, err := DetectFraming(r); err != gots.ErrFramingNotDetected {
+		t.Errorf("Expected ErrFramingNotDetected, got %v", err)
+	}
+}
+
+func TestSyncFraming(t *testing.T) {
+	for _, f := range Framings {
+		r := bufio.NewReader(bytes.NewReader(framedStream(t, f, 7, 3)))
+		off, err := SyncFraming(r, f)
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", f, err)
+		}
+		if off != 7 {
+			t.Errorf("%v: expected offset 7, got %d", f, off)
+		}
+	}
+}
+
+func TestFrameReader(t *testing.T) {
+	for _, f := range Framings {
+		fr, err := NewFrameReader(bytes.NewReader(framedStream(t, f, 0, 3)), f)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var pkt Packet
+		for i := 0; i < 3; i++ {
+			if err := fr.ReadPacket(&pkt); err != nil {
+				t.Fatalf("%v: unexpected error: %v", f, err)
+			}
+			if pkt.PID() != 100 || pkt.ContinuityCounter() != i {
+				t.Errorf("%v: unexpected packet %X", f, pkt)
+			}
+			if len(fr.Extra()) != f.ExtraLen() {
+				t.Errorf("%v: expected %d extra bytes, got %d", f, f.ExtraLen(), len(fr.Extra()))
+			}
+			h, err := fr.M2TSHeader()
+			if f != Framing192 {
+				if err == nil {
+					t.Errorf("%v: expected an error reading the M2TS header", f)
+				}
+				continue
+			}
+			if err != nil || h.ArrivalTimeStamp() != uint32(i*1000) {
+				t.Errorf("Unexpected M2TS header %X, %v", uint32(h), err)
+			}
+		}
+		if err := fr.ReadPacket(&pkt); err != io.EOF {
+			t.Errorf("%v: expected EOF, got %v", f, err)
+		}
+	}
+}
+
+func TestFrameWriterExtra(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := NewFrameWriter(&buf, Framing192)
+	if _, err := w.WriteFrame(&TestPatPacket, []byte{1, 2}); err != gots.ErrInvalidFrameExtra {
+		t.Errorf("Expected ErrInvalidFrameExtra, got %v", err)
+	}
+	if _, err := w.WriteFrame(&TestPatPacket, []byte{1, 2, 3, 4}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes()[:5], []byte{1, 2, 3, 4, SyncByte}) {
+		t.Errorf("Unexpected frame %X", buf.Bytes())
+	}
+	if _, err := NewFrameWriter(&buf, Framing(190)); err != gots.ErrUnknownFraming {
+		t.Errorf("Expected ErrUnknownFraming, got %v", err)
+	}
+}
+
+func TestM2TSHeader(t *testing.T) {
+	h := NewM2TSHeader(3, 0x12345678)
+	if h.CopyPermissionIndicator() != 3 {
+		t.Errorf("Expected copy permission 3, got %d", h.CopyPermissionIndicator())
+	}
+	if h.ArrivalTimeStamp() != 0x12345678 {
+		t.Errorf("Expected arrival time stamp 0x12345678, got %X", h.ArrivalTimeStamp())
+	}
+	parsed, err := M2TSHeaderFromBytes(h.Bytes())
+	if err != nil || parsed != h {
+		t.Errorf("Expected %X, got %X, %v", uint32(h), uint32(parsed), err)
+	}
+}
+
+func TestReedSolomonParity(t *testing.T) {
+	parity := make([]byte, ReedSolomonParitySize)
+	ReedSolomonParity(&TestPmtPacket, parity)
+	codeword := append(TestPmtPacket[:], parity...)
+
+	// A valid codeword evaluates to zero at each root of the generator.
+	for i := 0; i < ReedSolomonParitySize; i++ {
+		var s byte
+		for _, c := range codeword {
+			s = rsMul(s, rsExp[i]) ^ c
+		}
+		if s != 0 {
+			t.Errorf("Syndrome %d is %X", i, s)
+		}
+	}
+}
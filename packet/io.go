@@ -101,9 +101,14 @@ func IsSynced(r Peeker) (ok bool, err error) {
 	if err != nil {
 		return false, err
 	}
+	return isHeader(b), nil
+}
+
+// isHeader returns whether b starts with a plausible packet header.
+func isHeader(b []byte) bool {
 	// Check that the first byte is the sync byte.
 	if b[0] != SyncByte {
-		return false, nil
+		return false
 	}
 
 	const (
@@ -115,10 +120,10 @@ func IsSynced(r Peeker) (ok bool, err error) {
 	// Check that the AFC is not zero (reserved).
 	afc := header & afcMask
 	if afc == 0x0 {
-		return false, nil
+		return false
 	}
 
 	// Check that the PID is not 0x4-0xf (reserved).
 	pid := (header & pidMask) >> 8
-	return pid < 0x4 || 0xf < pid, nil
+	return pid < 0x4 || 0xf < pid
 }
@@ -0,0 +1,94 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package packet
+
+// The Reed-Solomon RS(204,188,T=8) code used by DVB is a shortened
+// RS(255,239) code over GF(256) with the field generator polynomial
+// x^8 + x^4 + x^3 + x^2 + 1 and the code generator polynomial
+// (x+λ^0)(x+λ^1)...(x+λ^15), where λ = 0x02.
+
+const rsFieldPoly = 0x11d
+
+var (
+	rsExp [512]byte
+	rsLog [256]byte
+	// rsGenerator holds the coefficients of the code generator polynomial
+	// from the highest to the lowest degree, without the leading 1.
+	rsGenerator [ReedSolomonParitySize]byte
+)
+
+func init() {
+	x := 1
+	for i := 0; i < 255; i++ {
+		rsExp[i] = byte(x)
+		rsLog[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= rsFieldPoly
+		}
+	}
+	for i := 255; i < len(rsExp); i++ {
+		rsExp[i] = rsExp[i-255]
+	}
+
+	// Multiply out the generator polynomial, lowest degree first.
+	g := []byte{1}
+	for i := 0; i < ReedSolomonParitySize; i++ {
+		next := make([]byte, len(g)+1)
+		for j, c := range g {
+			next[j+1] ^= c
+			next[j] ^= rsMul(c, rsExp[i])
+		}
+		g = next
+	}
+	for i := range rsGenerator {
+		rsGenerator[i] = g[ReedSolomonParitySize-1-i]
+	}
+}
+
+func rsMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return rsExp[int(rsLog[a])+int(rsLog[b])]
+}
+
+// ReedSolomonParity computes the 16 Reed-Solomon parity bytes of p used in
+// the 204 byte DVB framing and stores them in parity, which must be at
+// least ReedSolomonParitySize bytes long.
+func ReedSolomonParity(p *Packet, parity []byte) {
+	var r [ReedSolomonParitySize]byte
+	for _, b := range p {
+		feedback := b ^ r[0]
+		copy(r[:], r[1:])
+		r[ReedSolomonParitySize-1] = 0
+		if feedback != 0 {
+			for i, g := range rsGenerator {
+				r[i] ^= rsMul(feedback, g)
+			}
+		}
+	}
+	copy(parity, r[:])
+}
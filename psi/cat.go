@@ -0,0 +1,224 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"fmt"
+
+	"github.com/Comcast/gots/v3"
+)
+
+const (
+	// CatPid is the PID of a CAT. By definition this value is one.
+	CatPid = 1
+	// CatTableID is the table_id of a CAT.
+	CatTableID = 0x01
+)
+
+// CASystemNames maps CA_system_ID values allocated individually to the name
+// of the CA system. See ETSI TS 101 162.
+var CASystemNames = map[uint16]string{
+	0x4AEA: "Cryptoguard",
+	0x5601: "Verimatrix",
+}
+
+// CASystemRange is a range of CA_system_ID values allocated to a vendor.
+type CASystemRange struct {
+	First uint16
+	Last  uint16
+	Name  string
+}
+
+// CASystemRanges lists the CA_system_ID ranges allocated to a single vendor.
+// Blocks shared by several vendors, such as 0x4A00 to 0x4AFF, are not ranges
+// and their values are listed individually in CASystemNames.
+var CASystemRanges = []CASystemRange{
+	{0x0100, 0x01FF, "SECA Mediaguard"},
+	{0x0500, 0x05FF, "Viaccess"},
+	{0x0600, 0x06FF, "Irdeto"},
+	{0x0700, 0x07FF, "DigiCipher 2"},
+	{0x0900, 0x09FF, "NDS Videoguard"},
+	{0x0B00, 0x0BFF, "Conax"},
+	{0x0D00, 0x0DFF, "CryptoWorks"},
+	{0x0E00, 0x0EFF, "PowerVu"},
+	{0x1000, 0x10FF, "RAS"},
+	{0x1700, 0x17FF, "BetaCrypt"},
+	{0x1800, 0x18FF, "Nagravision"},
+	{0x2600, 0x26FF, "BISS"},
+}
+
+// CASystemName returns the name of the CA system with the given id. The id
+// is looked up in CASystemNames first and then in CASystemRanges. "unknown"
+// is returned if it is in neither.
+func CASystemName(id uint16) string {
+	if name, ok := CASystemNames[id]; ok {
+		return name
+	}
+	for _, r := range CASystemRanges {
+		if id >= r.First && id <= r.Last {
+			return r.Name
+		}
+	}
+	return "unknown"
+}
+
+// CA is a decoded CA_descriptor. In a CAT the PID carries EMMs, and in a PMT
+// it carries the ECMs of the program or elementary stream.
+type CA struct {
+	SystemID    uint16
+	PID         int
+	PrivateData []byte
+}
+
+// SystemName returns the name of the CA system.
+func (ca CA) SystemName() string {
+	return CASystemName(ca.SystemID)
+}
+
+// String returns a human readable representation of the CA_descriptor.
+func (ca CA) String() string {
+	return fmt.Sprintf("CA system 0x%04X (%s) on PID %d", ca.SystemID, ca.SystemName(), ca.PID)
+}
+
+// DecodeCADescriptor decodes a CA_descriptor.
+func DecodeCADescriptor(d PmtDescriptor) (CA, error) {
+	data := d.Data()
+	if d.Tag() != CONDITIONAL_ACCESS || len(data) < 4 {
+		return CA{}, gots.ErrParsePMTDescriptor
+	}
+	return CA{
+		SystemID:    uint16(data[0])<<8 | uint16(data[1]),
+		PID:         int(data[2]&0x1F)<<8 | int(data[3]),
+		PrivateData: data[4:],
+	}, nil
+}
+
+// DecodeCADescriptors decodes all CA_descriptors of a descriptor loop, such
+// as the descriptors of a CAT, the program descriptors of a PMT or the
+// descriptors of an elementary stream.
+func DecodeCADescriptors(descriptors []PmtDescriptor) ([]CA, error) {
+	var cas []CA
+	for _, d := range descriptors {
+		if d.Tag() != CONDITIONAL_ACCESS {
+			continue
+		}
+		ca, err := DecodeCADescriptor(d)
+		if err != nil {
+			return nil, err
+		}
+		cas = append(cas, ca)
+	}
+	return cas, nil
+}
+
+// ScrambledStreams returns the elementary streams of a PMT that are
+// scrambled according to its CA_descriptors. A CA_descriptor in the program
+// info applies to all elementary streams of the program, otherwise streams
+// are scrambled if they have a CA_descriptor of their own.
+func ScrambledStreams(pmt PMT) []PmtElementaryStream {
+	if hasCADescriptor(pmt.ProgramDescriptors()) {
+		return pmt.ElementaryStreams()
+	}
+	var streams []PmtElementaryStream
+	for _, es := range pmt.ElementaryStreams() {
+		if hasCADescriptor(es.Descriptors()) {
+			streams = append(streams, es)
+		}
+	}
+	return streams
+}
+
+// IsScrambled returns true if any elementary stream of the program
+// described by the PMT is scrambled, see ScrambledStreams.
+func IsScrambled(pmt PMT) bool {
+	return len(ScrambledStreams(pmt)) > 0
+}
+
+func hasCADescriptor(descriptors []PmtDescriptor) bool {
+	for _, d := range descriptors {
+		if d.Tag() == CONDITIONAL_ACCESS {
+			return true
+		}
+	}
+	return false
+}
+
+// CAT is a Conditional Access Table, associating CA systems with the PIDs
+// carrying their EMMs.
+type CAT interface {
+	VersionNumber() uint8
+	CurrentNextIndicator() bool
+	Descriptors() []PmtDescriptor
+	// ConditionalAccess returns the decoded CA_descriptors.
+	ConditionalAccess() ([]CA, error)
+}
+
+type cat struct {
+	versionNumber        uint8
+	currentNextIndicator bool
+	descriptors          []PmtDescriptor
+}
+
+// NewCAT creates a new CAT from the given bytes, which should be concatenated
+// packet payload contents starting with the pointer field. Descriptors of
+// all CAT sections in the bytes are combined. The CRC of each section is
+// validated unless WithoutCRCCheck is given.
+func NewCAT(catBytes []byte, options ...func(*ParseOptions)) (CAT, error) {
+	sections, err := parseSections(catBytes, func(id uint8) bool {
+		return id == CatTableID
+	}, NewParseOptions(options...))
+	if err != nil {
+		return nil, err
+	}
+
+	c := &cat{
+		versionNumber:        sections[0].VersionNumber(),
+		currentNextIndicator: sections[0].CurrentNextIndicator(),
+	}
+	for _, section := range sections {
+		descriptors, err := ParseDescriptors(section.Data())
+		if err != nil {
+			return nil, err
+		}
+		c.descriptors = append(c.descriptors, descriptors...)
+	}
+	return c, nil
+}
+
+func (c *cat) VersionNumber() uint8 {
+	return c.versionNumber
+}
+
+func (c *cat) CurrentNextIndicator() bool {
+	return c.currentNextIndicator
+}
+
+func (c *cat) Descriptors() []PmtDescriptor {
+	return c.descriptors
+}
+
+func (c *cat) ConditionalAccess() ([]CA, error) {
+	return DecodeCADescriptors(c.descriptors)
+}
@@ -0,0 +1,141 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/Comcast/gots/v3"
+)
+
+func TestNewCAT(t *testing.T) {
+	data := []byte{
+		CONDITIONAL_ACCESS, 4, 0x06, 0x04, 0xE1, 0x00,
+		CONDITIONAL_ACCESS, 6, 0x18, 0x30, 0xE1, 0x01, 0xAA, 0xBB,
+	}
+	b := NewPointerField(0)
+	b = append(b, makeSection(CatTableID, 0xFFFF, 3, 0, 1, data[:6])...)
+	b = append(b, makeSection(CatTableID, 0xFFFF, 3, 1, 1, data[6:])...)
+
+	cat, err := NewCAT(b)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cat.VersionNumber() != 3 || !cat.CurrentNextIndicator() || len(cat.Descriptors()) != 2 {
+		t.Errorf("Unexpected CAT %+v", cat)
+	}
+	cas, err := cat.ConditionalAccess()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(cas) != 2 {
+		t.Fatalf("Expected 2 CA descriptors, got %d", len(cas))
+	}
+	if cas[0].SystemID != 0x0604 || cas[0].PID != 0x100 || len(cas[0].PrivateData) != 0 || cas[0].SystemName() != "Irdeto" {
+		t.Errorf("Unexpected CA %+v", cas[0])
+	}
+	if cas[1].SystemID != 0x1830 || cas[1].PID != 0x101 || !bytes.Equal(cas[1].PrivateData, []byte{0xAA, 0xBB}) ||
+		cas[1].SystemName() != "Nagravision" {
+		t.Errorf("Unexpected CA %+v", cas[1])
+	}
+	if got := fmt.Sprint(cat.Descriptors()[0]); got != "Conditional Access (9): CA system 0x0604 (Irdeto) on PID 256" {
+		t.Errorf("Unexpected descriptor string %q", got)
+	}
+
+	if _, err := NewCAT(append(NewPointerField(0), makeSection(CatTableID, 0xFFFF, 0, 0, 0, data[:5])...)); err != gots.ErrParsePMTDescriptor {
+		t.Errorf("Expected ErrParsePMTDescriptor, got %v", err)
+	}
+	if _, err := DecodeCADescriptor(NewPmtDescriptor(CONDITIONAL_ACCESS, []byte{0x06})); err != gots.ErrParsePMTDescriptor {
+		t.Errorf("Expected ErrParsePMTDescriptor, got %v", err)
+	}
+}
+
+func TestCASystemName(t *testing.T) {
+	tests := map[uint16]string{
+		0x0500: "Viaccess",
+		0x09C4: "NDS Videoguard",
+		0x5601: "Verimatrix",
+		0x5602: "unknown",
+		0x4AE0: "unknown",
+		0x4AEA: "Cryptoguard",
+		0x01FF: "SECA Mediaguard",
+		0x0200: "unknown",
+		0xFFFF: "unknown",
+	}
+	for id, want := range tests {
+		if got := CASystemName(id); got != want {
+			t.Errorf("CASystemName(0x%04X) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestPMTConditionalAccess(t *testing.T) {
+	pmt, _ := CreatePMT(1, 0x100, 0)
+	pmt.SetProgramDescriptors([]PmtDescriptor{NewPmtDescriptor(CONDITIONAL_ACCESS, []byte{0x0B, 0x00, 0xE2, 0x00})})
+	pmt.AddElementaryStream(NewPmtElementaryStream(PmtStreamTypeMpeg2VideoH262, 0x100,
+		[]PmtDescriptor{NewPmtDescriptor(CONDITIONAL_ACCESS, []byte{0x0B, 0x00, 0xE2, 0x01})}))
+	if _, err := pmt.UpdateData(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	parsed, err := NewPMT(pmt.Data())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cas, err := DecodeCADescriptors(parsed.ProgramDescriptors())
+	if err != nil || len(cas) != 1 || cas[0].PID != 0x200 || cas[0].SystemName() != "Conax" {
+		t.Errorf("Unexpected program CA %+v, %v", cas, err)
+	}
+	cas, err = DecodeCADescriptors(parsed.ElementaryStreams()[0].Descriptors())
+	if err != nil || len(cas) != 1 || cas[0].PID != 0x201 {
+		t.Errorf("Unexpected elementary stream CA %+v, %v", cas, err)
+	}
+}
+
+func TestScrambledStreams(t *testing.T) {
+	ca := NewPmtDescriptor(CONDITIONAL_ACCESS, []byte{0x0B, 0x00, 0xE2, 0x00})
+	pmt, _ := CreatePMT(1, 0x100, 0)
+	pmt.SetElementaryStreams([]PmtElementaryStream{
+		NewPmtElementaryStream(PmtStreamTypeMpeg2VideoH262, 0x100, []PmtDescriptor{ca}),
+		NewPmtElementaryStream(0x0F, 0x101, nil),
+	})
+	streams := ScrambledStreams(pmt)
+	if len(streams) != 1 || streams[0].ElementaryPid() != 0x100 || !IsScrambled(pmt) {
+		t.Errorf("Expected the video stream to be scrambled, got %v", streams)
+	}
+
+	pmt.SetProgramDescriptors([]PmtDescriptor{ca})
+	if streams := ScrambledStreams(pmt); len(streams) != 2 {
+		t.Errorf("Expected all streams to be scrambled, got %v", streams)
+	}
+
+	clear, _ := CreatePMT(2, 0x200, 0)
+	clear.AddElementaryStream(NewPmtElementaryStream(PmtStreamTypeMpeg2VideoH262, 0x200, nil))
+	if IsScrambled(clear) || len(ScrambledStreams(clear)) != 0 {
+		t.Error("Expected a clear program")
+	}
+}
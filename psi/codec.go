@@ -0,0 +1,179 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+// Codec identifies the coding of an elementary stream. It is derived from
+// the stream type and, for streams of private data, from the descriptors.
+type Codec int
+
+// Codecs of elementary streams
+const (
+	CodecUnknown Codec = iota
+	CodecMPEG2Video
+	CodecAVC
+	CodecHEVC
+	CodecVVC
+	CodecAV1
+	CodecAAC
+	CodecAC3
+	CodecEAC3
+	CodecAC4
+	CodecOpus
+	CodecDTS
+	CodecDTSHD
+	CodecDTSUHD
+	CodecMPEGH3DAudio
+)
+
+// CodecNames maps codecs to human readable names.
+var CodecNames = map[Codec]string{
+	CodecUnknown:      "unknown",
+	CodecMPEG2Video:   "MPEG-2 Video",
+	CodecAVC:          "AVC",
+	CodecHEVC:         "HEVC",
+	CodecVVC:          "VVC",
+	CodecAV1:          "AV1",
+	CodecAAC:          "AAC",
+	CodecAC3:          "AC-3",
+	CodecEAC3:         "E-AC-3",
+	CodecAC4:          "AC-4",
+	CodecOpus:         "Opus",
+	CodecDTS:          "DTS",
+	CodecDTSHD:        "DTS-HD",
+	CodecDTSUHD:       "DTS-UHD",
+	CodecMPEGH3DAudio: "MPEG-H 3D Audio",
+}
+
+func (c Codec) String() string {
+	if name, ok := CodecNames[c]; ok {
+		return name
+	}
+	return CodecNames[CodecUnknown]
+}
+
+// IsVideo returns true for video codecs.
+func (c Codec) IsVideo() bool {
+	switch c {
+	case CodecMPEG2Video, CodecAVC, CodecHEVC, CodecVVC, CodecAV1:
+		return true
+	}
+	return false
+}
+
+// IsAudio returns true for audio codecs.
+func (c Codec) IsAudio() bool {
+	switch c {
+	case CodecAAC, CodecAC3, CodecEAC3, CodecAC4, CodecOpus, CodecDTS,
+		CodecDTSHD, CodecDTSUHD, CodecMPEGH3DAudio:
+		return true
+	}
+	return false
+}
+
+// streamTypeCodecs maps stream types that identify the codec on their own.
+var streamTypeCodecs = map[uint8]Codec{
+	PmtStreamTypeMpeg2VideoH262:  CodecMPEG2Video,
+	PmtStreamTypeMpeg4VideoH264:  CodecAVC,
+	PmtStreamTypeMpeg4VideoH265:  CodecHEVC,
+	PmtStreamTypeVvc:             CodecVVC,
+	PmtStreamTypeAac:             CodecAAC,
+	PmtStreamTypeAacLatm:         CodecAAC,
+	PmtStreamTypeMpeg4Audio:      CodecAAC,
+	PmtStreamTypeAc3:             CodecAC3,
+	PmtStreamTypeEc3:             CodecEAC3,
+	PmtStreamTypeDtsHd:           CodecDTSHD,
+	PmtStreamTypeMpegH3dAudio:    CodecMPEGH3DAudio,
+	PmtStreamTypeMpegH3dAudioAux: CodecMPEGH3DAudio,
+}
+
+// registrationCodecs maps format identifiers of registration descriptors to
+// codecs.
+var registrationCodecs = map[string]Codec{
+	"AV01": CodecAV1,
+	"Opus": CodecOpus,
+	"AC-3": CodecAC3,
+	"EAC3": CodecEAC3,
+	"AC-4": CodecAC4,
+	"HEVC": CodecHEVC,
+	"DTS1": CodecDTS,
+	"DTS2": CodecDTS,
+	"DTS3": CodecDTS,
+}
+
+// LookupCodec returns the codec of an elementary stream with the given
+// stream type and descriptors. Codec specific descriptors take precedence
+// over registration descriptors, which are only consulted if the stream
+// type does not identify the codec. The AV1_video_descriptor tag is in the
+// user private range, so it only identifies AV1 in the presence of an
+// "AV01" registration descriptor.
+func LookupCodec(streamType uint8, descriptors []PmtDescriptor) Codec {
+	if c, ok := streamTypeCodecs[streamType]; ok {
+		return c
+	}
+	registered := CodecUnknown
+	for _, d := range descriptors {
+		switch d.Tag() {
+		case AV1_VIDEO:
+			if hasRegistration(descriptors, "AV01") {
+				return CodecAV1
+			}
+		case DVB_AC3:
+			return CodecAC3
+		case DVB_EAC3:
+			return CodecEAC3
+		case DVB_AAC:
+			return CodecAAC
+		case DTS:
+			return CodecDTS
+		case EXTENSION:
+			switch {
+			case d.IsExtensionDescriptor(AC4_DESC_TAG_EXTENSION):
+				return CodecAC4
+			case d.IsExtensionDescriptor(OPUS_DESC_TAG_EXTENSION):
+				return CodecOpus
+			case d.IsExtensionDescriptor(DTS_HD_DESC_TAG_EXTENSION):
+				return CodecDTSHD
+			case d.IsExtensionDescriptor(DTS_UHD_DESC_TAG_EXTENSION):
+				return CodecDTSUHD
+			}
+		case REGISTRATION:
+			if r, err := d.DecodeRegistration(); err == nil && registered == CodecUnknown {
+				registered = registrationCodecs[r.FormatIdentifier]
+			}
+		}
+	}
+	return registered
+}
+
+// hasRegistration returns true if one of the descriptors is a registration
+// descriptor with the given format identifier.
+func hasRegistration(descriptors []PmtDescriptor, formatIdentifier string) bool {
+	for _, d := range descriptors {
+		if r, err := d.DecodeRegistration(); err == nil && r.FormatIdentifier == formatIdentifier {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"testing"
+)
+
+func TestLookupCodec(t *testing.T) {
+	registration := func(id string) PmtDescriptor {
+		d, err := NewRegistrationDescriptor(RegistrationDescriptor{FormatIdentifier: id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	ac4, err := NewAC4Descriptor(AC4Descriptor{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	av1, err := NewAV1VideoDescriptor(AV1VideoDescriptor{SeqLevelIdx0: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opus, err := NewOpusDescriptor(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name        string
+		streamType  uint8
+		descriptors []PmtDescriptor
+		codec       Codec
+		audio       bool
+		video       bool
+	}{
+		{"H.264", PmtStreamTypeMpeg4VideoH264, nil, CodecAVC, false, true},
+		{"VVC", PmtStreamTypeVvc, nil, CodecVVC, false, true},
+		{"MPEG-H", PmtStreamTypeMpegH3dAudio, nil, CodecMPEGH3DAudio, true, false},
+		{"MPEG-H auxiliary", PmtStreamTypeMpegH3dAudioAux, nil, CodecMPEGH3DAudio, true, false},
+		{"DTS-HD", PmtStreamTypeDtsHd, nil, CodecDTSHD, true, false},
+		{"AV1", PmtStreamTypePrivateContent, []PmtDescriptor{registration("AV01"), av1}, CodecAV1, false, true},
+		{"AV1 registration only", PmtStreamTypePrivateContent, []PmtDescriptor{registration("AV01")}, CodecAV1, false, true},
+		{"AV1 descriptor tag without registration", PmtStreamTypePrivateContent, []PmtDescriptor{av1}, CodecUnknown, false, false},
+		{"AV1 descriptor tag with other registration", PmtStreamTypePrivateContent, []PmtDescriptor{registration("ABCD"), av1}, CodecUnknown, false, false},
+		{"AAC LATM", PmtStreamTypeAacLatm, nil, CodecAAC, true, false},
+		{"Opus", PmtStreamTypePrivateContent, []PmtDescriptor{registration("Opus"), opus}, CodecOpus, true, false},
+		{"AC-4", PmtStreamTypePrivateContent, []PmtDescriptor{ac4}, CodecAC4, true, false},
+		{"DTS", PmtStreamTypePrivateContent, []PmtDescriptor{NewPmtDescriptor(DTS, []byte{0, 0, 0, 0, 0})}, CodecDTS, true, false},
+		{"DTS-UHD", PmtStreamTypePrivateContent, []PmtDescriptor{
+			NewPmtDescriptor(EXTENSION, []byte{DTS_UHD_DESC_TAG_EXTENSION, 0}),
+		}, CodecDTSUHD, true, false},
+		{"private data", PmtStreamTypePrivateContent, []PmtDescriptor{registration("ABCD")}, CodecUnknown, false, false},
+	}
+	for _, test := range tests {
+		es := NewPmtElementaryStream(test.streamType, 0x100, test.descriptors)
+		if es.Codec() != test.codec {
+			t.Errorf("%s: expected codec %v, got %v", test.name, test.codec, es.Codec())
+		}
+		if es.IsAudioContent() != test.audio || es.IsVideoContent() != test.video {
+			t.Errorf("%s: expected audio %v and video %v, got %v and %v", test.name,
+				test.audio, test.video, es.IsAudioContent(), es.IsVideoContent())
+		}
+	}
+}
+
+func TestStreamTypeCodecsAgree(t *testing.T) {
+	for code, codec := range streamTypeCodecs {
+		st := LookupPmtStreamType(code)
+		if st.IsAudioContent() != codec.IsAudio() || st.IsVideoContent() != codec.IsVideo() {
+			t.Errorf("stream type %d: audio %v and video %v, but codec %v has audio %v and video %v", code,
+				st.IsAudioContent(), st.IsVideoContent(), codec, codec.IsAudio(), codec.IsVideo())
+		}
+	}
+}
+
+func TestLookupPmtStreamTypeDescriptions(t *testing.T) {
+	for code, description := range map[uint8]string{
+		PmtStreamTypeMpeg4VideoH265: "HEVC video stream as defined in ITU-T Rec. H.265 | ISO/IEC 23008-2 Video",
+		PmtStreamTypeVvc:            "VVC video stream as defined in ITU-T Rec. H.266 | ISO/IEC 23090-3 Video",
+		PmtStreamTypeMpegH3dAudio:   "ISO/IEC 23008-3 Audio with MHAS transport syntax - main stream",
+	} {
+		if got := LookupPmtStreamType(code).StreamTypeDescription(); got != description {
+			t.Errorf("stream type %d: expected %q, got %q", code, description, got)
+		}
+	}
+}
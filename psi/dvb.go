@@ -0,0 +1,117 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"github.com/Comcast/gots/v3"
+	"github.com/Comcast/gots/v3/internal/fields"
+)
+
+// DVB SI PIDs, see ETSI EN 300 468.
+const (
+	NitPid = 0x10
+	SdtPid = 0x11
+	EitPid = 0x12
+	TdtPid = 0x14
+)
+
+// DVB SI descriptor tags.
+const (
+	NETWORK_NAME                uint8 = 64 // 0100 0000 (0x40)
+	SERVICE_LIST                uint8 = 65 // 0100 0001 (0x41)
+	SATELLITE_DELIVERY_SYSTEM   uint8 = 67 // 0100 0011 (0x43)
+	CABLE_DELIVERY_SYSTEM       uint8 = 68 // 0100 0100 (0x44)
+	SERVICE                     uint8 = 72 // 0100 1000 (0x48)
+	SHORT_EVENT                 uint8 = 77 // 0100 1101 (0x4D)
+	EXTENDED_EVENT              uint8 = 78 // 0100 1110 (0x4E)
+	CONTENT                     uint8 = 84 // 0101 0100 (0x54)
+	PARENTAL_RATING             uint8 = 85 // 0101 0101 (0x55)
+	LOCAL_TIME_OFFSET           uint8 = 88 // 0101 1000 (0x58)
+	TERRESTRIAL_DELIVERY_SYSTEM uint8 = 90 // 0101 1010 (0x5A)
+)
+
+// RunningStatus is the running_status of a service or event.
+type RunningStatus uint8
+
+// Running status values
+const (
+	RunningStatusUndefined RunningStatus = iota
+	RunningStatusNotRunning
+	RunningStatusStartsSoon
+	RunningStatusPausing
+	RunningStatusRunning
+	RunningStatusOffAir
+)
+
+// RunningStatusNames maps running status values to their names.
+var RunningStatusNames = map[RunningStatus]string{
+	RunningStatusUndefined:  "undefined",
+	RunningStatusNotRunning: "not running",
+	RunningStatusStartsSoon: "starts in a few seconds",
+	RunningStatusPausing:    "pausing",
+	RunningStatusRunning:    "running",
+	RunningStatusOffAir:     "service off-air",
+}
+
+func (r RunningStatus) String() string {
+	if name, ok := RunningStatusNames[r]; ok {
+		return name
+	}
+	return "reserved"
+}
+
+// findDescriptor returns the first descriptor with the given tag.
+func findDescriptor(descriptors []PmtDescriptor, tag uint8) (PmtDescriptor, bool) {
+	for _, d := range descriptors {
+		if d.Tag() == tag {
+			return d, true
+		}
+	}
+	return nil, false
+}
+
+// decodeBCD decodes the given number of 4-bit binary coded decimal digits
+// from the start of b.
+func decodeBCD(b []byte, digits int) uint64 {
+	var v uint64
+	for i := 0; i < digits; i++ {
+		nibble := b[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		v = v*10 + uint64(nibble&0x0F)
+	}
+	return v
+}
+
+// lengthPrefixed returns the field of a descriptor following the 8 bit
+// length at the start of b and the remainder of b.
+func lengthPrefixed(b []byte) ([]byte, []byte, error) {
+	field, rest, err := fields.LengthPrefixed(b)
+	if err != nil {
+		return nil, nil, gots.ErrParsePMTDescriptor
+	}
+	return field, rest, nil
+}
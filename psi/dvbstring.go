@@ -0,0 +1,237 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// DVB control codes within strings, see ETSI EN 300 468 Annex A.1.
+const (
+	dvbEmphasisOn  = 0x86
+	dvbEmphasisOff = 0x87
+	dvbCRLF        = 0x8A
+)
+
+// DecodeDVBString decodes a DVB text field, whose first byte may select the
+// character table, to UTF-8 as defined in ETSI EN 300 468 Annex A.
+//
+// The default table (ISO/IEC 6937), ISO/IEC 8859-1, 5, 7, 9 and 15, ISO/IEC
+// 10646 (UCS-2) and UTF-8 are supported. Characters of other tables are
+// replaced with U+FFFD. Diacritical marks of ISO/IEC 6937 are decoded as
+// combining characters following the base letter. Emphasis control codes
+// are dropped and the CR/LF control code is decoded as a newline.
+func DecodeDVBString(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	switch first := b[0]; {
+	case first >= 0x20:
+		return decodeISO6937(b)
+	case first >= 0x01 && first <= 0x0B:
+		// ISO/IEC 8859-5 to 8859-15
+		return decodeISO8859(int(first)+4, b[1:])
+	case first == 0x10:
+		if len(b) < 3 {
+			return ""
+		}
+		return decodeISO8859(int(b[1])<<8|int(b[2]), b[3:])
+	case first == 0x11:
+		return decodeUCS2(b[1:])
+	case first == 0x15:
+		return decodeDVBUTF8(b[1:])
+	case first == 0x1F:
+		// encoding_type_id
+		if len(b) < 2 {
+			return ""
+		}
+		return decodeUnsupported(b[2:])
+	}
+	return decodeUnsupported(b[1:])
+}
+
+// iso6937 maps the upper half of ISO/IEC 6937 to Unicode. Zero values are
+// undefined, and 0xC1 to 0xCF are combining diacritical marks.
+var iso6937 = [96]rune{
+	// 0xA0
+	0x00A0, 0x00A1, 0x00A2, 0x00A3, '$', 0x00A5, '#', 0x00A7,
+	0x00A4, 0x2018, 0x201C, 0x00AB, 0x2190, 0x2191, 0x2192, 0x2193,
+	// 0xB0
+	0x00B0, 0x00B1, 0x00B2, 0x00B3, 0x00D7, 0x00B5, 0x00B6, 0x00B7,
+	0x00F7, 0x2019, 0x201D, 0x00BB, 0x00BC, 0x00BD, 0x00BE, 0x00BF,
+	// 0xC0
+	0, 0x0300, 0x0301, 0x0302, 0x0303, 0x0304, 0x0306, 0x0307,
+	0x0308, 0x0308, 0x030A, 0x0327, 0x0332, 0x030B, 0x0328, 0x030C,
+	// 0xD0
+	0x2015, 0x00B9, 0x00AE, 0x00A9, 0x2122, 0x266A, 0x00AC, 0x00A6,
+	0, 0, 0, 0, 0x215B, 0x215C, 0x215D, 0x215E,
+	// 0xE0
+	0x2126, 0x00C6, 0x0110, 0x00AA, 0x0126, 0, 0x0132, 0x013F,
+	0x0141, 0x00D8, 0x0152, 0x00BA, 0x00DE, 0x0166, 0x014A, 0x0149,
+	// 0xF0
+	0x0138, 0x00E6, 0x0111, 0x00F0, 0x0127, 0x0131, 0x0133, 0x0140,
+	0x0142, 0x00F8, 0x0153, 0x00DF, 0x00FE, 0x0167, 0x014B, 0x00AD,
+}
+
+func decodeISO6937(b []byte) string {
+	var sb strings.Builder
+	var mark rune
+	for _, c := range b {
+		var r rune
+		switch {
+		case c == dvbCRLF:
+			r = '\n'
+		case c >= 0x80 && c < 0xA0:
+			continue
+		case c < 0x80:
+			r = rune(c)
+		default:
+			r = iso6937[c-0xA0]
+			if r == 0 {
+				r = utf8.RuneError
+			}
+		}
+		if c >= 0xC1 && c <= 0xCF {
+			mark = r
+			continue
+		}
+		sb.WriteRune(r)
+		if mark != 0 {
+			sb.WriteRune(mark)
+			mark = 0
+		}
+	}
+	return sb.String()
+}
+
+// iso8859Overrides holds the characters of the supported ISO/IEC 8859 parts
+// that differ from ISO/IEC 8859-1.
+var iso8859Overrides = map[int]map[byte]rune{
+	1: {},
+	9: {
+		0xD0: 0x011E, 0xDD: 0x0130, 0xDE: 0x015E,
+		0xF0: 0x011F, 0xFD: 0x0131, 0xFE: 0x015F,
+	},
+	15: {
+		0xA4: 0x20AC, 0xA6: 0x0160, 0xA8: 0x0161, 0xB4: 0x017D,
+		0xB8: 0x017E, 0xBC: 0x0152, 0xBD: 0x0153, 0xBE: 0x0178,
+	},
+}
+
+func decodeISO8859(part int, b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		switch {
+		case c == dvbCRLF:
+			sb.WriteByte('\n')
+		case c >= 0x80 && c < 0xA0:
+		case c < 0x80:
+			sb.WriteByte(c)
+		default:
+			sb.WriteRune(iso8859Rune(part, c))
+		}
+	}
+	return sb.String()
+}
+
+// iso8859Rune decodes a character in the range 0xA0 to 0xFF.
+func iso8859Rune(part int, c byte) rune {
+	switch part {
+	case 5: // Cyrillic
+		switch {
+		case c == 0xA0 || c == 0xAD:
+			return rune(c)
+		case c == 0xF0:
+			return 0x2116
+		case c == 0xFD:
+			return 0x00A7
+		}
+		return rune(c) - 0xA0 + 0x0400
+	case 7: // Greek
+		switch {
+		case c == 0xA1:
+			return 0x2018
+		case c == 0xA2:
+			return 0x2019
+		case c == 0xA4:
+			return 0x20AC
+		case c == 0xA5:
+			return 0x20AF
+		case c == 0xAA:
+			return 0x037A
+		case c == 0xAF:
+			return 0x2015
+		case c >= 0xB4 && c != 0xB7 && c != 0xBB && c != 0xBD && c != 0xD2 && c != 0xFF:
+			return rune(c) - 0xA0 + 0x0370
+		case c == 0xD2 || c == 0xFF:
+			return utf8.RuneError
+		}
+		return rune(c)
+	}
+	overrides, ok := iso8859Overrides[part]
+	if !ok {
+		return utf8.RuneError
+	}
+	if r, ok := overrides[c]; ok {
+		return r
+	}
+	return rune(c)
+}
+
+func decodeUCS2(b []byte) string {
+	units := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		u := uint16(b[i])<<8 | uint16(b[i+1])
+		switch u {
+		case 0xE000 | dvbEmphasisOn, 0xE000 | dvbEmphasisOff:
+			continue
+		case 0xE000 | dvbCRLF:
+			u = '\n'
+		}
+		units = append(units, u)
+	}
+	return string(utf16.Decode(units))
+}
+
+func decodeDVBUTF8(b []byte) string {
+	s := strings.ToValidUTF8(string(b), string(utf8.RuneError))
+	s = strings.ReplaceAll(s, "\u0086", "")
+	s = strings.ReplaceAll(s, "\u0087", "")
+	return strings.ReplaceAll(s, "\u008A", "\n")
+}
+
+func decodeUnsupported(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		if c < 0x80 {
+			sb.WriteByte(c)
+		} else {
+			sb.WriteRune(utf8.RuneError)
+		}
+	}
+	return sb.String()
+}
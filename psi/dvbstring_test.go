@@ -0,0 +1,55 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import "testing"
+
+func TestDecodeDVBString(t *testing.T) {
+	tests := []struct {
+		in   []byte
+		want string
+	}{
+		{nil, ""},
+		{[]byte("Plain"), "Plain"},
+		// ISO/IEC 6937 diacritic followed by the base letter
+		{[]byte{'C', 'a', 'f', 0xC2, 'e'}, "Café"},
+		{[]byte{'A', 0x86, 'B', 0x87, 0x8A, 'C'}, "AB\nC"},
+		// ISO/IEC 8859-5
+		{[]byte{0x01, 0xB0, 0xB1}, "АБ"},
+		// ISO/IEC 8859-7
+		{[]byte{0x03, 0xC1}, "Α"},
+		// ISO/IEC 8859-15 via the three byte form
+		{[]byte{0x10, 0x00, 0x0F, 0xA4}, "€"},
+		{[]byte{0x10, 0x00, 0x01, 0xE9}, "é"},
+		{[]byte{0x11, 0x00, 'H', 0x04, 0x10}, "HА"},
+		{[]byte{0x15, 'h', 0xC3, 0xA9}, "hé"},
+		{[]byte{0x13, 'x', 0xC1}, "x�"},
+	}
+	for _, test := range tests {
+		if got := DecodeDVBString(test.in); got != test.want {
+			t.Errorf("DecodeDVBString(%X) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"time"
+)
+
+// mjdEpoch is day zero of the Modified Julian Date.
+var mjdEpoch = time.Date(1858, time.November, 17, 0, 0, 0, 0, time.UTC)
+
+// UndefinedDVBDuration is returned by DecodeDVBDuration for a duration with
+// all bits set, which signals that the duration is unknown.
+const UndefinedDVBDuration time.Duration = -1
+
+// DecodeDVBTime decodes a 40 bit DVB UTC time, 16 bits of Modified Julian
+// Date followed by six 4-bit BCD digits of hours, minutes and seconds, as
+// defined in ETSI EN 300 468 Annex C. The zero time is returned if b is
+// shorter than 5 bytes or if the time is undefined (all bits set).
+func DecodeDVBTime(b []byte) time.Time {
+	if len(b) < 5 || (b[0]&b[1]&b[2]&b[3]&b[4]) == 0xFF {
+		return time.Time{}
+	}
+	mjd := int(b[0])<<8 | int(b[1])
+	d := DecodeDVBDuration(b[2:5])
+	if d == UndefinedDVBDuration {
+		return time.Time{}
+	}
+	return mjdEpoch.AddDate(0, 0, mjd).Add(d)
+}
+
+// EncodeDVBTime encodes t, converted to UTC and truncated to seconds, as a
+// 40 bit DVB UTC time.
+func EncodeDVBTime(t time.Time) []byte {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	mjd := int(day.Sub(mjdEpoch).Hours() / 24)
+	return append([]byte{byte(mjd >> 8), byte(mjd)}, EncodeDVBDuration(t.Sub(day))...)
+}
+
+// DecodeDVBDuration decodes a 24 bit DVB duration of six 4-bit BCD digits
+// of hours, minutes and seconds. Zero is returned if b is shorter than 3
+// bytes and UndefinedDVBDuration if all bits are set.
+func DecodeDVBDuration(b []byte) time.Duration {
+	if len(b) < 3 {
+		return 0
+	}
+	if b[0]&b[1]&b[2] == 0xFF {
+		return UndefinedDVBDuration
+	}
+	return time.Duration(decodeBCD(b[0:1], 2))*time.Hour +
+		time.Duration(decodeBCD(b[1:2], 2))*time.Minute +
+		time.Duration(decodeBCD(b[2:3], 2))*time.Second
+}
+
+// EncodeDVBDuration encodes d, truncated to seconds, as a 24 bit DVB
+// duration. Durations of 100 hours or more can not be represented and wrap.
+// UndefinedDVBDuration is encoded with all bits set.
+func EncodeDVBDuration(d time.Duration) []byte {
+	if d == UndefinedDVBDuration {
+		return []byte{0xFF, 0xFF, 0xFF}
+	}
+	s := int(d / time.Second)
+	return []byte{encodeBCD(s / 3600 % 100), encodeBCD(s / 60 % 60), encodeBCD(s % 60)}
+}
+
+// encodeBCD encodes a value below 100 as two 4-bit BCD digits.
+func encodeBCD(v int) byte {
+	return byte(v/10<<4 | v%10)
+}
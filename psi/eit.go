@@ -0,0 +1,444 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"time"
+
+	"github.com/Comcast/gots/v3"
+)
+
+// EIT table ids
+const (
+	EitActualPresentFollowingTableID = 0x4E
+	EitOtherPresentFollowingTableID  = 0x4F
+	EitActualScheduleFirstTableID    = 0x50
+	EitActualScheduleLastTableID     = 0x5F
+	EitOtherScheduleFirstTableID     = 0x60
+	EitOtherScheduleLastTableID      = 0x6F
+)
+
+// Content descriptor level 1 genres.
+const (
+	ContentUndefined   uint8 = 0x0
+	ContentMovie       uint8 = 0x1
+	ContentNews        uint8 = 0x2
+	ContentShow        uint8 = 0x3
+	ContentSports      uint8 = 0x4
+	ContentChildren    uint8 = 0x5
+	ContentMusic       uint8 = 0x6
+	ContentArts        uint8 = 0x7
+	ContentSocial      uint8 = 0x8
+	ContentEducation   uint8 = 0x9
+	ContentLeisure     uint8 = 0xA
+	ContentSpecial     uint8 = 0xB
+	ContentUserDefined uint8 = 0xF
+)
+
+// ContentGenreNames maps level 1 content nibbles to their names.
+var ContentGenreNames = map[uint8]string{
+	ContentUndefined:   "undefined content",
+	ContentMovie:       "movie/drama",
+	ContentNews:        "news/current affairs",
+	ContentShow:        "show/game show",
+	ContentSports:      "sports",
+	ContentChildren:    "children's/youth programmes",
+	ContentMusic:       "music/ballet/dance",
+	ContentArts:        "arts/culture (without music)",
+	ContentSocial:      "social/political issues/economics",
+	ContentEducation:   "education/science/factual topics",
+	ContentLeisure:     "leisure hobbies",
+	ContentSpecial:     "special characteristics",
+	ContentUserDefined: "user defined",
+}
+
+// EIT is a DVB Event Information Table, describing the events of a service.
+// See ETSI EN 300 468 section 5.2.4.
+type EIT interface {
+	TableID() uint8
+	// IsActual returns true if the EIT describes a service of the transport
+	// stream it is carried in.
+	IsActual() bool
+	// IsPresentFollowing returns true for present/following tables and false
+	// for schedule tables.
+	IsPresentFollowing() bool
+	ServiceID() int
+	TransportStreamID() int
+	OriginalNetworkID() int
+	VersionNumber() uint8
+	CurrentNextIndicator() bool
+	SegmentLastSectionNumber() uint8
+	LastTableID() uint8
+	Events() []EITEvent
+}
+
+// EITEvent is an event entry of an EIT.
+type EITEvent struct {
+	EventID int
+	// StartTime is the zero time if undefined, as for example in a NVOD
+	// reference service.
+	StartTime time.Time
+	// Duration is UndefinedDVBDuration if the duration is unknown.
+	Duration      time.Duration
+	RunningStatus RunningStatus
+	// FreeCAMode is true if one or more component streams may be scrambled.
+	FreeCAMode  bool
+	Descriptors []PmtDescriptor
+}
+
+// ShortEvent is a decoded short_event_descriptor.
+type ShortEvent struct {
+	Language  string
+	EventName string
+	Text      string
+}
+
+// ExtendedEvent is a decoded extended_event_descriptor.
+type ExtendedEvent struct {
+	DescriptorNumber     uint8
+	LastDescriptorNumber uint8
+	Language             string
+	Items                []ExtendedEventItem
+	Text                 string
+}
+
+// ExtendedEventItem is an item of an extended_event_descriptor.
+type ExtendedEventItem struct {
+	Description string
+	Item        string
+}
+
+// Content is a classification of a content_descriptor.
+type Content struct {
+	// Level1 is the genre, see ContentGenreNames.
+	Level1   uint8
+	Level2   uint8
+	UserByte uint8
+}
+
+// ParentalRating is a rating of a parental_rating_descriptor.
+type ParentalRating struct {
+	CountryCode string
+	Rating      uint8
+}
+
+// MinimumAge returns the minimum recommended age of the rating, or 0 if the
+// rating is undefined or defined by the broadcaster.
+func (r ParentalRating) MinimumAge() int {
+	if r.Rating >= 0x01 && r.Rating <= 0x0F {
+		return int(r.Rating) + 3
+	}
+	return 0
+}
+
+type eit struct {
+	tableID                  uint8
+	serviceID                int
+	transportStreamID        int
+	originalNetworkID        int
+	versionNumber            uint8
+	currentNextIndicator     bool
+	segmentLastSectionNumber uint8
+	lastTableID              uint8
+	events                   []EITEvent
+}
+
+func isEITTableID(id uint8) bool {
+	return id >= EitActualPresentFollowingTableID && id <= EitOtherScheduleLastTableID
+}
+
+// isEITScheduleTableID returns true for the table_ids of EIT schedules,
+// whose sections are grouped in segments.
+func isEITScheduleTableID(id uint8) bool {
+	return id >= EitActualScheduleFirstTableID && id <= EitOtherScheduleLastTableID
+}
+
+// NewEIT creates a new EIT from the given bytes, which should be concatenated
+// packet payload contents starting with the pointer field. Events of all EIT
+// sections in the bytes are combined, gots.ErrMixedSections is returned if
+// their table_id, service_id, transport_stream_id or original_network_id
+// differ. The CRC of each section is validated unless WithoutCRCCheck is
+// given.
+func NewEIT(eitBytes []byte, options ...func(*ParseOptions)) (EIT, error) {
+	sections, err := parseSections(eitBytes, isEITTableID, NewParseOptions(options...))
+	if err != nil {
+		return nil, err
+	}
+
+	e := &eit{
+		tableID:              sections[0].TableID(),
+		serviceID:            int(sections[0].TableIDExtension()),
+		versionNumber:        sections[0].VersionNumber(),
+		currentNextIndicator: sections[0].CurrentNextIndicator(),
+	}
+	for i, section := range sections {
+		if section.TableID() != sections[0].TableID() || section.TableIDExtension() != sections[0].TableIDExtension() {
+			return nil, gots.ErrMixedSections
+		}
+		if err := e.parseSection(section.Data(), i == 0); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+// parseSection appends the events of a section. The transport_stream_id and
+// original_network_id are taken from the first section and must be the same
+// in the others.
+func (e *eit) parseSection(data []byte, first bool) error {
+	if len(data) < 6 {
+		return gots.ErrShortPayload
+	}
+	transportStreamID := int(data[0])<<8 | int(data[1])
+	originalNetworkID := int(data[2])<<8 | int(data[3])
+	if first {
+		e.transportStreamID = transportStreamID
+		e.originalNetworkID = originalNetworkID
+	} else if transportStreamID != e.transportStreamID || originalNetworkID != e.originalNetworkID {
+		return gots.ErrMixedSections
+	}
+	e.segmentLastSectionNumber = data[4]
+	e.lastTableID = data[5]
+	data = data[6:]
+	for len(data) > 0 {
+		if len(data) < 12 {
+			return gots.ErrShortPayload
+		}
+		length, err := loopLength(data[10:])
+		if err != nil {
+			return err
+		}
+		descriptors, err := ParseDescriptors(data[12 : 12+length])
+		if err != nil {
+			return err
+		}
+		e.events = append(e.events, EITEvent{
+			EventID:       int(data[0])<<8 | int(data[1]),
+			StartTime:     DecodeDVBTime(data[2:7]),
+			Duration:      DecodeDVBDuration(data[7:10]),
+			RunningStatus: RunningStatus(data[10] >> 5),
+			FreeCAMode:    data[10]&0x10 != 0,
+			Descriptors:   descriptors,
+		})
+		data = data[12+length:]
+	}
+	return nil
+}
+
+func (e *eit) TableID() uint8 {
+	return e.tableID
+}
+
+func (e *eit) IsActual() bool {
+	return e.tableID == EitActualPresentFollowingTableID ||
+		(e.tableID >= EitActualScheduleFirstTableID && e.tableID <= EitActualScheduleLastTableID)
+}
+
+func (e *eit) IsPresentFollowing() bool {
+	return e.tableID == EitActualPresentFollowingTableID || e.tableID == EitOtherPresentFollowingTableID
+}
+
+func (e *eit) ServiceID() int {
+	return e.serviceID
+}
+
+func (e *eit) TransportStreamID() int {
+	return e.transportStreamID
+}
+
+func (e *eit) OriginalNetworkID() int {
+	return e.originalNetworkID
+}
+
+func (e *eit) VersionNumber() uint8 {
+	return e.versionNumber
+}
+
+func (e *eit) CurrentNextIndicator() bool {
+	return e.currentNextIndicator
+}
+
+func (e *eit) SegmentLastSectionNumber() uint8 {
+	return e.segmentLastSectionNumber
+}
+
+func (e *eit) LastTableID() uint8 {
+	return e.lastTableID
+}
+
+func (e *eit) Events() []EITEvent {
+	return e.events
+}
+
+// EndTime returns the time the event ends, or the zero time if the start
+// time or the duration is undefined.
+func (ev EITEvent) EndTime() time.Time {
+	if ev.StartTime.IsZero() || ev.Duration == UndefinedDVBDuration {
+		return time.Time{}
+	}
+	return ev.StartTime.Add(ev.Duration)
+}
+
+// ShortEvent returns the first decoded short_event_descriptor of the event.
+// gots.ErrDescriptorNotFound is returned if the event has none.
+func (ev EITEvent) ShortEvent() (ShortEvent, error) {
+	d, ok := findDescriptor(ev.Descriptors, SHORT_EVENT)
+	if !ok {
+		return ShortEvent{}, gots.ErrDescriptorNotFound
+	}
+	return DecodeShortEventDescriptor(d)
+}
+
+// ExtendedEvents returns the decoded extended_event_descriptors of the event
+// in order. gots.ErrDescriptorNotFound is returned if the event has none.
+func (ev EITEvent) ExtendedEvents() ([]ExtendedEvent, error) {
+	var events []ExtendedEvent
+	for _, d := range ev.Descriptors {
+		if d.Tag() != EXTENDED_EVENT {
+			continue
+		}
+		e, err := DecodeExtendedEventDescriptor(d)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	if len(events) == 0 {
+		return nil, gots.ErrDescriptorNotFound
+	}
+	return events, nil
+}
+
+// Content returns the decoded content_descriptor of the event.
+// gots.ErrDescriptorNotFound is returned if the event has none.
+func (ev EITEvent) Content() ([]Content, error) {
+	d, ok := findDescriptor(ev.Descriptors, CONTENT)
+	if !ok {
+		return nil, gots.ErrDescriptorNotFound
+	}
+	return DecodeContentDescriptor(d)
+}
+
+// ParentalRatings returns the decoded parental_rating_descriptor of the
+// event. gots.ErrDescriptorNotFound is returned if the event has none.
+func (ev EITEvent) ParentalRatings() ([]ParentalRating, error) {
+	d, ok := findDescriptor(ev.Descriptors, PARENTAL_RATING)
+	if !ok {
+		return nil, gots.ErrDescriptorNotFound
+	}
+	return DecodeParentalRatingDescriptor(d)
+}
+
+// DecodeShortEventDescriptor decodes a short_event_descriptor.
+func DecodeShortEventDescriptor(d PmtDescriptor) (ShortEvent, error) {
+	data := d.Data()
+	if d.Tag() != SHORT_EVENT || len(data) < 3 {
+		return ShortEvent{}, gots.ErrParsePMTDescriptor
+	}
+	name, rest, err := lengthPrefixed(data[3:])
+	if err != nil {
+		return ShortEvent{}, err
+	}
+	text, _, err := lengthPrefixed(rest)
+	if err != nil {
+		return ShortEvent{}, err
+	}
+	return ShortEvent{
+		Language:  string(data[0:3]),
+		EventName: DecodeDVBString(name),
+		Text:      DecodeDVBString(text),
+	}, nil
+}
+
+// DecodeExtendedEventDescriptor decodes an extended_event_descriptor.
+func DecodeExtendedEventDescriptor(d PmtDescriptor) (ExtendedEvent, error) {
+	data := d.Data()
+	if d.Tag() != EXTENDED_EVENT || len(data) < 4 {
+		return ExtendedEvent{}, gots.ErrParsePMTDescriptor
+	}
+	e := ExtendedEvent{
+		DescriptorNumber:     data[0] >> 4,
+		LastDescriptorNumber: data[0] & 0x0F,
+		Language:             string(data[1:4]),
+	}
+	items, rest, err := lengthPrefixed(data[4:])
+	if err != nil {
+		return ExtendedEvent{}, err
+	}
+	for len(items) > 0 {
+		var description, item []byte
+		description, items, err = lengthPrefixed(items)
+		if err != nil {
+			return ExtendedEvent{}, err
+		}
+		item, items, err = lengthPrefixed(items)
+		if err != nil {
+			return ExtendedEvent{}, err
+		}
+		e.Items = append(e.Items, ExtendedEventItem{
+			Description: DecodeDVBString(description),
+			Item:        DecodeDVBString(item),
+		})
+	}
+	text, _, err := lengthPrefixed(rest)
+	if err != nil {
+		return ExtendedEvent{}, err
+	}
+	e.Text = DecodeDVBString(text)
+	return e, nil
+}
+
+// DecodeContentDescriptor decodes a content_descriptor.
+func DecodeContentDescriptor(d PmtDescriptor) ([]Content, error) {
+	data := d.Data()
+	if d.Tag() != CONTENT || len(data)%2 != 0 {
+		return nil, gots.ErrParsePMTDescriptor
+	}
+	content := make([]Content, 0, len(data)/2)
+	for i := 0; i < len(data); i += 2 {
+		content = append(content, Content{
+			Level1:   data[i] >> 4,
+			Level2:   data[i] & 0x0F,
+			UserByte: data[i+1],
+		})
+	}
+	return content, nil
+}
+
+// DecodeParentalRatingDescriptor decodes a parental_rating_descriptor.
+func DecodeParentalRatingDescriptor(d PmtDescriptor) ([]ParentalRating, error) {
+	data := d.Data()
+	if d.Tag() != PARENTAL_RATING || len(data)%4 != 0 {
+		return nil, gots.ErrParsePMTDescriptor
+	}
+	ratings := make([]ParentalRating, 0, len(data)/4)
+	for i := 0; i < len(data); i += 4 {
+		ratings = append(ratings, ParentalRating{
+			CountryCode: string(data[i : i+3]),
+			Rating:      data[i+3],
+		})
+	}
+	return ratings, nil
+}
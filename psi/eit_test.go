@@ -0,0 +1,185 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/Comcast/gots/v3"
+)
+
+func eitEvent(eventID uint16, start []byte, duration []byte, status byte, descriptors []byte) []byte {
+	b := []byte{byte(eventID >> 8), byte(eventID)}
+	b = append(b, start...)
+	b = append(b, duration...)
+	b = append(b, status<<5|byte(len(descriptors)>>8), byte(len(descriptors)))
+	return append(b, descriptors...)
+}
+
+func TestDVBTime(t *testing.T) {
+	// example from ETSI EN 300 468 Annex C
+	b := []byte{0xC0, 0x79, 0x12, 0x45, 0x00}
+	want := time.Date(1993, time.October, 13, 12, 45, 0, 0, time.UTC)
+	if got := DecodeDVBTime(b); !got.Equal(want) {
+		t.Errorf("DecodeDVBTime(%X) = %v, want %v", b, got, want)
+	}
+	if got := EncodeDVBTime(want); !bytes.Equal(got, b) {
+		t.Errorf("EncodeDVBTime(%v) = %X, want %X", want, got, b)
+	}
+	if got := DecodeDVBTime([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF}); !got.IsZero() {
+		t.Errorf("Expected zero time for undefined time, got %v", got)
+	}
+
+	d := []byte{0x01, 0x45, 0x30}
+	if got := DecodeDVBDuration(d); got != time.Hour+45*time.Minute+30*time.Second {
+		t.Errorf("DecodeDVBDuration(%X) = %v", d, got)
+	}
+	if got := EncodeDVBDuration(time.Hour + 45*time.Minute + 30*time.Second); !bytes.Equal(got, d) {
+		t.Errorf("EncodeDVBDuration = %X, want %X", got, d)
+	}
+	undefined := []byte{0xFF, 0xFF, 0xFF}
+	if got := DecodeDVBDuration(undefined); got != UndefinedDVBDuration {
+		t.Errorf("Expected UndefinedDVBDuration, got %v", got)
+	}
+	if got := EncodeDVBDuration(UndefinedDVBDuration); !bytes.Equal(got, undefined) {
+		t.Errorf("EncodeDVBDuration(UndefinedDVBDuration) = %X", got)
+	}
+	ev := EITEvent{StartTime: DecodeDVBTime(b), Duration: UndefinedDVBDuration}
+	if !ev.EndTime().IsZero() {
+		t.Errorf("Expected zero end time for an undefined duration, got %v", ev.EndTime())
+	}
+}
+
+func TestNewEIT(t *testing.T) {
+	shortEvent := append([]byte{SHORT_EVENT, 14}, "eng\x04News\x05Today"...)
+	extended := []byte{EXTENDED_EVENT, 0, 0x01, 'e', 'n', 'g', 8, 3, 'D', 'i', 'r', 3, 'B', 'o', 'b', 4, 'M', 'o', 'r', 'e'}
+	extended[1] = byte(len(extended) - 2)
+	content := []byte{CONTENT, 4, 0x21, 0x00, 0x43, 0x05}
+	rating := []byte{PARENTAL_RATING, 4, 'G', 'B', 'R', 0x09}
+	descriptors := append(append(append(shortEvent, extended...), content...), rating...)
+
+	start := EncodeDVBTime(time.Date(2024, time.March, 1, 18, 0, 0, 0, time.UTC))
+	data := []byte{0x00, 0x01, 0x20, 0x85, 0x01, EitActualPresentFollowingTableID}
+	data = append(data, eitEvent(0x100, start, []byte{0x00, 0x30, 0x00}, byte(RunningStatusRunning), descriptors)...)
+	data = append(data, eitEvent(0x101, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, []byte{0x01, 0x00, 0x00}, 0, nil)...)
+	b := append(NewPointerField(0), makeSection(EitActualPresentFollowingTableID, 0x1001, 7, 0, 1, data)...)
+
+	eit, err := NewEIT(b)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !eit.IsActual() || !eit.IsPresentFollowing() || eit.ServiceID() != 0x1001 || eit.TransportStreamID() != 1 ||
+		eit.OriginalNetworkID() != 0x2085 || eit.VersionNumber() != 7 || eit.SegmentLastSectionNumber() != 1 ||
+		eit.LastTableID() != EitActualPresentFollowingTableID {
+		t.Errorf("Unexpected EIT header %+v", eit)
+	}
+	events := eit.Events()
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+
+	ev := events[0]
+	if ev.EventID != 0x100 || ev.RunningStatus != RunningStatusRunning || ev.Duration != 30*time.Minute ||
+		!ev.EndTime().Equal(time.Date(2024, time.March, 1, 18, 30, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected event %+v", ev)
+	}
+	se, err := ev.ShortEvent()
+	if err != nil || se != (ShortEvent{Language: "eng", EventName: "News", Text: "Today"}) {
+		t.Errorf("Unexpected short event %+v, %v", se, err)
+	}
+	ee, err := ev.ExtendedEvents()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(ee) != 1 || ee[0].LastDescriptorNumber != 1 || ee[0].Language != "eng" || ee[0].Text != "More" ||
+		len(ee[0].Items) != 1 || ee[0].Items[0] != (ExtendedEventItem{"Dir", "Bob"}) {
+		t.Errorf("Unexpected extended events %+v", ee)
+	}
+	c, err := ev.Content()
+	if err != nil || len(c) != 2 || c[0] != (Content{ContentNews, 1, 0}) || c[1] != (Content{ContentSports, 3, 5}) {
+		t.Errorf("Unexpected content %+v, %v", c, err)
+	}
+	r, err := ev.ParentalRatings()
+	if err != nil || len(r) != 1 || r[0].CountryCode != "GBR" || r[0].MinimumAge() != 12 {
+		t.Errorf("Unexpected parental ratings %+v, %v", r, err)
+	}
+
+	ev = events[1]
+	if !ev.StartTime.IsZero() || ev.Duration != time.Hour {
+		t.Errorf("Unexpected event %+v", ev)
+	}
+	if _, err := ev.ShortEvent(); err != gots.ErrDescriptorNotFound {
+		t.Errorf("Expected ErrDescriptorNotFound, got %v", err)
+	}
+	if _, err := ev.ExtendedEvents(); err != gots.ErrDescriptorNotFound {
+		t.Errorf("Expected ErrDescriptorNotFound, got %v", err)
+	}
+}
+
+func TestNewEITSchedule(t *testing.T) {
+	data := []byte{0x00, 0x01, 0x20, 0x85, 0x00, 0x61}
+	b := append(NewPointerField(0), makeSection(0x61, 1, 0, 0, 0, data)...)
+	eit, err := NewEIT(b)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if eit.IsActual() || eit.IsPresentFollowing() || len(eit.Events()) != 0 {
+		t.Errorf("Unexpected EIT %+v", eit)
+	}
+
+	data = append(data, eitEvent(1, make([]byte, 5), make([]byte, 3), 0, []byte{CONTENT, 3, 0, 0, 0})...)
+	b = append(NewPointerField(0), makeSection(0x61, 1, 0, 0, 0, data)...)
+	eit, err = NewEIT(b)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := eit.Events()[0].Content(); err != gots.ErrParsePMTDescriptor {
+		t.Errorf("Expected ErrParsePMTDescriptor, got %v", err)
+	}
+}
+
+func TestNewEITMixedSections(t *testing.T) {
+	data := func(tsid, onid uint16) []byte {
+		return []byte{byte(tsid >> 8), byte(tsid), byte(onid >> 8), byte(onid), 0x01, EitActualPresentFollowingTableID}
+	}
+	tests := []struct {
+		name   string
+		second []byte
+	}{
+		{"table_id", makeSection(EitOtherPresentFollowingTableID, 1, 0, 1, 1, data(1, 2))},
+		{"service_id", makeSection(EitActualPresentFollowingTableID, 2, 0, 1, 1, data(1, 2))},
+		{"transport_stream_id", makeSection(EitActualPresentFollowingTableID, 1, 0, 1, 1, data(3, 2))},
+		{"original_network_id", makeSection(EitActualPresentFollowingTableID, 1, 0, 1, 1, data(1, 3))},
+	}
+	for _, test := range tests {
+		b := append(NewPointerField(0), makeSection(EitActualPresentFollowingTableID, 1, 0, 0, 1, data(1, 2))...)
+		b = append(b, test.second...)
+		if _, err := NewEIT(b); err != gots.ErrMixedSections {
+			t.Errorf("%s: expected ErrMixedSections, got %v", test.name, err)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"bytes"
+
+	"github.com/Comcast/gots/v3"
+	"github.com/Comcast/gots/v3/packet"
+)
+
+// ElementaryStreamChange holds two versions of an elementary stream on the same PID.
+type ElementaryStreamChange struct {
+	Old PmtElementaryStream
+	New PmtElementaryStream
+}
+
+// PMTDiff describes the differences between the elementary streams of two PMTs.
+type PMTDiff struct {
+	// Added holds streams on PIDs that were not in the old PMT.
+	Added []PmtElementaryStream
+	// Removed holds streams on PIDs that are not in the new PMT.
+	Removed []PmtElementaryStream
+	// StreamTypeChanged holds streams whose stream_type changed.
+	StreamTypeChanged []ElementaryStreamChange
+	// DescriptorsChanged holds streams whose descriptors changed.
+	DescriptorsChanged []ElementaryStreamChange
+}
+
+// Empty returns true if the diff holds no changes.
+func (d PMTDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 &&
+		len(d.StreamTypeChanged) == 0 && len(d.DescriptorsChanged) == 0
+}
+
+// DiffPMT returns the differences between the elementary streams of two PMTs.
+// Either PMT may be nil, in which case it is treated as having no streams.
+func DiffPMT(oldPMT, newPMT PMT) PMTDiff {
+	var diff PMTDiff
+	oldStreams := make(map[int]PmtElementaryStream)
+	if oldPMT != nil {
+		for _, es := range oldPMT.ElementaryStreams() {
+			oldStreams[es.ElementaryPid()] = es
+		}
+	}
+	newPids := make(map[int]bool)
+	if newPMT != nil {
+		for _, es := range newPMT.ElementaryStreams() {
+			newPids[es.ElementaryPid()] = true
+			old, ok := oldStreams[es.ElementaryPid()]
+			switch {
+			case !ok:
+				diff.Added = append(diff.Added, es)
+			case old.StreamType() != es.StreamType():
+				diff.StreamTypeChanged = append(diff.StreamTypeChanged, ElementaryStreamChange{old, es})
+			case !descriptorsEqual(old.Descriptors(), es.Descriptors()):
+				diff.DescriptorsChanged = append(diff.DescriptorsChanged, ElementaryStreamChange{old, es})
+			}
+		}
+	}
+	if oldPMT != nil {
+		for _, es := range oldPMT.ElementaryStreams() {
+			if !newPids[es.ElementaryPid()] {
+				diff.Removed = append(diff.Removed, es)
+			}
+		}
+	}
+	return diff
+}
+
+func descriptorsEqual(a, b []PmtDescriptor) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Tag() != b[i].Tag() || !bytes.Equal(a[i].Data(), b[i].Data()) {
+			return false
+		}
+	}
+	return true
+}
+
+// PATChange is delivered when a new PAT is received.
+type PATChange struct {
+	// Old is the previous PAT, or nil for the first PAT.
+	Old PAT
+	New PAT
+}
+
+// PMTChange is delivered when a new PMT is received or a program is removed
+// from the PAT.
+type PMTChange struct {
+	ProgramNumber int
+	PID           int
+	// Old is the previous PMT, or nil for the first PMT of the program.
+	Old PMT
+	// New is the new PMT, or nil if the program was removed.
+	New  PMT
+	Diff PMTDiff
+}
+
+// Monitor tracks the PAT and PMTs of a transport stream and reports changes.
+// Tables with the current_next_indicator unset and tables that fail to parse
+// are ignored. Monitor is not thread safe.
+type Monitor interface {
+	packet.PacketWriter
+	// PAT returns the current PAT, or nil if none has been received.
+	PAT() PAT
+	// PMT returns the current PMT of a program, or nil if none has been received.
+	PMT(programNumber int) PMT
+	// OnPATChange registers a handler called when the PAT changes.
+	OnPATChange(func(PATChange))
+	// OnPMTChange registers a handler called when a PMT changes.
+	OnPMTChange(func(PMTChange))
+}
+
+type monitoredProgram struct {
+	pid int
+	pmt PMT
+}
+
+// monitoredPid holds the programs whose PMT is carried on a PID. Several
+// programs of a multi program transport stream may share a PMT PID.
+type monitoredPid struct {
+	acc      packet.Accumulator
+	programs map[int]bool
+}
+
+type monitor struct {
+	patAcc     packet.Accumulator
+	pat        PAT
+	programs   map[int]*monitoredProgram // program number to program
+	pmtPids    map[int]*monitoredPid     // PMT PID to programs
+	patHandler []func(PATChange)
+	pmtHandler []func(PMTChange)
+}
+
+// NewMonitor creates a new Monitor.
+func NewMonitor() Monitor {
+	return &monitor{
+		patAcc:   packet.NewAccumulator(PmtAccumulatorDoneFunc),
+		programs: make(map[int]*monitoredProgram),
+		pmtPids:  make(map[int]*monitoredPid),
+	}
+}
+
+func (m *monitor) PAT() PAT {
+	return m.pat
+}
+
+func (m *monitor) PMT(programNumber int) PMT {
+	if p, ok := m.programs[programNumber]; ok {
+		return p.pmt
+	}
+	return nil
+}
+
+func (m *monitor) OnPATChange(h func(PATChange)) {
+	m.patHandler = append(m.patHandler, h)
+}
+
+func (m *monitor) OnPMTChange(h func(PMTChange)) {
+	m.pmtHandler = append(m.pmtHandler, h)
+}
+
+func (m *monitor) WritePacket(pkt *packet.Packet) (int, error) {
+	pid := packet.Pid(pkt)
+	if pid == PatPid {
+		if b, ok := accumulate(m.patAcc, pkt); ok {
+			m.updatePAT(b)
+		}
+		return packet.PacketSize, nil
+	}
+	if mp, ok := m.pmtPids[pid]; ok {
+		if b, ok := accumulate(mp.acc, pkt); ok {
+			m.updatePMT(mp, b)
+		}
+	}
+	return packet.PacketSize, nil
+}
+
+// accumulate writes pkt to acc and returns the accumulated bytes once they
+// are complete.
+func accumulate(acc packet.Accumulator, pkt *packet.Packet) ([]byte, bool) {
+	_, err := acc.WritePacket(pkt)
+	if err != gots.ErrAccumulatorDone {
+		return nil, false
+	}
+	b := acc.Bytes()
+	acc.Reset()
+	return b, true
+}
+
+func (m *monitor) updatePAT(b []byte) {
+	pat, err := NewPAT(b)
+	if err != nil || !pat.CurrentNextIndicator() || TableID(b) != patTableID {
+		return
+	}
+	old := m.pat
+	programs := pat.ProgramMap()
+	if old != nil && old.VersionNumber() == pat.VersionNumber() &&
+		old.TransportStreamID() == pat.TransportStreamID() && programMapsEqual(old.ProgramMap(), programs) {
+		return
+	}
+	m.pat = pat
+
+	for pn, p := range m.programs {
+		if pid, ok := programs[pn]; !ok || pid != p.pid {
+			m.removeProgram(pn, p)
+		}
+	}
+	for pn, pid := range programs {
+		if _, ok := m.programs[pn]; !ok {
+			m.programs[pn] = &monitoredProgram{pid: pid}
+			mp, ok := m.pmtPids[pid]
+			if !ok {
+				mp = &monitoredPid{
+					acc:      packet.NewAccumulator(PmtAccumulatorDoneFunc),
+					programs: make(map[int]bool),
+				}
+				m.pmtPids[pid] = mp
+			}
+			mp.programs[pn] = true
+		}
+	}
+
+	change := PATChange{Old: old, New: pat}
+	for _, h := range m.patHandler {
+		h(change)
+	}
+}
+
+func (m *monitor) removeProgram(pn int, p *monitoredProgram) {
+	delete(m.programs, pn)
+	if mp, ok := m.pmtPids[p.pid]; ok {
+		delete(mp.programs, pn)
+		if len(mp.programs) == 0 {
+			delete(m.pmtPids, p.pid)
+		}
+	}
+	if p.pmt != nil {
+		m.emitPMTChange(PMTChange{
+			ProgramNumber: pn,
+			PID:           p.pid,
+			Old:           p.pmt,
+			Diff:          DiffPMT(p.pmt, nil),
+		})
+	}
+}
+
+// updatePMT dispatches a PMT section to the program named by its
+// program_number, which must be carried on the PID the section came from.
+func (m *monitor) updatePMT(mp *monitoredPid, b []byte) {
+	pmt, err := NewPMT(b)
+	if err != nil || !pmt.CurrentNextIndicator() {
+		return
+	}
+	pn := pmt.ProgramNumber()
+	if !mp.programs[pn] {
+		return
+	}
+	p := m.programs[pn]
+	diff := DiffPMT(p.pmt, pmt)
+	if p.pmt != nil && p.pmt.VersionNumber() == pmt.VersionNumber() && diff.Empty() {
+		return
+	}
+	old := p.pmt
+	p.pmt = pmt
+	m.emitPMTChange(PMTChange{
+		ProgramNumber: pn,
+		PID:           p.pid,
+		Old:           old,
+		New:           pmt,
+		Diff:          diff,
+	})
+}
+
+func (m *monitor) emitPMTChange(change PMTChange) {
+	for _, h := range m.pmtHandler {
+		h(change)
+	}
+}
+
+func programMapsEqual(a, b map[int]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for pn, pid := range a {
+		if other, ok := b[pn]; !ok || other != pid {
+			return false
+		}
+	}
+	return true
+}
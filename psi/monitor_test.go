@@ -0,0 +1,169 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"testing"
+
+	"github.com/Comcast/gots/v3/packet"
+)
+
+func writePackets(t *testing.T, m Monitor, pkts []packet.Packet) {
+	for i := range pkts {
+		if _, err := m.WritePacket(&pkts[i]); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+}
+
+func writePMT(t *testing.T, m Monitor, pid int, pmt PMT) {
+	pkts, err := pmt.Packets(pid, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	writePackets(t, m, pkts)
+}
+
+func TestMonitor(t *testing.T) {
+	m := NewMonitor()
+	var patChanges []PATChange
+	var pmtChanges []PMTChange
+	m.OnPATChange(func(c PATChange) { patChanges = append(patChanges, c) })
+	m.OnPMTChange(func(c PMTChange) { pmtChanges = append(pmtChanges, c) })
+
+	pat, _ := CreatePAT(1, 0, map[int]int{1: 100, 2: 200})
+	writePackets(t, m, pat.Packets(0))
+	writePackets(t, m, pat.Packets(1))
+	if len(patChanges) != 1 || patChanges[0].Old != nil || m.PAT() == nil {
+		t.Fatalf("Expected a single PAT change, got %v", patChanges)
+	}
+
+	video := NewPmtElementaryStream(0x1b, 101, nil)
+	audio := NewPmtElementaryStream(0x0f, 102, []PmtDescriptor{NewPmtDescriptor(LANGUAGE, []byte{'e', 'n', 'g', 0})})
+	pmt, _ := CreatePMT(1, 101, 0)
+	pmt.SetElementaryStreams([]PmtElementaryStream{video, audio})
+	writePMT(t, m, 100, pmt)
+	writePMT(t, m, 100, pmt)
+	if len(pmtChanges) != 1 || pmtChanges[0].Old != nil || len(pmtChanges[0].Diff.Added) != 2 {
+		t.Fatalf("Expected a single PMT change adding 2 streams, got %+v", pmtChanges)
+	}
+	if m.PMT(1) == nil || m.PMT(2) != nil {
+		t.Errorf("Unexpected PMTs %v, %v", m.PMT(1), m.PMT(2))
+	}
+
+	// A PMT for a program that is not in the PAT is ignored
+	other, _ := CreatePMT(3, 301, 0)
+	writePMT(t, m, 300, other)
+
+	// Change the audio language, the video stream type and add a stream
+	pmt, _ = CreatePMT(1, 101, 1)
+	pmt.SetElementaryStreams([]PmtElementaryStream{
+		NewPmtElementaryStream(0x24, 101, nil),
+		NewPmtElementaryStream(0x0f, 102, []PmtDescriptor{NewPmtDescriptor(LANGUAGE, []byte{'s', 'p', 'a', 0})}),
+		NewPmtElementaryStream(0x86, 103, nil),
+	})
+	writePMT(t, m, 100, pmt)
+	if len(pmtChanges) != 2 {
+		t.Fatalf("Expected a second PMT change, got %d changes", len(pmtChanges))
+	}
+	diff := pmtChanges[1].Diff
+	if len(diff.Added) != 1 || diff.Added[0].ElementaryPid() != 103 {
+		t.Errorf("Unexpected added streams %v", diff.Added)
+	}
+	if len(diff.StreamTypeChanged) != 1 || diff.StreamTypeChanged[0].Old.StreamType() != 0x1b || diff.StreamTypeChanged[0].New.StreamType() != 0x24 {
+		t.Errorf("Unexpected stream type changes %v", diff.StreamTypeChanged)
+	}
+	if len(diff.DescriptorsChanged) != 1 || diff.DescriptorsChanged[0].New.ElementaryPid() != 102 {
+		t.Errorf("Unexpected descriptor changes %v", diff.DescriptorsChanged)
+	}
+	if len(diff.Removed) != 0 || pmtChanges[1].Old.VersionNumber() != 0 {
+		t.Errorf("Unexpected PMT change %+v", pmtChanges[1])
+	}
+
+	// Removing program 1 from the PAT removes its PMT
+	pat, _ = CreatePAT(1, 1, map[int]int{2: 200})
+	writePackets(t, m, pat.Packets(2))
+	if len(patChanges) != 2 || patChanges[1].Old.VersionNumber() != 0 {
+		t.Fatalf("Expected a second PAT change, got %v", patChanges)
+	}
+	if len(pmtChanges) != 3 || pmtChanges[2].New != nil || len(pmtChanges[2].Diff.Removed) != 3 {
+		t.Fatalf("Expected the PMT of program 1 to be removed, got %+v", pmtChanges)
+	}
+	if m.PMT(1) != nil {
+		t.Error("PMT of removed program still returned")
+	}
+}
+
+func TestDiffPMTRemoved(t *testing.T) {
+	old, _ := CreatePMT(1, 101, 0)
+	old.SetElementaryStreams([]PmtElementaryStream{
+		NewPmtElementaryStream(0x1b, 101, nil),
+		NewPmtElementaryStream(0x0f, 102, nil),
+	})
+	updated, _ := CreatePMT(1, 101, 1)
+	updated.SetElementaryStreams([]PmtElementaryStream{NewPmtElementaryStream(0x1b, 101, nil)})
+
+	diff := DiffPMT(old, updated)
+	if len(diff.Removed) != 1 || diff.Removed[0].ElementaryPid() != 102 || len(diff.Added) != 0 {
+		t.Errorf("Unexpected diff %+v", diff)
+	}
+	if !DiffPMT(old, old).Empty() {
+		t.Error("Expected an empty diff")
+	}
+}
+
+func TestMonitorSharedPMTPid(t *testing.T) {
+	m := NewMonitor()
+	var pmtChanges []PMTChange
+	m.OnPMTChange(func(c PMTChange) { pmtChanges = append(pmtChanges, c) })
+
+	pat, _ := CreatePAT(1, 0, map[int]int{1: 100, 2: 100})
+	writePackets(t, m, pat.Packets(0))
+
+	pmt1, _ := CreatePMT(1, 101, 0)
+	pmt1.SetElementaryStreams([]PmtElementaryStream{NewPmtElementaryStream(0x1b, 101, nil)})
+	pmt2, _ := CreatePMT(2, 201, 0)
+	pmt2.SetElementaryStreams([]PmtElementaryStream{NewPmtElementaryStream(0x1b, 201, nil)})
+	writePMT(t, m, 100, pmt1)
+	writePMT(t, m, 100, pmt2)
+	if len(pmtChanges) != 2 || m.PMT(1) == nil || m.PMT(2) == nil {
+		t.Fatalf("Expected PMTs for both programs, got %+v", pmtChanges)
+	}
+	if m.PMT(2).PCRPid() != 201 {
+		t.Errorf("Unexpected PMT for program 2: %v", m.PMT(2))
+	}
+
+	// Removing program 1 keeps program 2 on the shared PID
+	pat, _ = CreatePAT(1, 1, map[int]int{2: 100})
+	writePackets(t, m, pat.Packets(1))
+	pmt2, _ = CreatePMT(2, 201, 1)
+	writePMT(t, m, 100, pmt2)
+	if len(pmtChanges) != 4 || pmtChanges[2].ProgramNumber != 1 || pmtChanges[2].New != nil {
+		t.Fatalf("Expected program 1 to be removed, got %+v", pmtChanges)
+	}
+	if pmtChanges[3].ProgramNumber != 2 || pmtChanges[3].New.VersionNumber() != 1 {
+		t.Errorf("Expected a new PMT version for program 2, got %+v", pmtChanges[3])
+	}
+}
@@ -0,0 +1,334 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"encoding/binary"
+
+	"github.com/Comcast/gots/v3"
+)
+
+// NIT table ids
+const (
+	NitActualTableID = 0x40
+	NitOtherTableID  = 0x41
+)
+
+// NIT is a DVB Network Information Table, describing the transport streams
+// of a network. See ETSI EN 300 468 section 5.2.1.
+type NIT interface {
+	TableID() uint8
+	// IsActual returns true if the NIT describes the network the transport
+	// stream it is carried in belongs to.
+	IsActual() bool
+	NetworkID() int
+	VersionNumber() uint8
+	CurrentNextIndicator() bool
+	NetworkDescriptors() []PmtDescriptor
+	// NetworkName returns the decoded network_name_descriptor, or
+	// gots.ErrDescriptorNotFound if there is none.
+	NetworkName() (string, error)
+	TransportStreams() []NITTransportStream
+}
+
+// NITTransportStream is a transport stream entry of a NIT.
+type NITTransportStream struct {
+	TransportStreamID int
+	OriginalNetworkID int
+	Descriptors       []PmtDescriptor
+}
+
+// ServiceListEntry is a service of a service_list_descriptor.
+type ServiceListEntry struct {
+	ServiceID   int
+	ServiceType uint8
+}
+
+// CableDeliverySystem is a decoded cable_delivery_system_descriptor.
+type CableDeliverySystem struct {
+	// Frequency in Hz
+	Frequency  uint64
+	FECOuter   uint8
+	Modulation uint8
+	// SymbolRate in symbols per second
+	SymbolRate uint64
+	FECInner   uint8
+}
+
+// SatelliteDeliverySystem is a decoded satellite_delivery_system_descriptor.
+type SatelliteDeliverySystem struct {
+	// Frequency in Hz
+	Frequency uint64
+	// OrbitalPosition in tenths of a degree
+	OrbitalPosition uint16
+	// East is true for an eastern orbital position.
+	East         bool
+	Polarization uint8
+	// RollOff is only meaningful for DVB-S2 (ModulationSystem 1).
+	RollOff          uint8
+	ModulationSystem uint8
+	ModulationType   uint8
+	// SymbolRate in symbols per second
+	SymbolRate uint64
+	FECInner   uint8
+}
+
+// TerrestrialDeliverySystem is a decoded
+// terrestrial_delivery_system_descriptor.
+type TerrestrialDeliverySystem struct {
+	// CentreFrequency in Hz
+	CentreFrequency uint64
+	Bandwidth       uint8
+	HighPriority    bool
+	// TimeSlicing is true if at least one elementary stream uses time
+	// slicing, which is signalled by a cleared indicator bit.
+	TimeSlicing bool
+	// MPEFEC is true if at least one elementary stream uses MPE-FEC.
+	MPEFEC               bool
+	Constellation        uint8
+	HierarchyInformation uint8
+	CodeRateHP           uint8
+	CodeRateLP           uint8
+	GuardInterval        uint8
+	TransmissionMode     uint8
+	OtherFrequency       bool
+}
+
+type nit struct {
+	tableID              uint8
+	networkID            int
+	versionNumber        uint8
+	currentNextIndicator bool
+	networkDescriptors   []PmtDescriptor
+	transportStreams     []NITTransportStream
+}
+
+// NewNIT creates a new NIT from the given bytes, which should be concatenated
+// packet payload contents starting with the pointer field. Descriptors and
+// transport streams of all NIT sections in the bytes are combined,
+// gots.ErrMixedSections is returned if their table_id or network_id differ.
+// The CRC of each section is validated unless WithoutCRCCheck is given.
+func NewNIT(nitBytes []byte, options ...func(*ParseOptions)) (NIT, error) {
+	sections, err := parseSections(nitBytes, func(id uint8) bool {
+		return id == NitActualTableID || id == NitOtherTableID
+	}, NewParseOptions(options...))
+	if err != nil {
+		return nil, err
+	}
+
+	n := &nit{
+		tableID:              sections[0].TableID(),
+		networkID:            int(sections[0].TableIDExtension()),
+		versionNumber:        sections[0].VersionNumber(),
+		currentNextIndicator: sections[0].CurrentNextIndicator(),
+	}
+	for _, section := range sections {
+		if section.TableID() != sections[0].TableID() || section.TableIDExtension() != sections[0].TableIDExtension() {
+			return nil, gots.ErrMixedSections
+		}
+		if err := n.parseSection(section.Data()); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+// loopLength returns the 12 bit loop length at the start of b, checking
+// that the loop following it is complete.
+func loopLength(b []byte) (int, error) {
+	if len(b) < 2 {
+		return 0, gots.ErrShortPayload
+	}
+	length := int(b[0]&0x0F)<<8 | int(b[1])
+	if len(b) < 2+length {
+		return 0, gots.ErrShortPayload
+	}
+	return length, nil
+}
+
+func (n *nit) parseSection(data []byte) error {
+	length, err := loopLength(data)
+	if err != nil {
+		return err
+	}
+	descriptors, err := ParseDescriptors(data[2 : 2+length])
+	if err != nil {
+		return err
+	}
+	n.networkDescriptors = append(n.networkDescriptors, descriptors...)
+	data = data[2+length:]
+
+	length, err = loopLength(data)
+	if err != nil {
+		return err
+	}
+	data = data[2 : 2+length]
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return gots.ErrShortPayload
+		}
+		length, err := loopLength(data[4:])
+		if err != nil {
+			return err
+		}
+		descriptors, err := ParseDescriptors(data[6 : 6+length])
+		if err != nil {
+			return err
+		}
+		n.transportStreams = append(n.transportStreams, NITTransportStream{
+			TransportStreamID: int(data[0])<<8 | int(data[1]),
+			OriginalNetworkID: int(data[2])<<8 | int(data[3]),
+			Descriptors:       descriptors,
+		})
+		data = data[6+length:]
+	}
+	return nil
+}
+
+func (n *nit) TableID() uint8 {
+	return n.tableID
+}
+
+func (n *nit) IsActual() bool {
+	return n.tableID == NitActualTableID
+}
+
+func (n *nit) NetworkID() int {
+	return n.networkID
+}
+
+func (n *nit) VersionNumber() uint8 {
+	return n.versionNumber
+}
+
+func (n *nit) CurrentNextIndicator() bool {
+	return n.currentNextIndicator
+}
+
+func (n *nit) NetworkDescriptors() []PmtDescriptor {
+	return n.networkDescriptors
+}
+
+func (n *nit) NetworkName() (string, error) {
+	d, ok := findDescriptor(n.networkDescriptors, NETWORK_NAME)
+	if !ok {
+		return "", gots.ErrDescriptorNotFound
+	}
+	return DecodeDVBString(d.Data()), nil
+}
+
+func (n *nit) TransportStreams() []NITTransportStream {
+	return n.transportStreams
+}
+
+// ServiceList returns the decoded service_list_descriptor of the transport
+// stream. gots.ErrDescriptorNotFound is returned if it has none.
+func (ts NITTransportStream) ServiceList() ([]ServiceListEntry, error) {
+	d, ok := findDescriptor(ts.Descriptors, SERVICE_LIST)
+	if !ok {
+		return nil, gots.ErrDescriptorNotFound
+	}
+	return DecodeServiceListDescriptor(d)
+}
+
+// DecodeServiceListDescriptor decodes a service_list_descriptor.
+func DecodeServiceListDescriptor(d PmtDescriptor) ([]ServiceListEntry, error) {
+	data := d.Data()
+	if d.Tag() != SERVICE_LIST || len(data)%3 != 0 {
+		return nil, gots.ErrParsePMTDescriptor
+	}
+	services := make([]ServiceListEntry, 0, len(data)/3)
+	for i := 0; i < len(data); i += 3 {
+		services = append(services, ServiceListEntry{
+			ServiceID:   int(data[i])<<8 | int(data[i+1]),
+			ServiceType: data[i+2],
+		})
+	}
+	return services, nil
+}
+
+// DecodeCableDeliverySystemDescriptor decodes a
+// cable_delivery_system_descriptor.
+func DecodeCableDeliverySystemDescriptor(d PmtDescriptor) (CableDeliverySystem, error) {
+	data := d.Data()
+	if d.Tag() != CABLE_DELIVERY_SYSTEM || len(data) < 11 {
+		return CableDeliverySystem{}, gots.ErrParsePMTDescriptor
+	}
+	return CableDeliverySystem{
+		// XXXX.XXXX MHz
+		Frequency:  decodeBCD(data[0:4], 8) * 100,
+		FECOuter:   data[5] & 0x0F,
+		Modulation: data[6],
+		// XXX.XXXX Msymbol/s
+		SymbolRate: decodeBCD(data[7:11], 7) * 100,
+		FECInner:   data[10] & 0x0F,
+	}, nil
+}
+
+// DecodeSatelliteDeliverySystemDescriptor decodes a
+// satellite_delivery_system_descriptor.
+func DecodeSatelliteDeliverySystemDescriptor(d PmtDescriptor) (SatelliteDeliverySystem, error) {
+	data := d.Data()
+	if d.Tag() != SATELLITE_DELIVERY_SYSTEM || len(data) < 11 {
+		return SatelliteDeliverySystem{}, gots.ErrParsePMTDescriptor
+	}
+	return SatelliteDeliverySystem{
+		// XXX.XXXXX GHz
+		Frequency:        decodeBCD(data[0:4], 8) * 10000,
+		OrbitalPosition:  uint16(decodeBCD(data[4:6], 4)),
+		East:             data[6]&0x80 != 0,
+		Polarization:     data[6] >> 5 & 0x03,
+		RollOff:          data[6] >> 3 & 0x03,
+		ModulationSystem: data[6] >> 2 & 0x01,
+		ModulationType:   data[6] & 0x03,
+		// XXX.XXXX Msymbol/s
+		SymbolRate: decodeBCD(data[7:11], 7) * 100,
+		FECInner:   data[10] & 0x0F,
+	}, nil
+}
+
+// DecodeTerrestrialDeliverySystemDescriptor decodes a
+// terrestrial_delivery_system_descriptor.
+func DecodeTerrestrialDeliverySystemDescriptor(d PmtDescriptor) (TerrestrialDeliverySystem, error) {
+	data := d.Data()
+	if d.Tag() != TERRESTRIAL_DELIVERY_SYSTEM || len(data) < 7 {
+		return TerrestrialDeliverySystem{}, gots.ErrParsePMTDescriptor
+	}
+	return TerrestrialDeliverySystem{
+		// units of 10 Hz
+		CentreFrequency:      uint64(binary.BigEndian.Uint32(data[0:4])) * 10,
+		Bandwidth:            data[4] >> 5,
+		HighPriority:         data[4]&0x10 != 0,
+		TimeSlicing:          data[4]&0x08 == 0,
+		MPEFEC:               data[4]&0x04 == 0,
+		Constellation:        data[5] >> 6,
+		HierarchyInformation: data[5] >> 3 & 0x07,
+		CodeRateHP:           data[5] & 0x07,
+		CodeRateLP:           data[6] >> 5,
+		GuardInterval:        data[6] >> 3 & 0x03,
+		TransmissionMode:     data[6] >> 1 & 0x03,
+		OtherFrequency:       data[6]&0x01 != 0,
+	}, nil
+}
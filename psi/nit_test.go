@@ -0,0 +1,173 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"testing"
+
+	"github.com/Comcast/gots/v3"
+)
+
+func withLoopLength(b []byte) []byte {
+	return append([]byte{0xF0 | byte(len(b)>>8), byte(len(b))}, b...)
+}
+
+func nitTransportStream(tsid, onid uint16, descriptors []byte) []byte {
+	b := []byte{byte(tsid >> 8), byte(tsid), byte(onid >> 8), byte(onid)}
+	return append(b, withLoopLength(descriptors)...)
+}
+
+func TestNewNIT(t *testing.T) {
+	networkName := append([]byte{NETWORK_NAME, 7}, "Network"...)
+	cable := []byte{CABLE_DELIVERY_SYSTEM, 11,
+		0x03, 0x46, 0x00, 0x00, // 346.0000 MHz
+		0xFF, 0xF2, // FEC outer
+		0x03,                   // 64-QAM
+		0x00, 0x69, 0x00, 0x05, // 6.9000 Msymbol/s, FEC inner 5
+	}
+	serviceList := []byte{SERVICE_LIST, 6, 0x10, 0x01, 0x01, 0x10, 0x02, 0x02}
+	satellite := []byte{SATELLITE_DELIVERY_SYSTEM, 11,
+		0x01, 0x17, 0x77, 0x50, // 11.77750 GHz
+		0x01, 0x92, // 19.2 degrees
+		0xA5,                   // east, vertical, roll off 0, DVB-S2, QPSK
+		0x02, 0x75, 0x00, 0x03, // 27.5000 Msymbol/s, FEC inner 3
+	}
+	terrestrial := []byte{TERRESTRIAL_DELIVERY_SYSTEM, 11,
+		0x04, 0xC4, 0xB4, 0x00, // 800 MHz
+		0x1F, // 8 MHz, high priority, no time slicing or MPE-FEC
+		0x82, // 64-QAM, code rate HP 3/4
+		0x45, // code rate LP 2/3, guard 1/32, transmission mode 8k, other frequency
+		0xFF, 0xFF, 0xFF, 0xFF,
+	}
+
+	data := withLoopLength(networkName)
+	loop := nitTransportStream(1, 0x2085, append(cable, serviceList...))
+	loop = append(loop, nitTransportStream(2, 0x2085, satellite)...)
+	loop = append(loop, nitTransportStream(3, 0x2085, terrestrial)...)
+	data = append(data, withLoopLength(loop)...)
+	b := append(NewPointerField(0), makeSection(NitActualTableID, 0x3001, 2, 0, 0, data)...)
+
+	nit, err := NewNIT(b)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !nit.IsActual() || nit.NetworkID() != 0x3001 || nit.VersionNumber() != 2 || !nit.CurrentNextIndicator() {
+		t.Errorf("Unexpected NIT header %+v", nit)
+	}
+	if name, err := nit.NetworkName(); err != nil || name != "Network" {
+		t.Errorf("Unexpected network name %q, %v", name, err)
+	}
+	tss := nit.TransportStreams()
+	if len(tss) != 3 {
+		t.Fatalf("Expected 3 transport streams, got %d", len(tss))
+	}
+	if tss[0].TransportStreamID != 1 || tss[0].OriginalNetworkID != 0x2085 || len(tss[0].Descriptors) != 2 {
+		t.Errorf("Unexpected transport stream %+v", tss[0])
+	}
+
+	services, err := tss[0].ServiceList()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(services) != 2 || services[0] != (ServiceListEntry{0x1001, 1}) || services[1] != (ServiceListEntry{0x1002, 2}) {
+		t.Errorf("Unexpected service list %+v", services)
+	}
+	if _, err := tss[1].ServiceList(); err != gots.ErrDescriptorNotFound {
+		t.Errorf("Expected ErrDescriptorNotFound, got %v", err)
+	}
+
+	c, err := DecodeCableDeliverySystemDescriptor(tss[0].Descriptors[0])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if c != (CableDeliverySystem{Frequency: 346000000, FECOuter: 2, Modulation: 3, SymbolRate: 6900000, FECInner: 5}) {
+		t.Errorf("Unexpected cable delivery system %+v", c)
+	}
+
+	s, err := DecodeSatelliteDeliverySystemDescriptor(tss[1].Descriptors[0])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if s != (SatelliteDeliverySystem{Frequency: 11777500000, OrbitalPosition: 192, East: true, Polarization: 1,
+		ModulationSystem: 1, ModulationType: 1, SymbolRate: 27500000, FECInner: 3}) {
+		t.Errorf("Unexpected satellite delivery system %+v", s)
+	}
+
+	ter, err := DecodeTerrestrialDeliverySystemDescriptor(tss[2].Descriptors[0])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ter != (TerrestrialDeliverySystem{CentreFrequency: 800000000, HighPriority: true, Constellation: 2, CodeRateHP: 2,
+		CodeRateLP: 2, GuardInterval: 0, TransmissionMode: 2, OtherFrequency: true}) {
+		t.Errorf("Unexpected terrestrial delivery system %+v", ter)
+	}
+
+	if _, err := DecodeCableDeliverySystemDescriptor(tss[1].Descriptors[0]); err != gots.ErrParsePMTDescriptor {
+		t.Errorf("Expected ErrParsePMTDescriptor, got %v", err)
+	}
+}
+
+func TestNewNITMultipleSections(t *testing.T) {
+	first := append(withLoopLength(nil), withLoopLength(nitTransportStream(1, 1, nil))...)
+	second := append(withLoopLength(nil), withLoopLength(nitTransportStream(2, 1, nil))...)
+	b := NewPointerField(0)
+	b = append(b, makeSection(NitOtherTableID, 9, 0, 0, 1, first)...)
+	b = append(b, makeSection(NitOtherTableID, 9, 0, 1, 1, second)...)
+
+	nit, err := NewNIT(b)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if nit.IsActual() {
+		t.Error("Expected NIT other")
+	}
+	if _, err := nit.NetworkName(); err != gots.ErrDescriptorNotFound {
+		t.Errorf("Expected ErrDescriptorNotFound, got %v", err)
+	}
+	tss := nit.TransportStreams()
+	if len(tss) != 2 || tss[0].TransportStreamID != 1 || tss[1].TransportStreamID != 2 {
+		t.Errorf("Unexpected transport streams %+v", tss)
+	}
+
+	truncated := append(withLoopLength(nil), 0xF0, 0x06, 0, 1, 0, 1, 0xF0)
+	b = append(NewPointerField(0), makeSection(NitActualTableID, 9, 0, 0, 0, truncated)...)
+	if _, err := NewNIT(b); err != gots.ErrShortPayload {
+		t.Errorf("Expected ErrShortPayload, got %v", err)
+	}
+}
+
+func TestNewNITMixedSections(t *testing.T) {
+	data := append(withLoopLength(nil), withLoopLength(nitTransportStream(1, 1, nil))...)
+	for _, second := range [][]byte{
+		makeSection(NitOtherTableID, 9, 0, 1, 1, data),
+		makeSection(NitActualTableID, 10, 0, 1, 1, data),
+	} {
+		b := append(NewPointerField(0), makeSection(NitActualTableID, 9, 0, 0, 1, data)...)
+		b = append(b, second...)
+		if _, err := NewNIT(b); err != gots.ErrMixedSections {
+			t.Errorf("Expected ErrMixedSections, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"github.com/Comcast/gots/v3/packet"
+)
+
+// packetPayloadSize is the payload size of a packet without adaptation field.
+const packetPayloadSize = packet.PacketSize - 4
+
+// SectionPacketizer splits PSI sections into packets on a PID. The
+// continuity counter continues across calls, so that successive versions or
+// repetitions of a table can be packetized by the same SectionPacketizer.
+type SectionPacketizer interface {
+	// Packetize returns the packets carrying the sections, which must each
+	// start at the table_id and be complete. The first packet of each call
+	// starts with a new section.
+	Packetize(sections ...[]byte) []packet.Packet
+	// ContinuityCounter returns the continuity counter of the next packet.
+	ContinuityCounter() uint8
+}
+
+// PacketizerOptions controls how sections are packetized.
+type PacketizerOptions struct {
+	// PackSections allows a section to start in the packet carrying the
+	// end of the previous section, instead of stuffing the rest of that
+	// packet with 0xFF.
+	PackSections bool
+}
+
+// WithPackedSections is an option function for packetizing multiple sections
+// per packet.
+func WithPackedSections(o *PacketizerOptions) {
+	o.PackSections = true
+}
+
+type sectionPacketizer struct {
+	pid     int
+	cc      uint8
+	options PacketizerOptions
+}
+
+// NewSectionPacketizer creates a new SectionPacketizer for the given PID
+// with continuity counters starting at cc.
+func NewSectionPacketizer(pid int, cc uint8, options ...func(*PacketizerOptions)) SectionPacketizer {
+	p := &sectionPacketizer{pid: pid, cc: cc & 0x0f}
+	for _, option := range options {
+		option(&p.options)
+	}
+	return p
+}
+
+func (p *sectionPacketizer) ContinuityCounter() uint8 {
+	return p.cc
+}
+
+func (p *sectionPacketizer) Packetize(sections ...[]byte) []packet.Packet {
+	if len(sections) == 0 {
+		return nil
+	}
+	if !p.options.PackSections {
+		var pkts []packet.Packet
+		for _, s := range sections {
+			pkts = append(pkts, p.packetize(s, []int{0})...)
+		}
+		return pkts
+	}
+	var data []byte
+	starts := make([]int, 0, len(sections))
+	for _, s := range sections {
+		starts = append(starts, len(data))
+		data = append(data, s...)
+	}
+	return p.packetize(data, starts)
+}
+
+// packetize splits data into packets. starts are the ascending offsets of
+// the sections in data, where the payload unit start indicator and pointer
+// field are set.
+func (p *sectionPacketizer) packetize(data []byte, starts []int) []packet.Packet {
+	var pkts []packet.Packet
+	for pos := 0; pos < len(data) || len(pkts) == 0; {
+		var pkt packet.Packet
+		pkt[0] = packet.SyncByte
+		pkt[1] = byte(p.pid>>8) & 0x1f
+		pkt[2] = byte(p.pid)
+		pkt[3] = 0x10 | p.cc // payload only
+		p.cc = (p.cc + 1) & 0x0f
+		for i := 4; i < packet.PacketSize; i++ {
+			pkt[i] = 0xFF
+		}
+
+		payload := pkt[4:]
+		for len(starts) > 0 && starts[0] < pos {
+			starts = starts[1:]
+		}
+		switch {
+		case len(starts) > 0 && starts[0]-pos < packetPayloadSize-1:
+			// the pointer field points at the first section starting in
+			// the packet
+			pkt[1] |= 0x40
+			payload[0] = byte(starts[0] - pos)
+			payload = payload[1:]
+		case len(starts) > 0 && starts[0]-pos == packetPayloadSize-1:
+			// a section can not start in the last byte without a pointer
+			// field, so the packet ends with stuffing instead
+			payload = payload[:packetPayloadSize-1]
+		}
+		pos += copy(payload, data[pos:])
+		pkts = append(pkts, pkt)
+	}
+	return pkts
+}
@@ -0,0 +1,119 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Comcast/gots/v3/packet"
+)
+
+// depacketize returns the concatenated payloads of the packets.
+func depacketize(t *testing.T, pkts []packet.Packet) []byte {
+	var b []byte
+	for i := range pkts {
+		pay, err := packet.Payload(&pkts[i])
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		b = append(b, pay...)
+	}
+	return b
+}
+
+func TestSectionPacketizer(t *testing.T) {
+	long := makeSection(0x80, 1, 0, 0, 1, bytes.Repeat([]byte{0xAB}, 300))
+	short := makeSection(0x80, 1, 0, 1, 1, []byte{1, 2, 3})
+
+	p := NewSectionPacketizer(0x100, 14)
+	pkts := p.Packetize(long, short)
+	if len(pkts) != 3 {
+		t.Fatalf("Expected 3 packets, got %d", len(pkts))
+	}
+	pusi := []bool{true, false, true}
+	for i := range pkts {
+		if packet.Pid(&pkts[i]) != 0x100 || packet.PayloadUnitStartIndicator(&pkts[i]) != pusi[i] ||
+			packet.ContinuityCounter(&pkts[i]) != uint8(14+i)&0x0f {
+			t.Errorf("Unexpected packet %d header %X", i, pkts[i][:4])
+		}
+	}
+	if p.ContinuityCounter() != 1 {
+		t.Errorf("Expected continuity counter 1, got %d", p.ContinuityCounter())
+	}
+	pay := depacketize(t, pkts[:2])
+	if pay[0] != 0 || !bytes.Equal(pay[1:1+len(long)], long) || pay[1+len(long)] != 0xFF {
+		t.Errorf("Unexpected payload %X", pay)
+	}
+	if sections := SplitSections(depacketize(t, pkts[2:])); len(sections) != 1 || !bytes.Equal(sections[0], short) {
+		t.Errorf("Unexpected sections %X", sections)
+	}
+}
+
+func TestSectionPacketizerNoSections(t *testing.T) {
+	if pkts := NewSectionPacketizer(0x100, 0).Packetize(); pkts != nil {
+		t.Errorf("Expected no packets, got %d", len(pkts))
+	}
+	if pkts := NewSectionPacketizer(0x100, 0, WithPackedSections).Packetize(); pkts != nil {
+		t.Errorf("Expected no packets, got %d", len(pkts))
+	}
+}
+
+func TestSectionPacketizerPacked(t *testing.T) {
+	first := makeSection(0x80, 1, 0, 0, 2, bytes.Repeat([]byte{0xAB}, 200))
+	second := makeSection(0x80, 1, 0, 1, 2, []byte{1, 2, 3})
+	third := makeSection(0x80, 1, 0, 2, 2, []byte{4, 5, 6})
+
+	pkts := NewSectionPacketizer(0x100, 0, WithPackedSections).Packetize(first, second, third)
+	if len(pkts) != 2 {
+		t.Fatalf("Expected 2 packets, got %d", len(pkts))
+	}
+	pay, _ := packet.Payload(&pkts[1])
+	if !packet.PayloadUnitStartIndicator(&pkts[1]) || int(pay[0]) != len(first)-183 {
+		t.Errorf("Unexpected second packet %X", pkts[1])
+	}
+	sections := SplitSections(depacketize(t, pkts[:1]))
+	if len(sections) != 0 {
+		t.Errorf("Expected no complete sections in the first packet, got %d", len(sections))
+	}
+	all := append(depacketize(t, pkts[:1]), pay[1:]...)
+	sections = SplitSections(all)
+	if len(sections) != 3 || !bytes.Equal(sections[0], first) || !bytes.Equal(sections[1], second) ||
+		!bytes.Equal(sections[2], third) {
+		t.Errorf("Unexpected sections %X", sections)
+	}
+
+	// a section can not start in the last byte of a packet without a
+	// pointer field
+	first = makeSection(0x80, 1, 0, 0, 1, bytes.Repeat([]byte{0xAB}, 183+183-12))
+	pkts = NewSectionPacketizer(0x100, 0, WithPackedSections).Packetize(first, second)
+	if len(pkts) != 3 || packet.PayloadUnitStartIndicator(&pkts[1]) || pkts[1][packet.PacketSize-1] != 0xFF {
+		t.Fatalf("Unexpected packets %X", pkts)
+	}
+	pay, _ = packet.Payload(&pkts[2])
+	if !packet.PayloadUnitStartIndicator(&pkts[2]) || pay[0] != 0 || !bytes.Equal(pay[1:1+len(second)], second) {
+		t.Errorf("Unexpected third packet %X", pkts[2])
+	}
+}
@@ -27,6 +27,7 @@ package psi
 import (
 	"errors"
 	"io"
+	"sort"
 
 	"github.com/Comcast/gots/v3"
 	"github.com/Comcast/gots/v3/packet"
@@ -37,24 +38,46 @@ const (
 	PatPid = 0
 )
 
-// PAT interface represents operations on a Program Association Table. Currently only single program transport streams (SPTS)are supported
+const (
+	patTableID = 0x00
+	// patStaticLen is the length of the fixed fields following the section
+	// length, including the CRC.
+	patStaticLen = 9
+	// maxSectionLength is the maximum section_length of a PAT or PMT.
+	maxSectionLength = 1021
+)
+
+// PAT interface represents operations on a Program Association Table.
 type PAT interface {
 	NumPrograms() int
 	ProgramMap() map[int]int
 	SPTSpmtPID() (int, error)
+	// TransportStreamID returns the transport_stream_id of the PAT.
+	TransportStreamID() int
+	// VersionNumber returns the version_number of the PAT.
+	VersionNumber() uint8
+	// CurrentNextIndicator returns true if the PAT is currently applicable.
+	CurrentNextIndicator() bool
+	// NetworkPID returns the PID of the NIT listed as program number 0, or
+	// ErrNoNetworkPID if the PAT does not list one.
+	NetworkPID() (int, error)
+	// Data returns the bytes of the PAT, including the pointer field.
+	Data() []byte
+	// Packets returns the PAT packetized on PID 0 with continuity counters
+	// starting at cc.
+	Packets(cc uint8) []packet.Packet
 }
 
 // The Program Association Table (PAT) lists the programs available in transport
-// stream.  Currently it is assumed that it will always be processing a Single
-// Program Transport Stream (SPTS), and thus will only ever receive a PAT that
-// contains a single programNumber to PID mapping.
+// stream. The bytes include the pointer field.
 type pat []byte
 
 // NewPAT constructs a new PAT from the provided bytes.
 // patBytes should be concatenated packet payload contents.
 // If a 188 byte slice is passed in, NewPAT tries to help and
 // treats it as TS packet and builds a PAT from the packet payload.
-func NewPAT(patBytes []byte) (PAT, error) {
+// The CRC of the PAT is validated unless WithoutCRCCheck is given.
+func NewPAT(patBytes []byte, options ...func(*ParseOptions)) (PAT, error) {
 	if len(patBytes) < 13 {
 		return nil, gots.ErrInvalidPATLength
 	}
@@ -69,9 +92,72 @@ func NewPAT(patBytes []byte) (PAT, error) {
 		}
 	}
 
+	offset := int(1 + PointerField(patBytes))
+	if offset+3 > len(patBytes) {
+		return nil, gots.ErrInvalidPATLength
+	}
+	if err := NewParseOptions(options...).CheckCRC(patBytes[offset:]); err != nil {
+		if err == gots.ErrShortPayload {
+			return nil, gots.ErrInvalidPATLength
+		}
+		return nil, err
+	}
+
 	return pat(patBytes), nil
 }
 
+// CreatePAT creates a PAT with the given transport_stream_id, version and
+// map of program numbers to PMT PIDs. A network PID is included as program
+// number 0. The PAT is current and programs are ordered by program number.
+// ErrFieldOutOfRange is returned if the transport_stream_id or a program
+// number does not fit in 16 bits, a PID in 13 bits or the version in 5 bits.
+func CreatePAT(transportStreamID int, version uint8, programs map[int]int) (PAT, error) {
+	if transportStreamID < 0 || transportStreamID > 0xFFFF || version > 0x1F {
+		return nil, gots.ErrFieldOutOfRange
+	}
+	numbers := make([]int, 0, len(programs))
+	for pn, pid := range programs {
+		if pn < 0 || pn > 0xFFFF || pid < 0 || pid > 0x1FFF {
+			return nil, gots.ErrFieldOutOfRange
+		}
+		numbers = append(numbers, pn)
+	}
+	sort.Ints(numbers)
+
+	sectionLength := patStaticLen + 4*len(numbers)
+	if sectionLength > maxSectionLength {
+		return nil, gots.ErrSectionTooLong
+	}
+
+	th := TableHeader{
+		TableID:                patTableID,
+		SectionSyntaxIndicator: true,
+		SectionLength:          uint16(sectionLength),
+	}
+	data := append(NewPointerField(0), th.Data()...)
+	data = append(data,
+		byte(transportStreamID>>8), byte(transportStreamID),
+		0xC1|version&0x1f<<1, // reserved, version_number, current_next_indicator
+		0,                    // section_number
+		0,                    // last_section_number
+	)
+	for _, pn := range numbers {
+		pid := programs[pn]
+		data = append(data, byte(pn>>8), byte(pn), 0xE0|byte(pid>>8)&0x1f, byte(pid))
+	}
+	data = append(data, gots.ComputeCRC(data[1:])...)
+	return pat(data), nil
+}
+
+// section returns the PAT section following the pointer field.
+func (pat pat) section() []byte {
+	offset := int(1 + PointerField(pat))
+	if offset > len(pat) {
+		return nil
+	}
+	return pat[offset:]
+}
+
 // NumPrograms returns the number of programs in this PAT
 func (pat pat) NumPrograms() int {
 	sectionLength := int(SectionLength(pat))
@@ -92,13 +178,14 @@ func (pat pat) NumPrograms() int {
 func (pat pat) ProgramMap() map[int]int {
 	m := make(map[int]int)
 
-	counter := 8 // skip table id et al
+	section := pat.section()
+	counter := 7 // skip table id et al
 
-	for i := 0; i < pat.NumPrograms(); i++ {
-		pn := (int(pat[counter+1]) << 8) | int(pat[counter+2])
+	for i := 0; i < pat.NumPrograms() && counter+4 < len(section); i++ {
+		pn := (int(section[counter+1]) << 8) | int(section[counter+2])
 
 		// ignore the top three (reserved) bits
-		pid := int(pat[counter+3])&0x1f<<8 | int(pat[counter+4])
+		pid := int(section[counter+3])&0x1f<<8 | int(section[counter+4])
 
 		// A value of 0 is reserved for a NIT packet identifier.
 		if pn > 0 {
@@ -122,6 +209,56 @@ func (pat pat) SPTSpmtPID() (int, error) {
 	return 0, errors.New("No programs in transport stream")
 }
 
+// TransportStreamID returns the transport_stream_id of the PAT.
+func (pat pat) TransportStreamID() int {
+	section := pat.section()
+	if len(section) < 5 {
+		return 0
+	}
+	return int(section[3])<<8 | int(section[4])
+}
+
+// VersionNumber returns the version number of the PAT.
+func (pat pat) VersionNumber() uint8 {
+	version, _, _ := tableVersionAndCNI(pat.section())
+	return version
+}
+
+// CurrentNextIndicator returns true if the PAT is currently applicable.
+func (pat pat) CurrentNextIndicator() bool {
+	_, cni, _ := tableVersionAndCNI(pat.section())
+	return cni
+}
+
+// NetworkPID returns the PID of the NIT, listed as program number 0.
+// If the PAT has no network PID gots.ErrNoNetworkPID is returned.
+func (pat pat) NetworkPID() (int, error) {
+	section := pat.section()
+	counter := 8
+	for i := 0; i < pat.NumPrograms() && counter+3 < len(section); i++ {
+		if section[counter] == 0 && section[counter+1] == 0 {
+			return int(section[counter+2])&0x1f<<8 | int(section[counter+3]), nil
+		}
+		counter += 4
+	}
+	return 0, gots.ErrNoNetworkPID
+}
+
+// Data returns the bytes of the PAT, starting with the pointer field.
+func (pat pat) Data() []byte {
+	return pat
+}
+
+// Packets returns the PAT in packets on PID 0 with continuity counters
+// starting at cc.
+func (pat pat) Packets(cc uint8) []packet.Packet {
+	end := int(1+PointerField(pat)) + 3 + int(SectionLength(pat))
+	if end > len(pat) {
+		end = len(pat)
+	}
+	return NewSectionPacketizer(PatPid, cc).Packetize(pat[1+PointerField(pat) : end])
+}
+
 // ReadPAT extracts a PAT from a reader of a TS stream. It will read until a
 // PAT packet is found or EOF is reached.
 // It returns a new PAT object parsed from the packet, if found, and otherwise
@@ -27,8 +27,12 @@ package psi
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
 	"reflect"
 	"testing"
+
+	"github.com/Comcast/gots/v3"
+	"github.com/Comcast/gots/v3/packet"
 )
 
 var testData = []struct {
@@ -136,3 +140,131 @@ func TestReadPATIncomplete(t *testing.T) {
 		t.Errorf("Expected to get error reading PAT, but did not")
 	}
 }
+
+func TestPATHeaderFields(t *testing.T) {
+	patBytes, _ := hex.DecodeString("0000b0150007c500000001e0640002e0c80003e12c5f8bfdf2")
+	pat, err := NewPAT(patBytes)
+	if err != nil {
+		t.Fatalf("Can't parse PAT table %v", err)
+	}
+	if pat.TransportStreamID() != 7 {
+		t.Errorf("Wrong transport stream ID got %v, want %v", pat.TransportStreamID(), 7)
+	}
+	if pat.VersionNumber() != 2 {
+		t.Errorf("Wrong version number got %v, want %v", pat.VersionNumber(), 2)
+	}
+	if !pat.CurrentNextIndicator() {
+		t.Error("Expected current next indicator to be set")
+	}
+	if _, err := pat.NetworkPID(); err != gots.ErrNoNetworkPID {
+		t.Errorf("Expected ErrNoNetworkPID, got %v", err)
+	}
+}
+
+func TestCreatePAT(t *testing.T) {
+	programs := map[int]int{0: 16, 3: 300, 1: 100, 2: 200}
+	created, err := CreatePAT(0x1234, 5, programs)
+	if err != nil {
+		t.Fatalf("Unexpected error creating PAT: %v", err)
+	}
+	want, _ := hex.DecodeString("0000b0191234cb00000000e0100001e0640002e0c80003e12c")
+	data := created.Data()
+	if !bytes.Equal(want, data[:len(data)-4]) {
+		t.Errorf("Unexpected PAT\n got: %X\nwant: %X", data, want)
+	}
+	if !bytes.Equal(gots.ComputeCRC(data[1:len(data)-4]), data[len(data)-4:]) {
+		t.Errorf("PAT has an invalid CRC: %X", data)
+	}
+
+	pkts := created.Packets(3)
+	if len(pkts) != 1 {
+		t.Fatalf("Expected 1 packet, got %d", len(pkts))
+	}
+	if !packet.IsPat(&pkts[0]) || !packet.PayloadUnitStartIndicator(&pkts[0]) || packet.ContinuityCounter(&pkts[0]) != 3 {
+		t.Errorf("Unexpected PAT packet header %X", pkts[0][:4])
+	}
+
+	pat, err := NewPAT(pkts[0][:])
+	if err != nil {
+		t.Fatalf("Can't parse created PAT %v", err)
+	}
+	if pat.TransportStreamID() != 0x1234 || pat.VersionNumber() != 5 || !pat.CurrentNextIndicator() {
+		t.Errorf("Unexpected PAT header fields %X", pat.Data())
+	}
+	if nit, err := pat.NetworkPID(); err != nil || nit != 16 {
+		t.Errorf("Wrong network PID got %v, %v, want %v", nit, err, 16)
+	}
+	delete(programs, 0)
+	if !reflect.DeepEqual(programs, pat.ProgramMap()) {
+		t.Errorf("Wrong Program Map! got %v, want %v", pat.ProgramMap(), programs)
+	}
+}
+
+func TestCreatePATMultiplePackets(t *testing.T) {
+	programs := make(map[int]int)
+	for pn := 1; pn <= 100; pn++ {
+		programs[pn] = 0x100 + pn
+	}
+	pat, err := CreatePAT(1, 0, programs)
+	if err != nil {
+		t.Fatalf("Unexpected error creating PAT: %v", err)
+	}
+	pkts := pat.Packets(15)
+	if len(pkts) != 3 {
+		t.Fatalf("Expected 3 packets, got %d", len(pkts))
+	}
+	if packet.PayloadUnitStartIndicator(&pkts[1]) || packet.ContinuityCounter(&pkts[1]) != 0 {
+		t.Errorf("Unexpected header on second packet %X", pkts[1][:4])
+	}
+
+	programs[101] = 0x200
+	for pn := 102; pn <= 300; pn++ {
+		programs[pn] = 0x100 + pn
+	}
+	if _, err := CreatePAT(1, 0, programs); err != gots.ErrSectionTooLong {
+		t.Errorf("Expected ErrSectionTooLong, got %v", err)
+	}
+}
+
+func TestCreatePATOutOfRange(t *testing.T) {
+	tests := []struct {
+		tsid     int
+		version  uint8
+		programs map[int]int
+	}{
+		{0x10000, 0, map[int]int{1: 100}},
+		{-1, 0, map[int]int{1: 100}},
+		{1, 32, map[int]int{1: 100}},
+		{1, 0, map[int]int{0x10000: 100}},
+		{1, 0, map[int]int{1: 0x2000}},
+		{1, 0, map[int]int{1: -1}},
+	}
+	for _, test := range tests {
+		if _, err := CreatePAT(test.tsid, test.version, test.programs); err != gots.ErrFieldOutOfRange {
+			t.Errorf("CreatePAT(%d, %d, %v): expected ErrFieldOutOfRange, got %v", test.tsid, test.version, test.programs, err)
+		}
+	}
+	if _, err := CreatePAT(0xFFFF, 31, map[int]int{0xFFFF: 0x1FFF}); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func TestNewPATCRCMismatch(t *testing.T) {
+	patBytes, _ := hex.DecodeString("0000b00d0000c100000001e064dee0f321")
+	_, err := NewPAT(patBytes)
+	if !errors.Is(err, gots.ErrCRCMismatch) {
+		t.Fatalf("Expected ErrCRCMismatch, got %v", err)
+	}
+	var crcErr *gots.CRCMismatchError
+	if !errors.As(err, &crcErr) || crcErr.Expected != 0xdee0f321 || crcErr.Actual != 0xdee0f320 {
+		t.Errorf("Unexpected CRC mismatch error %v", err)
+	}
+
+	pat, err := NewPAT(patBytes, WithoutCRCCheck)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing PAT leniently: %v", err)
+	}
+	if !reflect.DeepEqual(map[int]int{1: 100}, pat.ProgramMap()) {
+		t.Errorf("Wrong Program Map! got %v", pat.ProgramMap())
+	}
+}
@@ -53,11 +53,41 @@ type PMT interface {
 	Pids() []int
 	VersionNumber() uint8
 	CurrentNextIndicator() bool
+	ProgramNumber() int
+	PCRPid() int
+	ProgramDescriptors() []PmtDescriptor
 	ElementaryStreams() []PmtElementaryStream
 	RemoveElementaryStreams(pids []int)
 	IsPidForStreamWherePresentationLagsEbp(pid int) bool
 	String() string
 	PIDExists(pid int) bool
+
+	// SetProgramNumber sets the program_number of the PMT. UpdateData
+	// fails if it does not fit in 16 bits.
+	SetProgramNumber(programNumber int)
+	// SetPCRPid sets the PID carrying the PCR of the program. UpdateData
+	// fails if it does not fit in 13 bits.
+	SetPCRPid(pid int)
+	// SetVersionNumber sets the 5 bit version_number of the PMT. UpdateData
+	// fails if it does not fit in 5 bits.
+	SetVersionNumber(version uint8)
+	// SetCurrentNextIndicator sets whether the PMT is currently applicable.
+	SetCurrentNextIndicator(cni bool)
+	// SetProgramDescriptors replaces the program level descriptors.
+	SetProgramDescriptors(descriptors []PmtDescriptor)
+	// AddElementaryStream adds an elementary stream, replacing any stream
+	// on the same PID.
+	AddElementaryStream(es PmtElementaryStream)
+	// SetElementaryStreams replaces the elementary streams of the PMT.
+	SetElementaryStreams(streams []PmtElementaryStream)
+	// UpdateData encodes the PMT after it was modified and returns the
+	// bytes, starting with the pointer field.
+	UpdateData() ([]byte, error)
+	// Data returns the bytes of the PMT as parsed or last encoded.
+	Data() []byte
+	// Packets encodes the PMT and returns it in packets on the given PID
+	// with continuity counters starting at cc.
+	Packets(pid int, cc uint8) ([]packet.Packet, error)
 }
 
 type pmt struct {
@@ -65,6 +95,10 @@ type pmt struct {
 	elementaryStreams    []PmtElementaryStream
 	versionNumber        uint8
 	currentNextIndicator bool
+	programNumber        int
+	pcrPid               int
+	programDescriptors   []PmtDescriptor
+	data                 []byte
 }
 
 // PmtAccumulatorDoneFunc is a doneFunc that can be used for packet accumulation
@@ -93,22 +127,30 @@ func PmtAccumulatorDoneFunc(b []byte) (bool, error) {
 
 // NewPMT Creates a new PMT from the given bytes.
 // pmtBytes should be concatenated packet payload contents.
-func NewPMT(pmtBytes []byte) (PMT, error) {
+// The CRC of each PMT section is validated unless WithoutCRCCheck is given.
+func NewPMT(pmtBytes []byte, options ...func(*ParseOptions)) (PMT, error) {
 	pmt := &pmt{}
-	err := pmt.parseTables(pmtBytes)
+	err := pmt.parseTables(pmtBytes, NewParseOptions(options...))
 	if err != nil {
 		return nil, err
 	}
+	pmt.data = pmtBytes
 	return pmt, nil
 }
 
-func (p *pmt) parseTables(pmtBytes []byte) error {
+func (p *pmt) parseTables(pmtBytes []byte, options ParseOptions) error {
 	sectionBytes := pmtBytes[1+PointerField(pmtBytes):]
 
 	for len(sectionBytes) > 2 && sectionBytes[0] != 0xFF {
 		tableLength := sectionLength(sectionBytes)
 
 		if tableID(sectionBytes) == 0x2 {
+			if err := options.CheckCRC(sectionBytes); err != nil {
+				if err == gots.ErrShortPayload {
+					return gots.ErrPMTParse
+				}
+				return err
+			}
 			err := p.parsePMTSection(sectionBytes[0 : 3+tableLength])
 			if err != nil {
 				return err
@@ -134,10 +176,21 @@ func (p *pmt) parsePMTSection(pmtBytes []byte) error {
 	if err != nil {
 		return err
 	}
+	p.programNumber = int(pmtBytes[3])<<8 | int(pmtBytes[4])
+	p.pcrPid = int(pmtBytes[8]&0x1f)<<8 | int(pmtBytes[9])
 
 	programInfoLength := uint16(pmtBytes[programInfoLengthOffset]&0x0f)<<8 |
 		uint16(pmtBytes[programInfoLengthOffset+1])
 
+	// A malformed program_info loop does not prevent the elementary streams
+	// from being parsed, only the descriptors preceding the error are kept.
+	programInfoStart := programInfoLengthOffset + 2
+	programInfoEnd := programInfoStart + int(programInfoLength)
+	if programInfoEnd > len(pmtBytes) {
+		programInfoEnd = len(pmtBytes)
+	}
+	p.programDescriptors, _ = ParseDescriptors(pmtBytes[programInfoStart:programInfoEnd])
+
 	// start at the stream descriptors, parse until the CRC
 	for offset := programInfoLengthOffset + 2 + programInfoLength; offset < PSIHeaderLen+sectionLength-pmtEsDescriptorStaticLen-CrcLen; {
 		elementaryStreamType := uint8(pmtBytes[offset])
@@ -171,8 +224,13 @@ func (p *pmt) parsePMTSection(pmtBytes []byte) error {
 		elementaryStreams = append(elementaryStreams, es)
 	}
 
-	p.pids = pids
-	p.elementaryStreams = elementaryStreams
+	// Streams of later sections of the same table are appended
+	if Section(pmtBytes).SectionNumber() == 0 {
+		p.pids = nil
+		p.elementaryStreams = nil
+	}
+	p.pids = append(p.pids, pids...)
+	p.elementaryStreams = append(p.elementaryStreams, elementaryStreams...)
 	return nil
 }
 
@@ -191,6 +249,21 @@ func (p *pmt) CurrentNextIndicator() bool {
 	return p.currentNextIndicator
 }
 
+// ProgramNumber returns the program number the PMT applies to
+func (p *pmt) ProgramNumber() int {
+	return p.programNumber
+}
+
+// PCRPid returns the PID of the packets carrying the PCR of the program
+func (p *pmt) PCRPid() int {
+	return p.pcrPid
+}
+
+// ProgramDescriptors returns the descriptors of the program_info loop
+func (p *pmt) ProgramDescriptors() []PmtDescriptor {
+	return p.programDescriptors
+}
+
 // ElementaryStreams returns a slice of PMT Elementary Streams
 func (p *pmt) ElementaryStreams() []PmtElementaryStream {
 	return p.elementaryStreams
@@ -281,6 +354,8 @@ func CanBuildPMT(payload []byte, sectionLength uint16) bool {
 // Returns packets and nil error if all pids are present in the PMT.
 // Returns packets and non-nil error if some pids are present in the PMT.
 // Returns nil packets and non-nil error if none of the pids are present in the PMT.
+// The filtered PMT is written to the given packets in order, keeping their headers and adaptation fields,
+// and packets that are no longer needed are dropped.
 func FilterPMTPacketsToPids(packets []*packet.Packet, pids []int) ([]*packet.Packet, error) {
 	// make sure we have packets
 	if len(packets) == 0 {
@@ -373,25 +448,21 @@ func FilterPMTPacketsToPids(packets []*packet.Packet, pids []int) ([]*packet.Pac
 	// Recalculate the CRC
 	fPMT = append(fPMT, gots.ComputeCRC(fPMT[pointerField:])...)
 
+	// Write the filtered PMT, which is never longer than the original, to
+	// the original packets so that their adaptation fields are preserved
 	var filteredPMTPackets []*packet.Packet
 	for _, pkt := range packets {
-		var pktBuf bytes.Buffer
-		header := packet.Header(pkt)
-		pktBuf.Write(header)
-		if len(fPMT) > 0 {
-			toWrite := safeSlice(fPMT, 0, packet.PacketSize-len(header))
-			// truncate fPMT to the remaining bytes
-			if len(toWrite) < len(fPMT) {
-				fPMT = fPMT[len(toWrite):]
-			} else {
-				fPMT = nil
-			}
-			pktBuf.Write(toWrite)
-		} else {
-			// all done
+		if len(fPMT) == 0 {
 			break
 		}
-		filteredPMTPackets = append(filteredPMTPackets, padPacket(&pktBuf))
+		var filtered packet.Packet
+		header := copy(filtered[:], packet.Header(pkt))
+		n := copy(filtered[header:], fPMT)
+		fPMT = fPMT[n:]
+		for i := header + n; i < packet.PacketSize; i++ {
+			filtered[i] = 0xff
+		}
+		filteredPMTPackets = append(filteredPMTPackets, &filtered)
 	}
 	return filteredPMTPackets, returnError
 }
@@ -417,21 +488,6 @@ func IsPMT(pkt *packet.Packet, pat PAT) (bool, error) {
 	return false, nil
 }
 
-func safeSlice(byteArray []byte, start, end int) []byte {
-	if end < len(byteArray) {
-		return byteArray[start:end]
-	}
-	return byteArray[start:len(byteArray)]
-}
-
-func padPacket(buf *bytes.Buffer) *packet.Packet {
-	var pkt packet.Packet
-	for i := copy(pkt[:], buf.Bytes()); i < packet.PacketSize; i++ {
-		pkt[i] = 0xff
-	}
-	return &pkt
-}
-
 func pidIn(pids []int, target int) bool {
 	for _, pid := range pids {
 		if pid == target {
@@ -27,6 +27,7 @@ package psi
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -100,6 +101,10 @@ func (es *testPmtElementaryStream) IsTTMLSubtitling() bool {
 	return false
 }
 
+func (es *testPmtElementaryStream) Codec() Codec {
+	return CodecUnknown
+}
+
 func TestParseTable(t *testing.T) {
 	byteArray, _ := hex.DecodeString("0002b02d0001cb0000e065f0060504435545491b" +
 		"e065f0050e030004b00fe066f0060a04656e670086e06ef0" +
@@ -111,7 +116,7 @@ func TestParseTable(t *testing.T) {
 		"ffffffffffffffffffffffffffffffffffffffff")
 
 	pmt := pmt{}
-	err := pmt.parseTables(byteArray)
+	err := pmt.parseTables(byteArray, ParseOptions{})
 	if err != nil {
 		t.Errorf("Can't parse PMT table %v", err)
 	}
@@ -140,7 +145,7 @@ func TestParseMultipleTables(t *testing.T) {
 		"ffffffffffffffffffffffffffffffffffffffff")
 
 	pmt := pmt{}
-	err := pmt.parseTables(byteArray)
+	err := pmt.parseTables(byteArray, ParseOptions{})
 	if err != nil {
 		t.Errorf("Can't parse PMT table %v", err)
 	}
@@ -220,6 +225,43 @@ func TestPIDExists(t *testing.T) {
 	}
 }
 
+func TestProgramFields(t *testing.T) {
+	pkt := parseHexString("474064100002b02d0001cb0000e065f0060504435545491b" +
+		"e065f0050e030004b00fe066f0060a04656e670086e06ef0" +
+		"007fc9ad32ffffffffffffffffffffffffffffffffffffff" +
+		"ffffffffffffffffffffffffffffffffffffffffffffffff" +
+		"ffffffffffffffffffffffffffffffffffffffffffffffff" +
+		"ffffffffffffffffffffffffffffffffffffffffffffffff" +
+		"ffffffffffffffffffffffffffffffffffffffffffffffff" +
+		"ffffffffffffffffffffffffffffffffffffffff")
+	pay, _ := packet.Payload(pkt)
+	pmt, err := NewPMT(pay)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pmt.ProgramNumber() != 1 {
+		t.Errorf("Wrong program number got %v, want %v", pmt.ProgramNumber(), 1)
+	}
+	if pmt.PCRPid() != 101 {
+		t.Errorf("Wrong PCR PID got %v, want %v", pmt.PCRPid(), 101)
+	}
+	descriptors := pmt.ProgramDescriptors()
+	if len(descriptors) != 1 {
+		t.Fatalf("Expected 1 program descriptor, got %d", len(descriptors))
+	}
+	d := descriptors[0]
+	if !d.IsRegistrationDescriptor() || !d.IsSCTE35Registration() {
+		t.Errorf("Expected a CUEI registration descriptor, got %v", d)
+	}
+	if d.IsConditionalAccessDescriptor() || d.IsSCTEAdaptationDescriptor() {
+		t.Errorf("Unexpected descriptor type %v", d)
+	}
+	if !bytes.Equal(d.Data(), []byte("CUEI")) {
+		t.Errorf("Unexpected descriptor data %X", d.Data())
+	}
+}
+
 func TestBuildPMT_ExpectsAnotherPacket(t *testing.T) {
 	pkt := parseHexString(
 		"4740271A0002B0BA0001F70000E065F00C0F04534150530504435545491BE065" +
@@ -524,6 +566,42 @@ func TestFilterPMTPacketsToPids_MultiPacketPMT(t *testing.T) {
 	}
 }
 
+func TestFilterPMTPacketsToPids_AdaptationField(t *testing.T) {
+	pmt, _ := CreatePMT(1, 0x64, 0)
+	pmt.SetElementaryStreams([]PmtElementaryStream{
+		NewPmtElementaryStream(0x1b, 0x65, nil),
+		NewPmtElementaryStream(0x0f, 0x66, nil),
+	})
+	data, err := pmt.UpdateData()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// A PMT packet carrying a PCR in its adaptation field
+	var pkt packet.Packet
+	header := []byte{0x47, 0x40, 0x64, 0x35, 0x07, 0x10, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06}
+	n := copy(pkt[:], header)
+	n += copy(pkt[n:], data)
+	for ; n < packet.PacketSize; n++ {
+		pkt[n] = 0xff
+	}
+
+	filtered, err := FilterPMTPacketsToPids([]*packet.Packet{&pkt}, []int{0x65})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(filtered) != 1 || !bytes.Equal(filtered[0][:len(header)], header) {
+		t.Fatalf("Expected the header and adaptation field to be preserved, got %X", filtered[0][:len(header)])
+	}
+	pay, _ := packet.Payload(filtered[0])
+	filteredPMT, err := NewPMT(pay)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if pids := filteredPMT.Pids(); len(pids) != 1 || pids[0] != 0x65 {
+		t.Errorf("Unexpected PIDs %v", pids)
+	}
+}
+
 func TestFilterPMTPacketsToPids_PIDNotFound(t *testing.T) {
 	// PMT contains PIDs 101-105.
 	pmtPacketBytes := parseHexString("4740641D0002B0940001DF0000E065F0050E03C015581BE065F0150E03C0109D2A027E1F9700E9080C001F418503E84187E066F01A0E03C00122050445414333CC07E0C2B0E8656E670A04656E670087E067F01A0E03C00122050445414333CC07E0C2B0E8656E670A04656E67000FE068F0100E03C001262B030102010A04656E67000FE069F0100E03C001262B030102010A04656E67002E9B5B71FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF")
@@ -932,3 +1010,51 @@ func TestIsDolbyATMOS(t *testing.T) {
 		t.Errorf("Positive Dolby ATMOS Stream failed. Supposed to be a Dolby ATMOS stream.")
 	}
 }
+
+func TestNewPMTCRCMismatch(t *testing.T) {
+	pkt := packet.TestPmtPacket
+	pay, _ := packet.Payload(&pkt)
+	pay[20]++
+	if _, err := NewPMT(pay); !errors.Is(err, gots.ErrCRCMismatch) {
+		t.Errorf("Expected ErrCRCMismatch, got %v", err)
+	}
+	pmt, err := NewPMT(pay, WithoutCRCCheck)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing PMT leniently: %v", err)
+	}
+	if len(pmt.Pids()) != 3 {
+		t.Errorf("Expected 3 PIDs, got %v", pmt.Pids())
+	}
+}
+
+func TestDecodeIso639Languages(t *testing.T) {
+	d := NewPmtDescriptor(LANGUAGE, []byte{'e', 'n', 'g', 0x00, 's', 'p', 'a', 0x03})
+	languages := d.DecodeIso639Languages()
+	expected := []Iso639Language{
+		{Code: "eng", AudioType: AUDIO_UNDEFINED},
+		{Code: "spa", AudioType: AUDIO_DESCRIPTION},
+	}
+	if len(languages) != len(expected) {
+		t.Fatalf("Expected %d languages, got %v", len(expected), languages)
+	}
+	for i := range expected {
+		if languages[i] != expected[i] {
+			t.Errorf("Language %d: expected %v, got %v", i, expected[i], languages[i])
+		}
+	}
+	if d.DecodeIso639LanguageCode() != "eng" {
+		t.Errorf("Expected first language code eng, got %s", d.DecodeIso639LanguageCode())
+	}
+	if audioType := NewPmtDescriptor(LANGUAGE, []byte{'s', 'p', 'a', 0x03}).DecodeIso639AudioType(); audioType != AUDIO_DESCRIPTION {
+		t.Errorf("Expected first audio type %v, got %v", AUDIO_DESCRIPTION, audioType)
+	}
+	if s := fmt.Sprint(d); s != "ISO 639 Language (code=eng, audioType=0x00; code=spa, audioType=0x03)" {
+		t.Errorf("Unexpected string %q", s)
+	}
+	if AUDIO_DESCRIPTION.String() != "Visual impaired commentary" || AudioType(0x42).String() != "0x42" {
+		t.Errorf("Unexpected audio type names %v %v", AUDIO_DESCRIPTION, AudioType(0x42))
+	}
+	if NewPmtDescriptor(LANGUAGE, []byte{'e', 'n'}).DecodeIso639LanguageCode() != "" {
+		t.Error("Expected no language code for a short descriptor")
+	}
+}
@@ -29,41 +29,92 @@ import (
 	"encoding/hex"
 	"fmt"
 	"strconv"
+	"strings"
+
+	"github.com/Comcast/gots/v3"
 )
 
 // Program Element Stream Descriptor Type.
 const (
-	VIDEO_STREAM       uint8 = 2   // 0000 0010 (0x02)
-	AUDIO_STREAM       uint8 = 3   // 0000 0011 (0x03)
-	REGISTRATION       uint8 = 5   // 0000 1000 (0x05)
-	CONDITIONAL_ACCESS uint8 = 9   // 0000 1001 (0x09)
-	LANGUAGE           uint8 = 10  // 0000 1010 (0x0A)
-	SYSTEM_CLOCK       uint8 = 11  // 0000 1011 (0x0B)
-	DOLBY_DIGITAL      uint8 = 12  // 0000 1100 (0x0C)
-	COPYRIGHT          uint8 = 13  // 0000 1101 (0x0D)
-	MAXIMUM_BITRATE    uint8 = 14  // 0000 1110 (0x0E)
-	AVC_VIDEO          uint8 = 40  // 0010 1000 (0x28)
-	STREAM_IDENTIFIER  uint8 = 82  // 0101 0010 (0x52)
-	EXTENSION          uint8 = 127 // 0111 1111 (0x7F)
-	SCTE_ADAPTATION    uint8 = 151 // 1001 0111 (0x97)
-	DOLBY_VISION       uint8 = 176 // 1011 0000 (0xB0)
-	EBP                uint8 = 233 // 1110 1001 (0xE9)
-	EC3                uint8 = 204 // 1100 1100 (0xCC)
+	VIDEO_STREAM          uint8 = 2   // 0000 0010 (0x02)
+	AUDIO_STREAM          uint8 = 3   // 0000 0011 (0x03)
+	REGISTRATION          uint8 = 5   // 0000 1000 (0x05)
+	DATA_STREAM_ALIGNMENT uint8 = 6   // 0000 0110 (0x06)
+	CONDITIONAL_ACCESS    uint8 = 9   // 0000 1001 (0x09)
+	LANGUAGE              uint8 = 10  // 0000 1010 (0x0A)
+	SYSTEM_CLOCK          uint8 = 11  // 0000 1011 (0x0B)
+	DOLBY_DIGITAL         uint8 = 12  // 0000 1100 (0x0C)
+	COPYRIGHT             uint8 = 13  // 0000 1101 (0x0D)
+	MAXIMUM_BITRATE       uint8 = 14  // 0000 1110 (0x0E)
+	MPEG4_AUDIO           uint8 = 28  // 0001 1100 (0x1C)
+	AVC_VIDEO             uint8 = 40  // 0010 1000 (0x28)
+	MPEG2_AAC_AUDIO       uint8 = 43  // 0010 1011 (0x2B)
+	HEVC_VIDEO            uint8 = 56  // 0011 1000 (0x38)
+	STREAM_IDENTIFIER     uint8 = 82  // 0101 0010 (0x52)
+	DVB_AC3               uint8 = 106 // 0110 1010 (0x6A)
+	DVB_EAC3              uint8 = 122 // 0111 1010 (0x7A)
+	DTS                   uint8 = 123 // 0111 1011 (0x7B)
+	DVB_AAC               uint8 = 124 // 0111 1100 (0x7C)
+	EXTENSION             uint8 = 127 // 0111 1111 (0x7F)
+	AV1_VIDEO             uint8 = 128 // 1000 0000 (0x80)
+	AC3_AUDIO             uint8 = 129 // 1000 0001 (0x81)
+	SCTE_ADAPTATION       uint8 = 151 // 1001 0111 (0x97)
+	DOLBY_VISION          uint8 = 176 // 1011 0000 (0xB0)
+	EBP                   uint8 = 233 // 1110 1001 (0xE9)
+	EC3                   uint8 = 204 // 1100 1100 (0xCC)
 )
 
+// AudioType is the audio_type of an ISO_639_language_descriptor entry.
+type AudioType uint8
+
 // ISO_639 Audio service type
 const (
-	AUDIO_UNDEFINED        int = 0   // 0000 0000 (0x00)
-	AUDIO_CLEAN_EFFECTS    int = 1   // 0000 0001 (0x01)
-	AUDIO_HEARING_IMPAIRED int = 2   // 0000 0010 (0x02)
-	AUDIO_DESCRIPTION      int = 3   // 0000 0011 (0x03)
-	AUDIO_PRIMARY          int = 128 // 1000 0000 (0x80)
-	AUDIO_NATIVE           int = 129 // 1000 0001 (0x81)
+	AUDIO_UNDEFINED        AudioType = 0   // 0000 0000 (0x00)
+	AUDIO_CLEAN_EFFECTS    AudioType = 1   // 0000 0001 (0x01)
+	AUDIO_HEARING_IMPAIRED AudioType = 2   // 0000 0010 (0x02)
+	AUDIO_DESCRIPTION      AudioType = 3   // 0000 0011 (0x03)
+	AUDIO_PRIMARY          AudioType = 128 // 1000 0000 (0x80)
+	AUDIO_NATIVE           AudioType = 129 // 1000 0001 (0x81)
+)
+
+// AudioTypeNames maps audio types to human readable names.
+var AudioTypeNames = map[AudioType]string{
+	AUDIO_UNDEFINED:        "Undefined",
+	AUDIO_CLEAN_EFFECTS:    "Clean effects",
+	AUDIO_HEARING_IMPAIRED: "Hearing impaired",
+	AUDIO_DESCRIPTION:      "Visual impaired commentary",
+	AUDIO_PRIMARY:          "Primary",
+	AUDIO_NATIVE:           "Native",
+}
+
+// String returns the name of the audio type, or its value if it is user
+// private or reserved.
+func (a AudioType) String() string {
+	if name, ok := AudioTypeNames[a]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%02X", uint8(a))
+}
+
+// Iso639Language is one entry of an ISO_639_language_descriptor.
+type Iso639Language struct {
+	Code      string
+	AudioType AudioType
+}
+
+// EBP partition_id values
+const (
+	EBP_PARTITION_FRAGMENT uint8 = 1 // 0000 0001 (0x01)
+	EBP_PARTITION_SEGMENT  uint8 = 2 // 0000 0010 (0x02)
 )
 
 // Descriptor tag extension
 const (
-	TTML_DESC_TAG_EXTENSION uint8 = 32 // 0010 0000 (0x20)
+	DTS_HD_DESC_TAG_EXTENSION  uint8 = 14  // 0000 1110 (0x0E)
+	AC4_DESC_TAG_EXTENSION     uint8 = 21  // 0001 0101 (0x15)
+	TTML_DESC_TAG_EXTENSION    uint8 = 32  // 0010 0000 (0x20)
+	DTS_UHD_DESC_TAG_EXTENSION uint8 = 33  // 0010 0001 (0x21)
+	OPUS_DESC_TAG_EXTENSION    uint8 = 128 // 1000 0000 (0x80)
 )
 
 const (
@@ -80,14 +131,20 @@ const (
 // PmtDescriptor represents operations currently necessary on descriptors found in the PMT
 type PmtDescriptor interface {
 	Tag() uint8
+	Data() []byte
 	Format() string
 	IsIso639LanguageDescriptor() bool
 	IsMaximumBitrateDescriptor() bool
+	IsRegistrationDescriptor() bool
+	IsConditionalAccessDescriptor() bool
+	IsSCTEAdaptationDescriptor() bool
+	IsSCTE35Registration() bool
 	IsIFrameProfile() bool
 	IsEBPDescriptor() bool
 	DecodeMaximumBitRate() uint32
 	DecodeIso639LanguageCode() string
-	DecodeIso639AudioType() byte
+	DecodeIso639AudioType() AudioType
+	DecodeIso639Languages() []Iso639Language
 	IsDolbyATMOS() bool
 	IsDolbyVision() bool
 	DecodeDolbyVisionCodec(string) string
@@ -95,6 +152,23 @@ type PmtDescriptor interface {
 	DecodeTTMLIso639LanguageCode() string
 	DecodeTTMLSubtitlePurpose() uint8
 	IsTTMLDescTagExtension() bool
+	RawData() []byte
+	DecodeRegistration() (RegistrationDescriptor, error)
+	DecodeAVCVideo() (AVCVideoDescriptor, error)
+	DecodeHEVCVideo() (HEVCVideoDescriptor, error)
+	DecodeMPEG4Audio() (uint8, error)
+	DecodeMPEG2AAC() (MPEG2AACDescriptor, error)
+	DecodeAAC() (AACDescriptor, error)
+	DecodeAC3() (AC3Descriptor, error)
+	DecodeDVBAC3() (DVBAC3Descriptor, error)
+	DecodeDataStreamAlignment() (uint8, error)
+	DecodeVideoStream() (VideoStreamDescriptor, error)
+	DecodeAudioStream() (AudioStreamDescriptor, error)
+	IsExtensionDescriptor(uint8) bool
+	DecodeAV1Video() (AV1VideoDescriptor, error)
+	DecodeAC4() (AC4Descriptor, error)
+	DecodeOpusChannelConfig() (uint8, error)
+	DecodeEBP() (EBPDescriptor, error)
 }
 
 type pmtDescriptor struct {
@@ -114,6 +188,40 @@ func (descriptor *pmtDescriptor) Tag() uint8 {
 	return descriptor.tag
 }
 
+// Data returns the descriptor contents following the tag and length.
+func (descriptor *pmtDescriptor) Data() []byte {
+	return descriptor.data
+}
+
+// ParseDescriptors parses a descriptor loop, such as the descriptors of a
+// CAT or of an elementary stream. If the loop is malformed the descriptors
+// preceding the malformed one are returned with gots.ErrParsePMTDescriptor.
+func ParseDescriptors(b []byte) ([]PmtDescriptor, error) {
+	var descriptors []PmtDescriptor
+	for len(b) > 0 {
+		if len(b) < 2 || len(b) < 2+int(b[1]) {
+			return descriptors, gots.ErrParsePMTDescriptor
+		}
+		descriptors = append(descriptors, NewPmtDescriptor(b[0], b[2:2+int(b[1])]))
+		b = b[2+int(b[1]):]
+	}
+	return descriptors, nil
+}
+
+// descriptorsData encodes a descriptor loop. gots.ErrInvalidDescriptor is
+// returned if a descriptor does not fit in the 8 bit descriptor_length.
+func descriptorsData(descriptors []PmtDescriptor) ([]byte, error) {
+	var data []byte
+	for _, d := range descriptors {
+		if len(d.Data()) > 0xFF {
+			return nil, gots.ErrInvalidDescriptor
+		}
+		data = append(data, d.Tag(), byte(len(d.Data())))
+		data = append(data, d.Data()...)
+	}
+	return data, nil
+}
+
 func (descriptor *pmtDescriptor) String() string {
 	return descriptor.decode()
 }
@@ -125,8 +233,16 @@ func (descriptor *pmtDescriptor) Format() string {
 func (descriptor *pmtDescriptor) decode() string {
 	switch descriptor.tag {
 	case LANGUAGE:
-		return fmt.Sprintf("ISO 639 Language (code=%s, audioType=0x%s)",
-			descriptor.DecodeIso639LanguageCode(), hex.EncodeToString([]byte{descriptor.DecodeIso639AudioType()}))
+		languages := descriptor.DecodeIso639Languages()
+		if len(languages) <= 1 {
+			return fmt.Sprintf("ISO 639 Language (code=%s, audioType=0x%s)",
+				descriptor.DecodeIso639LanguageCode(), hex.EncodeToString([]byte{byte(descriptor.DecodeIso639AudioType())}))
+		}
+		entries := make([]string, len(languages))
+		for i, l := range languages {
+			entries[i] = fmt.Sprintf("code=%s, audioType=0x%02x", l.Code, uint8(l.AudioType))
+		}
+		return fmt.Sprintf("ISO 639 Language (%s)", strings.Join(entries, "; "))
 	case MAXIMUM_BITRATE:
 		return fmt.Sprintf("Maximum Bit-Rate (%d)", descriptor.DecodeMaximumBitRate())
 	case VIDEO_STREAM:
@@ -134,8 +250,14 @@ func (descriptor *pmtDescriptor) decode() string {
 	case AUDIO_STREAM:
 		return fmt.Sprintf("Audio Stream (%d)", descriptor.tag)
 	case REGISTRATION:
+		if len(descriptor.data) >= 4 {
+			return fmt.Sprintf("Registration (%d): %q", descriptor.tag, descriptor.data[:4])
+		}
 		return fmt.Sprintf("Registration (%d)", descriptor.tag)
 	case CONDITIONAL_ACCESS:
+		if ca, err := DecodeCADescriptor(descriptor); err == nil {
+			return fmt.Sprintf("Conditional Access (%d): %v", descriptor.tag, ca)
+		}
 		return fmt.Sprintf("Conditional Access (%d)", descriptor.tag)
 	case SYSTEM_CLOCK:
 		return fmt.Sprintf("System Clock (%d)", descriptor.tag)
@@ -150,11 +272,28 @@ func (descriptor *pmtDescriptor) decode() string {
 	case DOLBY_VISION:
 		return fmt.Sprintf("Dolby Vision (%d)", descriptor.tag)
 	case EBP:
+		if ebp, err := descriptor.DecodeEBP(); err == nil {
+			return fmt.Sprintf("EBP (%d): %d partitions", descriptor.tag, len(ebp.Partitions))
+		}
 		return fmt.Sprintf("EBP (%d)", descriptor.tag)
 	case STREAM_IDENTIFIER:
 		return fmt.Sprintf("Stream Identifier (%d): %v", descriptor.tag, descriptor.data[0])
 	case EXTENSION:
+		switch {
+		case descriptor.IsExtensionDescriptor(AC4_DESC_TAG_EXTENSION):
+			return "AC-4"
+		case descriptor.IsExtensionDescriptor(OPUS_DESC_TAG_EXTENSION):
+			return "Opus"
+		case descriptor.IsExtensionDescriptor(DTS_HD_DESC_TAG_EXTENSION):
+			return "DTS-HD"
+		case descriptor.IsExtensionDescriptor(DTS_UHD_DESC_TAG_EXTENSION):
+			return "DTS-UHD"
+		}
 		return fmt.Sprintf("TTML Subtitling (language code=%s)", descriptor.DecodeTTMLIso639LanguageCode())
+	case AV1_VIDEO:
+		return fmt.Sprintf("AV1 Video (%d)", descriptor.tag)
+	case DTS:
+		return fmt.Sprintf("DTS (%d)", descriptor.tag)
 	}
 	return "unknown tag (" + strconv.Itoa(int(descriptor.tag)) + ")"
 }
@@ -167,36 +306,84 @@ func (descriptor *pmtDescriptor) IsMaximumBitrateDescriptor() bool {
 	return descriptor.tag == MAXIMUM_BITRATE
 }
 
+func (descriptor *pmtDescriptor) IsRegistrationDescriptor() bool {
+	return descriptor.tag == REGISTRATION
+}
+
+func (descriptor *pmtDescriptor) IsConditionalAccessDescriptor() bool {
+	return descriptor.tag == CONDITIONAL_ACCESS
+}
+
+func (descriptor *pmtDescriptor) IsSCTEAdaptationDescriptor() bool {
+	return descriptor.tag == SCTE_ADAPTATION
+}
+
+// IsSCTE35Registration returns true for a registration descriptor with the
+// format identifier "CUEI", which signals that the program carries SCTE-35.
+func (descriptor *pmtDescriptor) IsSCTE35Registration() bool {
+	return descriptor.IsRegistrationDescriptor() && len(descriptor.data) >= 4 &&
+		string(descriptor.data[:4]) == "CUEI"
+}
+
 func (descriptor *pmtDescriptor) IsEBPDescriptor() bool {
 	return descriptor.tag == EBP
 }
 
-// Return the decoded Maximum_bitrate in units of 50 bytes per second
+// Return the decoded Maximum_bitrate in units of 50 bytes per second.
+// The field is 22 bits wide, the six low bits of the first byte and the two
+// bytes after it. Descriptors shorter than three bytes decode to zero.
 func (descriptor *pmtDescriptor) DecodeMaximumBitRate() uint32 {
-	if descriptor.IsMaximumBitrateDescriptor() {
-		return uint32(descriptor.data[0]&0x1f)<<16 | uint32(descriptor.data[1])<<8 | uint32(descriptor.data[2])
+	if descriptor.IsMaximumBitrateDescriptor() && len(descriptor.data) >= 3 {
+		return uint32(descriptor.data[0]&0x3f)<<16 | uint32(descriptor.data[1])<<8 | uint32(descriptor.data[2])
 	}
 	return 0
 }
 
+// DecodeIso639LanguageCode returns the language code of the first entry of
+// an ISO_639_language_descriptor. Use DecodeIso639Languages for descriptors
+// with several entries, such as dual language audio.
 func (descriptor *pmtDescriptor) DecodeIso639LanguageCode() string {
-	if LANGUAGE == descriptor.tag {
+	if LANGUAGE == descriptor.tag && len(descriptor.data) >= 3 {
 		return string(descriptor.data[0:3])
 	}
 	return ""
 }
 
-func (descriptor *pmtDescriptor) DecodeIso639AudioType() byte {
+// DecodeIso639AudioType returns the audio type of the first entry of an
+// ISO_639_language_descriptor.
+func (descriptor *pmtDescriptor) DecodeIso639AudioType() AudioType {
 	if len(descriptor.data) >= 4 {
-		return descriptor.data[3]
+		return AudioType(descriptor.data[3])
 	}
 	return 0
 }
 
+// DecodeIso639Languages returns every entry of an
+// ISO_639_language_descriptor. A trailing partial entry is ignored.
+func (descriptor *pmtDescriptor) DecodeIso639Languages() []Iso639Language {
+	if descriptor.tag != LANGUAGE {
+		return nil
+	}
+	languages := make([]Iso639Language, 0, len(descriptor.data)/4)
+	for b := descriptor.data; len(b) >= 4; b = b[4:] {
+		languages = append(languages, Iso639Language{
+			Code:      string(b[0:3]),
+			AudioType: AudioType(b[3]),
+		})
+	}
+	return languages
+}
+
 func (descriptor *pmtDescriptor) IsTTMLDescTagExtension() bool {
 	return len(descriptor.data) >= 1 && uint8(descriptor.data[0]) == TTML_DESC_TAG_EXTENSION
 }
 
+// IsExtensionDescriptor returns true for a DVB extension descriptor with the
+// given descriptor_tag_extension.
+func (descriptor *pmtDescriptor) IsExtensionDescriptor(extension uint8) bool {
+	return descriptor.tag == EXTENSION && len(descriptor.data) >= 1 && descriptor.data[0] == extension
+}
+
 func (descriptor *pmtDescriptor) IsTTMLSubtitlingDescriptor() bool {
 	return descriptor.tag == EXTENSION
 }
@@ -225,42 +412,15 @@ func (descriptor *pmtDescriptor) DecodeTTMLSubtitlePurpose() uint8 {
 // descriptor as defined on page 16-17 of OC-SP-EBP-I01-130018.pdf.
 // https://www.teamccp.com/confluence/download/attachments/59024185/OC-SP-EBP-I01-130118.pdf?version=1&modificationDate=1378666671000&api=v2
 func (descriptor *pmtDescriptor) IsIFrameProfile() bool {
-	if EBP == descriptor.tag && 0 < len(descriptor.data) {
-
-		offset := 0
-		num_partitions := uint8((descriptor.data[offset] & 0xF8) >> 3)
-		timescale_flag := 1 == uint8((descriptor.data[offset]&0x04)>>2)
-		offset++
-
-		var EBP_distance_width_minus_1 uint8
-		if timescale_flag {
-			return false
-		}
-
-		indx := uint8(0)
-		for indx < num_partitions {
-			indx++
-			EBP_data_explicit_flag := 1 == uint8((descriptor.data[offset]&0x80)>>7)
-			representation_id_flag := 1 == uint8((descriptor.data[offset]&0x04)>>6)
-
-			if EBP_data_explicit_flag {
-				offset++
-				if 0 == EBP_distance_width_minus_1 {
-					EBP_distance := uint8(descriptor.data[offset])
-					return 1 == EBP_distance
-				} else {
-					return false
-				}
-			} else {
-				offset += 2
-			}
-
-			if representation_id_flag {
-				offset += 8
-			}
-		}
+	ebp, err := descriptor.DecodeEBP()
+	if err != nil || ebp.TimescaleFlag {
 		return false
 	}
+	for _, p := range ebp.Partitions {
+		if p.EBPDataExplicitFlag {
+			return p.EBPDistance == 1
+		}
+	}
 	return false
 }
 
@@ -0,0 +1,335 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"encoding/binary"
+
+	"github.com/Comcast/gots/v3"
+)
+
+// The functions in this file create descriptors that the corresponding
+// Decode methods of PmtDescriptor decode to the same values. Reserved bits
+// are set to one, except for reserved_zero_future_use bits which are set to
+// zero. gots.ErrInvalidDescriptor is returned if a field does not
+// fit its width.
+
+// newDescriptor creates a descriptor after checking that data fits within
+// the 8 bit descriptor_length.
+func newDescriptor(tag uint8, data []byte) (PmtDescriptor, error) {
+	if len(data) > 0xFF {
+		return nil, gots.ErrInvalidDescriptor
+	}
+	return NewPmtDescriptor(tag, data), nil
+}
+
+// flagBit returns mask if b is true and zero otherwise.
+func flagBit(b bool, mask byte) byte {
+	if b {
+		return mask
+	}
+	return 0
+}
+
+// NewIso639LanguageDescriptor creates an ISO_639_language_descriptor with
+// an entry for every language. Language codes must be three characters.
+func NewIso639LanguageDescriptor(languages ...Iso639Language) (PmtDescriptor, error) {
+	data := make([]byte, 0, 4*len(languages))
+	for _, l := range languages {
+		if len(l.Code) != 3 {
+			return nil, gots.ErrInvalidDescriptor
+		}
+		data = append(data, l.Code...)
+		data = append(data, byte(l.AudioType))
+	}
+	return newDescriptor(LANGUAGE, data)
+}
+
+// NewRegistrationDescriptor creates a registration_descriptor. The format
+// identifier must be four characters.
+func NewRegistrationDescriptor(r RegistrationDescriptor) (PmtDescriptor, error) {
+	if len(r.FormatIdentifier) != 4 {
+		return nil, gots.ErrInvalidDescriptor
+	}
+	data := append([]byte(r.FormatIdentifier), r.AdditionalInfo...)
+	return newDescriptor(REGISTRATION, data)
+}
+
+// NewMaximumBitrateDescriptor creates a maximum_bitrate_descriptor. The
+// bitrate is in units of 50 bytes per second and must fit in 22 bits.
+func NewMaximumBitrateDescriptor(bitrate uint32) (PmtDescriptor, error) {
+	if bitrate > 0x3FFFFF {
+		return nil, gots.ErrInvalidDescriptor
+	}
+	data := []byte{0xC0 | byte(bitrate>>16), byte(bitrate >> 8), byte(bitrate)}
+	return NewPmtDescriptor(MAXIMUM_BITRATE, data), nil
+}
+
+// NewCADescriptor creates a CA_descriptor.
+func NewCADescriptor(ca CA) (PmtDescriptor, error) {
+	if ca.PID < 0 || ca.PID > 0x1FFF {
+		return nil, gots.ErrInvalidDescriptor
+	}
+	data := []byte{byte(ca.SystemID >> 8), byte(ca.SystemID), 0xE0 | byte(ca.PID>>8), byte(ca.PID)}
+	return newDescriptor(CONDITIONAL_ACCESS, append(data, ca.PrivateData...))
+}
+
+// NewTTMLSubtitlingDescriptor creates a TTML_subtitling_descriptor with the
+// given language code, subtitle purpose and up to 15 DVB TTML profiles.
+// TTS_suitability is always zero (unknown), and the descriptor carries no
+// font usage, qualifier or service name.
+func NewTTMLSubtitlingDescriptor(language string, purpose uint8, profiles ...uint8) (PmtDescriptor, error) {
+	if len(language) != 3 || purpose > 0x3F || len(profiles) > 0x0F {
+		return nil, gots.ErrInvalidDescriptor
+	}
+	data := []byte{TTML_DESC_TAG_EXTENSION}
+	data = append(data, language...)
+	// TTS_suitability, essential_font_usage_flag, qualifier_present_flag and
+	// reserved_zero_future_use are zero
+	data = append(data, purpose<<2, byte(len(profiles)))
+	data = append(data, profiles...)
+	// text_length
+	data = append(data, 0)
+	return NewPmtDescriptor(EXTENSION, data), nil
+}
+
+// NewAVCVideoDescriptor creates an AVC_video_descriptor.
+func NewAVCVideoDescriptor(d AVCVideoDescriptor) (PmtDescriptor, error) {
+	return NewPmtDescriptor(AVC_VIDEO, []byte{
+		d.ProfileIDC,
+		d.ConstraintSetFlags,
+		d.LevelIDC,
+		flagBit(d.StillPresent, 0x80) | flagBit(d.Picture24Hour, 0x40) |
+			flagBit(d.FramePackingSEINotPresent, 0x20) | 0x1F,
+	}), nil
+}
+
+// NewHEVCVideoDescriptor creates an HEVC_video_descriptor. The
+// copied_44bits are set to zero.
+func NewHEVCVideoDescriptor(d HEVCVideoDescriptor) (PmtDescriptor, error) {
+	if d.ProfileSpace > 0x03 || d.ProfileIDC > 0x1F || d.HDRWCGIdc > 0x03 {
+		return nil, gots.ErrInvalidDescriptor
+	}
+	if d.TemporalLayerSubset && (d.TemporalIDMin > 0x07 || d.TemporalIDMax > 0x07) {
+		return nil, gots.ErrInvalidDescriptor
+	}
+	data := make([]byte, 13, 15)
+	data[0] = d.ProfileSpace<<6 | flagBit(d.TierFlag, 0x20) | d.ProfileIDC
+	binary.BigEndian.PutUint32(data[1:5], d.ProfileCompatibilityIndication)
+	data[5] = flagBit(d.ProgressiveSource, 0x80) | flagBit(d.InterlacedSource, 0x40) |
+		flagBit(d.NonPackedConstraint, 0x20) | flagBit(d.FrameOnlyConstraint, 0x10)
+	data[11] = d.LevelIDC
+	data[12] = flagBit(d.TemporalLayerSubset, 0x80) | flagBit(d.StillPresent, 0x40) |
+		flagBit(d.Picture24HourPresent, 0x20) | flagBit(d.SubPicHRDParamsNotPresent, 0x10) |
+		0x0C | d.HDRWCGIdc
+	if d.TemporalLayerSubset {
+		data = append(data, d.TemporalIDMin<<5|0x1F, d.TemporalIDMax<<5|0x1F)
+	}
+	return NewPmtDescriptor(HEVC_VIDEO, data), nil
+}
+
+// NewMPEG4AudioDescriptor creates an MPEG-4_audio_descriptor.
+func NewMPEG4AudioDescriptor(profileAndLevel uint8) (PmtDescriptor, error) {
+	return NewPmtDescriptor(MPEG4_AUDIO, []byte{profileAndLevel}), nil
+}
+
+// NewMPEG2AACDescriptor creates an MPEG-2_AAC_audio_descriptor.
+func NewMPEG2AACDescriptor(d MPEG2AACDescriptor) (PmtDescriptor, error) {
+	return NewPmtDescriptor(MPEG2_AAC_AUDIO, []byte{d.Profile, d.ChannelConfiguration, d.AdditionalInformation}), nil
+}
+
+// NewAACDescriptor creates a DVB AAC_descriptor. The flags byte is omitted
+// if no field after the profile and level is set.
+func NewAACDescriptor(d AACDescriptor) (PmtDescriptor, error) {
+	data := []byte{d.ProfileAndLevel}
+	if d.HasAACType || d.SAOCDE || len(d.AdditionalInfo) > 0 {
+		data = append(data, flagBit(d.HasAACType, 0x80)|flagBit(d.SAOCDE, 0x40))
+		if d.HasAACType {
+			data = append(data, d.AACType)
+		}
+		data = append(data, d.AdditionalInfo...)
+	}
+	return newDescriptor(DVB_AAC, data)
+}
+
+// NewAC3Descriptor creates an ATSC AC-3_audio_stream_descriptor holding
+// only the fixed fields.
+func NewAC3Descriptor(d AC3Descriptor) (PmtDescriptor, error) {
+	if d.SampleRateCode > 0x07 || d.BSID > 0x1F || d.BitRateCode > 0x3F ||
+		d.SurroundMode > 0x03 || d.BSMod > 0x07 || d.NumChannels > 0x0F {
+		return nil, gots.ErrInvalidDescriptor
+	}
+	return NewPmtDescriptor(AC3_AUDIO, []byte{
+		d.SampleRateCode<<5 | d.BSID,
+		d.BitRateCode<<2 | d.SurroundMode,
+		d.BSMod<<5 | d.NumChannels<<1 | flagBit(d.FullService, 0x01),
+	}), nil
+}
+
+// NewDVBAC3Descriptor creates a DVB AC-3_descriptor.
+func NewDVBAC3Descriptor(d DVBAC3Descriptor) (PmtDescriptor, error) {
+	data := []byte{flagBit(d.HasComponentType, 0x80) | flagBit(d.HasBSID, 0x40) |
+		flagBit(d.HasMainID, 0x20) | flagBit(d.HasASVC, 0x10)}
+	if d.HasComponentType {
+		data = append(data, d.ComponentType)
+	}
+	if d.HasBSID {
+		data = append(data, d.BSID)
+	}
+	if d.HasMainID {
+		data = append(data, d.MainID)
+	}
+	if d.HasASVC {
+		data = append(data, d.ASVC)
+	}
+	return newDescriptor(DVB_AC3, append(data, d.AdditionalInfo...))
+}
+
+// NewDataStreamAlignmentDescriptor creates a
+// data_stream_alignment_descriptor.
+func NewDataStreamAlignmentDescriptor(alignmentType uint8) (PmtDescriptor, error) {
+	return NewPmtDescriptor(DATA_STREAM_ALIGNMENT, []byte{alignmentType}), nil
+}
+
+// NewVideoStreamDescriptor creates a video_stream_descriptor.
+func NewVideoStreamDescriptor(d VideoStreamDescriptor) (PmtDescriptor, error) {
+	if d.FrameRateCode > 0x0F || d.ChromaFormat > 0x03 {
+		return nil, gots.ErrInvalidDescriptor
+	}
+	data := []byte{flagBit(d.MultipleFrameRate, 0x80) | d.FrameRateCode<<3 |
+		flagBit(d.MPEG1Only, 0x04) | flagBit(d.ConstrainedParameter, 0x02) |
+		flagBit(d.StillPicture, 0x01)}
+	if !d.MPEG1Only {
+		data = append(data, d.ProfileAndLevel,
+			d.ChromaFormat<<6|flagBit(d.FrameRateExtension, 0x20)|0x1F)
+	}
+	return NewPmtDescriptor(VIDEO_STREAM, data), nil
+}
+
+// NewAudioStreamDescriptor creates an audio_stream_descriptor.
+func NewAudioStreamDescriptor(d AudioStreamDescriptor) (PmtDescriptor, error) {
+	if d.ID > 0x01 || d.Layer > 0x03 {
+		return nil, gots.ErrInvalidDescriptor
+	}
+	return NewPmtDescriptor(AUDIO_STREAM, []byte{flagBit(d.FreeFormat, 0x80) |
+		d.ID<<6 | d.Layer<<4 | flagBit(d.VariableRateAudio, 0x08) | 0x07}), nil
+}
+
+// NewEBPDescriptor creates an EBP_descriptor. It fails if there are more
+// than 31 partitions or a field does not fit its width.
+func NewEBPDescriptor(e EBPDescriptor) (PmtDescriptor, error) {
+	if len(e.Partitions) > 0x1F {
+		return nil, gots.ErrInvalidDescriptor
+	}
+	data := []byte{byte(len(e.Partitions))<<3 | flagBit(e.TimescaleFlag, 0x04) | 0x03}
+	width := 1
+	if e.TimescaleFlag {
+		if e.TicksPerSecond > 0x1FFFFF || e.EBPDistanceWidthMinus1 > 0x07 {
+			return nil, gots.ErrInvalidDescriptor
+		}
+		v := e.TicksPerSecond<<3 | uint32(e.EBPDistanceWidthMinus1)
+		data = append(data, byte(v>>16), byte(v>>8), byte(v))
+		width = int(e.EBPDistanceWidthMinus1) + 1
+	}
+	for _, p := range e.Partitions {
+		if p.PartitionID > 0x1F || p.SAPTypeMax > 0x07 {
+			return nil, gots.ErrInvalidDescriptor
+		}
+		b := flagBit(p.EBPDataExplicitFlag, 0x80) | flagBit(p.RepresentationIDFlag, 0x40) | p.PartitionID<<1
+		if !p.EBPDataExplicitFlag {
+			if p.EBPPID > 0x1FFF {
+				return nil, gots.ErrInvalidDescriptor
+			}
+			data = append(data, b|0x01, byte(p.EBPPID>>5), byte(p.EBPPID<<3)|0x07)
+		} else {
+			if width < 8 && p.EBPDistance>>(8*uint(width)) != 0 {
+				return nil, gots.ErrInvalidDescriptor
+			}
+			data = append(data, b|flagBit(p.BoundaryFlag, 0x01))
+			for i := width - 1; i >= 0; i-- {
+				data = append(data, byte(p.EBPDistance>>(8*uint(i))))
+			}
+			last := byte(0xFE)
+			if p.BoundaryFlag {
+				last = p.SAPTypeMax<<5 | 0x1E
+			}
+			data = append(data, last|flagBit(p.AcquisitionFlag, 0x01))
+		}
+		if p.RepresentationIDFlag {
+			data = binary.BigEndian.AppendUint64(data, p.RepresentationID)
+		}
+	}
+	return newDescriptor(EBP, data)
+}
+
+// NewAV1VideoDescriptor creates an AV1_video_descriptor. A zero Version is
+// encoded as version 1.
+func NewAV1VideoDescriptor(d AV1VideoDescriptor) (PmtDescriptor, error) {
+	if d.Version > 0x7F || d.SeqProfile > 0x07 || d.SeqLevelIdx0 > 0x1F ||
+		d.ChromaSamplePosition > 0x03 || d.HDRWCGIdc > 0x03 {
+		return nil, gots.ErrInvalidDescriptor
+	}
+	version := d.Version
+	if version == 0 {
+		version = 1
+	}
+	last := d.HDRWCGIdc<<6 | flagBit(d.InitialPresentationDelayPresent, 0x10)
+	if d.InitialPresentationDelayPresent {
+		if d.InitialPresentationDelayMinusOne > 0x0F {
+			return nil, gots.ErrInvalidDescriptor
+		}
+		last |= d.InitialPresentationDelayMinusOne
+	}
+	return NewPmtDescriptor(AV1_VIDEO, []byte{
+		0x80 | version,
+		d.SeqProfile<<5 | d.SeqLevelIdx0,
+		flagBit(d.SeqTier0, 0x80) | flagBit(d.HighBitdepth, 0x40) | flagBit(d.TwelveBit, 0x20) |
+			flagBit(d.Monochrome, 0x10) | flagBit(d.ChromaSubsamplingX, 0x08) |
+			flagBit(d.ChromaSubsamplingY, 0x04) | d.ChromaSamplePosition,
+		last,
+	}), nil
+}
+
+// NewAC4Descriptor creates a DVB AC-4_descriptor. The TOC is included if it
+// is not nil.
+func NewAC4Descriptor(d AC4Descriptor) (PmtDescriptor, error) {
+	if len(d.TOC) > 0xFF || d.ChannelMode > 0x03 {
+		return nil, gots.ErrInvalidDescriptor
+	}
+	data := []byte{AC4_DESC_TAG_EXTENSION, flagBit(d.ConfigFlag, 0x80) | flagBit(d.TOC != nil, 0x40)}
+	if d.ConfigFlag {
+		data = append(data, flagBit(d.DialogEnhancementEnabled, 0x80)|d.ChannelMode<<5)
+	}
+	if d.TOC != nil {
+		data = append(data, byte(len(d.TOC)))
+		data = append(data, d.TOC...)
+	}
+	return newDescriptor(EXTENSION, append(data, d.AdditionalInfo...))
+}
+
+// NewOpusDescriptor creates a DVB Opus audio descriptor.
+func NewOpusDescriptor(channelConfig uint8) (PmtDescriptor, error) {
+	return NewPmtDescriptor(EXTENSION, []byte{OPUS_DESC_TAG_EXTENSION, channelConfig}), nil
+}
@@ -0,0 +1,334 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/Comcast/gots/v3"
+)
+
+func TestNewIso639LanguageDescriptor(t *testing.T) {
+	languages := []Iso639Language{{"eng", AUDIO_UNDEFINED}, {"fra", AUDIO_HEARING_IMPAIRED}}
+	d, err := NewIso639LanguageDescriptor(languages...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := d.DecodeIso639Languages(); !reflect.DeepEqual(got, languages) {
+		t.Errorf("DecodeIso639Languages() = %v, want %v", got, languages)
+	}
+	if _, err := NewIso639LanguageDescriptor(Iso639Language{Code: "en"}); err != gots.ErrInvalidDescriptor {
+		t.Errorf("expected ErrInvalidDescriptor, got %v", err)
+	}
+}
+
+func TestNewRegistrationDescriptor(t *testing.T) {
+	r := RegistrationDescriptor{FormatIdentifier: "CUEI", AdditionalInfo: []byte{}}
+	d, err := NewRegistrationDescriptor(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.IsSCTE35Registration() {
+		t.Error("expected a SCTE-35 registration")
+	}
+	if got, _ := d.DecodeRegistration(); !reflect.DeepEqual(got, r) {
+		t.Errorf("DecodeRegistration() = %+v, want %+v", got, r)
+	}
+	if _, err := NewRegistrationDescriptor(RegistrationDescriptor{FormatIdentifier: "AC-3", AdditionalInfo: make([]byte, 252)}); err != gots.ErrInvalidDescriptor {
+		t.Errorf("expected ErrInvalidDescriptor, got %v", err)
+	}
+}
+
+func TestNewMaximumBitrateDescriptor(t *testing.T) {
+	d, err := NewMaximumBitrateDescriptor(0x2ABCDE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(d.Data(), []byte{0xEA, 0xBC, 0xDE}) {
+		t.Errorf("unexpected data %X", d.Data())
+	}
+	if got := d.DecodeMaximumBitRate(); got != 0x2ABCDE {
+		t.Errorf("DecodeMaximumBitRate() = %X", got)
+	}
+	if got := NewPmtDescriptor(MAXIMUM_BITRATE, []byte{0xC0, 0x01}).DecodeMaximumBitRate(); got != 0 {
+		t.Errorf("DecodeMaximumBitRate() of a short descriptor = %X", got)
+	}
+	if _, err := NewMaximumBitrateDescriptor(0x400000); err != gots.ErrInvalidDescriptor {
+		t.Errorf("expected ErrInvalidDescriptor, got %v", err)
+	}
+}
+
+func TestNewCADescriptor(t *testing.T) {
+	ca := CA{SystemID: 0x0B00, PID: 0x1234, PrivateData: []byte{0x01}}
+	d, err := NewCADescriptor(ca)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := DecodeCADescriptor(d); !reflect.DeepEqual(got, ca) {
+		t.Errorf("DecodeCADescriptor() = %+v, want %+v", got, ca)
+	}
+}
+
+func TestNewTTMLSubtitlingDescriptor(t *testing.T) {
+	d, err := NewTTMLSubtitlingDescriptor("deu", TTML_PURPOSE_HARD_OF_HEARING, 0x01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.IsTTMLSubtitlingDescriptor() || !d.IsTTMLDescTagExtension() {
+		t.Error("expected a TTML subtitling descriptor")
+	}
+	if d.DecodeTTMLIso639LanguageCode() != "deu" || d.DecodeTTMLSubtitlePurpose() != TTML_PURPOSE_HARD_OF_HEARING {
+		t.Errorf("unexpected language %q or purpose %d", d.DecodeTTMLIso639LanguageCode(), d.DecodeTTMLSubtitlePurpose())
+	}
+	want := []byte{TTML_DESC_TAG_EXTENSION, 'd', 'e', 'u', 0x40, 0x01, 0x01, 0x00}
+	if !bytes.Equal(d.Data(), want) {
+		t.Errorf("unexpected data %X, want %X", d.Data(), want)
+	}
+}
+
+func TestNewCodecDescriptors(t *testing.T) {
+	must := func(d PmtDescriptor, err error) PmtDescriptor {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	avc := AVCVideoDescriptor{ProfileIDC: 100, ConstraintSetFlags: 0x0C, LevelIDC: 41, Picture24Hour: true}
+	if got, err := must(NewAVCVideoDescriptor(avc)).DecodeAVCVideo(); err != nil || got != avc {
+		t.Errorf("AVC round trip = %+v, %v", got, err)
+	}
+
+	hevc := HEVCVideoDescriptor{
+		ProfileSpace: 1, ProfileIDC: 2, ProfileCompatibilityIndication: 0x60000000,
+		ProgressiveSource: true, FrameOnlyConstraint: true, LevelIDC: 150,
+		StillPresent: true, HDRWCGIdc: 1,
+		TemporalLayerSubset: true, TemporalIDMin: 0, TemporalIDMax: 6,
+	}
+	if got, err := must(NewHEVCVideoDescriptor(hevc)).DecodeHEVCVideo(); err != nil || got != hevc {
+		t.Errorf("HEVC round trip = %+v, %v", got, err)
+	}
+	if data := must(NewHEVCVideoDescriptor(hevc)).Data(); !bytes.Equal(data[13:], []byte{0x1F, 0xDF}) {
+		t.Errorf("Unexpected HEVC temporal ids %X", data[13:])
+	}
+
+	if got, err := must(NewMPEG4AudioDescriptor(0x52)).DecodeMPEG4Audio(); err != nil || got != 0x52 {
+		t.Errorf("MPEG-4 audio round trip = %X, %v", got, err)
+	}
+
+	mpeg2AAC := MPEG2AACDescriptor{Profile: 1, ChannelConfiguration: 6, AdditionalInformation: 0}
+	if got, err := must(NewMPEG2AACDescriptor(mpeg2AAC)).DecodeMPEG2AAC(); err != nil || got != mpeg2AAC {
+		t.Errorf("MPEG-2 AAC round trip = %+v, %v", got, err)
+	}
+
+	for _, aac := range []AACDescriptor{
+		{ProfileAndLevel: 0x58},
+		{ProfileAndLevel: 0x51, HasAACType: true, AACType: 0x05, AdditionalInfo: []byte{}},
+	} {
+		d, err := NewAACDescriptor(aac)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := d.DecodeAAC(); err != nil || !reflect.DeepEqual(got, aac) {
+			t.Errorf("AAC round trip = %+v, %v, want %+v", got, err, aac)
+		}
+	}
+
+	ac3 := AC3Descriptor{SampleRateCode: 1, BSID: 8, BitRateCode: 14, SurroundMode: 2, BSMod: 1, NumChannels: 2, FullService: true}
+	if got, err := must(NewAC3Descriptor(ac3)).DecodeAC3(); err != nil || got != ac3 {
+		t.Errorf("AC-3 round trip = %+v, %v", got, err)
+	}
+
+	dvbAC3 := DVBAC3Descriptor{HasBSID: true, BSID: 6, HasASVC: true, ASVC: 0x11, AdditionalInfo: []byte{}}
+	d, err := NewDVBAC3Descriptor(dvbAC3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := d.DecodeDVBAC3(); err != nil || !reflect.DeepEqual(got, dvbAC3) {
+		t.Errorf("DVB AC-3 round trip = %+v, %v", got, err)
+	}
+
+	if got, err := must(NewDataStreamAlignmentDescriptor(0x01)).DecodeDataStreamAlignment(); err != nil || got != 1 {
+		t.Errorf("data stream alignment round trip = %d, %v", got, err)
+	}
+
+	for _, video := range []VideoStreamDescriptor{
+		{FrameRateCode: 3, ProfileAndLevel: 0x44, ChromaFormat: 1, FrameRateExtension: true},
+		{MultipleFrameRate: true, FrameRateCode: 5, MPEG1Only: true, StillPicture: true},
+	} {
+		if got, err := must(NewVideoStreamDescriptor(video)).DecodeVideoStream(); err != nil || got != video {
+			t.Errorf("video stream round trip = %+v, %v, want %+v", got, err, video)
+		}
+	}
+
+	audio := AudioStreamDescriptor{ID: 1, Layer: 2, VariableRateAudio: true}
+	if got, err := must(NewAudioStreamDescriptor(audio)).DecodeAudioStream(); err != nil || got != audio {
+		t.Errorf("audio stream round trip = %+v, %v", got, err)
+	}
+}
+
+func TestNewDescriptorsOutOfRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		create func() (PmtDescriptor, error)
+	}{
+		{"HEVC profile space", func() (PmtDescriptor, error) { return NewHEVCVideoDescriptor(HEVCVideoDescriptor{ProfileSpace: 4}) }},
+		{"HEVC profile", func() (PmtDescriptor, error) { return NewHEVCVideoDescriptor(HEVCVideoDescriptor{ProfileIDC: 0x20}) }},
+		{"HEVC HDR WCG", func() (PmtDescriptor, error) { return NewHEVCVideoDescriptor(HEVCVideoDescriptor{HDRWCGIdc: 4}) }},
+		{"HEVC temporal id", func() (PmtDescriptor, error) {
+			return NewHEVCVideoDescriptor(HEVCVideoDescriptor{TemporalLayerSubset: true, TemporalIDMin: 8})
+		}},
+		{"AC-3 sample rate", func() (PmtDescriptor, error) { return NewAC3Descriptor(AC3Descriptor{SampleRateCode: 8}) }},
+		{"AC-3 bsid", func() (PmtDescriptor, error) { return NewAC3Descriptor(AC3Descriptor{BSID: 0x20}) }},
+		{"AC-3 bit rate", func() (PmtDescriptor, error) { return NewAC3Descriptor(AC3Descriptor{BitRateCode: 0x40}) }},
+		{"AC-3 surround mode", func() (PmtDescriptor, error) { return NewAC3Descriptor(AC3Descriptor{SurroundMode: 4}) }},
+		{"AC-3 bsmod", func() (PmtDescriptor, error) { return NewAC3Descriptor(AC3Descriptor{BSMod: 8}) }},
+		{"AC-3 channels", func() (PmtDescriptor, error) { return NewAC3Descriptor(AC3Descriptor{NumChannels: 0x10}) }},
+		{"video frame rate", func() (PmtDescriptor, error) {
+			return NewVideoStreamDescriptor(VideoStreamDescriptor{FrameRateCode: 0x10})
+		}},
+		{"video chroma format", func() (PmtDescriptor, error) { return NewVideoStreamDescriptor(VideoStreamDescriptor{ChromaFormat: 4}) }},
+		{"audio id", func() (PmtDescriptor, error) { return NewAudioStreamDescriptor(AudioStreamDescriptor{ID: 2}) }},
+		{"audio layer", func() (PmtDescriptor, error) { return NewAudioStreamDescriptor(AudioStreamDescriptor{Layer: 4}) }},
+		{"AV1 version", func() (PmtDescriptor, error) { return NewAV1VideoDescriptor(AV1VideoDescriptor{Version: 0x80}) }},
+		{"AV1 profile", func() (PmtDescriptor, error) { return NewAV1VideoDescriptor(AV1VideoDescriptor{SeqProfile: 8}) }},
+		{"AV1 level", func() (PmtDescriptor, error) { return NewAV1VideoDescriptor(AV1VideoDescriptor{SeqLevelIdx0: 0x20}) }},
+		{"AV1 chroma sample position", func() (PmtDescriptor, error) {
+			return NewAV1VideoDescriptor(AV1VideoDescriptor{ChromaSamplePosition: 4})
+		}},
+		{"AV1 HDR WCG", func() (PmtDescriptor, error) { return NewAV1VideoDescriptor(AV1VideoDescriptor{HDRWCGIdc: 4}) }},
+		{"AV1 presentation delay", func() (PmtDescriptor, error) {
+			return NewAV1VideoDescriptor(AV1VideoDescriptor{InitialPresentationDelayPresent: true, InitialPresentationDelayMinusOne: 0x10})
+		}},
+		{"AC-4 channel mode", func() (PmtDescriptor, error) {
+			return NewAC4Descriptor(AC4Descriptor{ConfigFlag: true, ChannelMode: 4})
+		}},
+		{"EBP SAP type", func() (PmtDescriptor, error) {
+			return NewEBPDescriptor(EBPDescriptor{Partitions: []EBPPartition{{EBPDataExplicitFlag: true, BoundaryFlag: true, SAPTypeMax: 8}}})
+		}},
+	}
+	for _, test := range tests {
+		if d, err := test.create(); err != gots.ErrInvalidDescriptor {
+			t.Errorf("%s: expected ErrInvalidDescriptor, got %v, %v", test.name, d, err)
+		}
+	}
+}
+
+func TestNewEBPDescriptor(t *testing.T) {
+	e := EBPDescriptor{
+		Partitions: []EBPPartition{
+			{EBPDataExplicitFlag: true, PartitionID: 1, EBPDistance: 1},
+			{PartitionID: 2, EBPPID: 0x0101},
+		},
+	}
+	d, err := NewEBPDescriptor(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{0x13, 0x82, 0x01, 0xFE, 0x05, 0x08, 0x0F}
+	if !bytes.Equal(d.Data(), want) {
+		t.Errorf("unexpected data %X, want %X", d.Data(), want)
+	}
+	if got, err := d.DecodeEBP(); err != nil || !reflect.DeepEqual(got, e) {
+		t.Errorf("EBP round trip = %+v, %v", got, err)
+	}
+	if !d.IsEBPDescriptor() || !d.IsIFrameProfile() {
+		t.Error("expected an I-frame EBP descriptor")
+	}
+
+	e = EBPDescriptor{
+		TimescaleFlag:          true,
+		TicksPerSecond:         90000,
+		EBPDistanceWidthMinus1: 2,
+		Partitions: []EBPPartition{
+			{EBPDataExplicitFlag: true, RepresentationIDFlag: true, PartitionID: 2,
+				BoundaryFlag: true, EBPDistance: 180000, SAPTypeMax: 2, AcquisitionFlag: true,
+				RepresentationID: 0x0102030405060708},
+		},
+	}
+	d, err = NewEBPDescriptor(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []byte{0x0F, 0x0A, 0xFC, 0x82, 0xC5, 0x02, 0xBF, 0x20, 0x5F,
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}
+	if !bytes.Equal(d.Data(), want) {
+		t.Errorf("unexpected data %X, want %X", d.Data(), want)
+	}
+	if got, err := d.DecodeEBP(); err != nil || !reflect.DeepEqual(got, e) {
+		t.Errorf("EBP round trip = %+v, %v", got, err)
+	}
+
+	if _, err := NewEBPDescriptor(EBPDescriptor{Partitions: []EBPPartition{{EBPDataExplicitFlag: true, EBPDistance: 256}}}); err != gots.ErrInvalidDescriptor {
+		t.Errorf("expected ErrInvalidDescriptor, got %v", err)
+	}
+}
+
+func TestNewModernCodecDescriptors(t *testing.T) {
+	av1 := AV1VideoDescriptor{
+		Version: 1, SeqProfile: 1, SeqLevelIdx0: 13, HighBitdepth: true,
+		ChromaSubsamplingX: true, ChromaSubsamplingY: true, HDRWCGIdc: 1,
+		InitialPresentationDelayPresent: true, InitialPresentationDelayMinusOne: 3,
+	}
+	d, err := NewAV1VideoDescriptor(av1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(d.Data(), []byte{0x81, 0x2D, 0x4C, 0x53}) {
+		t.Errorf("unexpected AV1 descriptor data %X", d.Data())
+	}
+	if got, err := d.DecodeAV1Video(); err != nil || got != av1 {
+		t.Errorf("AV1 round trip = %+v, %v", got, err)
+	}
+
+	ac4 := AC4Descriptor{ConfigFlag: true, ChannelMode: 2, TOC: []byte{0x01, 0x02}, AdditionalInfo: []byte{}}
+	d, err = NewAC4Descriptor(ac4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.IsExtensionDescriptor(AC4_DESC_TAG_EXTENSION) || d.IsTTMLDescTagExtension() {
+		t.Error("expected an AC-4 extension descriptor")
+	}
+	if got, err := d.DecodeAC4(); err != nil || !reflect.DeepEqual(got, ac4) {
+		t.Errorf("AC-4 round trip = %+v, %v", got, err)
+	}
+	if s := fmt.Sprint(d); s != "AC-4" {
+		t.Errorf("unexpected string %q", s)
+	}
+
+	opus, err := NewOpusDescriptor(0x81)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := opus.DecodeOpusChannelConfig(); err != nil || got != 0x81 {
+		t.Errorf("Opus round trip = %X, %v", got, err)
+	}
+	if _, err := d.DecodeOpusChannelConfig(); err != gots.ErrParsePMTDescriptor {
+		t.Errorf("expected ErrParsePMTDescriptor, got %v", err)
+	}
+}
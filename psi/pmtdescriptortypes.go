@@ -0,0 +1,558 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"encoding/binary"
+
+	"github.com/Comcast/gots/v3"
+)
+
+// RegistrationDescriptor is a decoded registration_descriptor.
+type RegistrationDescriptor struct {
+	// FormatIdentifier is the four character code registered with the
+	// SMPTE Registration Authority, such as "CUEI" or "AC-3".
+	FormatIdentifier string
+	AdditionalInfo   []byte
+}
+
+// AVCVideoDescriptor is a decoded AVC_video_descriptor.
+type AVCVideoDescriptor struct {
+	ProfileIDC uint8
+	// ConstraintSetFlags holds constraint_set0_flag to constraint_set5_flag
+	// in its six most significant bits followed by AVC_compatible_flags.
+	ConstraintSetFlags        uint8
+	LevelIDC                  uint8
+	StillPresent              bool
+	Picture24Hour             bool
+	FramePackingSEINotPresent bool
+}
+
+// ConstraintSet returns constraint_setN_flag for N from 0 to 5.
+func (d AVCVideoDescriptor) ConstraintSet(n int) bool {
+	return n >= 0 && n <= 5 && d.ConstraintSetFlags&(0x80>>uint(n)) != 0
+}
+
+// HEVCVideoDescriptor is a decoded HEVC_video_descriptor.
+type HEVCVideoDescriptor struct {
+	ProfileSpace                   uint8
+	TierFlag                       bool
+	ProfileIDC                     uint8
+	ProfileCompatibilityIndication uint32
+	ProgressiveSource              bool
+	InterlacedSource               bool
+	NonPackedConstraint            bool
+	FrameOnlyConstraint            bool
+	LevelIDC                       uint8
+	StillPresent                   bool
+	Picture24HourPresent           bool
+	SubPicHRDParamsNotPresent      bool
+	HDRWCGIdc                      uint8
+	// TemporalLayerSubset is true if TemporalIDMin and TemporalIDMax are
+	// present.
+	TemporalLayerSubset bool
+	TemporalIDMin       uint8
+	TemporalIDMax       uint8
+}
+
+// MPEG2AACDescriptor is a decoded MPEG-2_AAC_audio_descriptor.
+type MPEG2AACDescriptor struct {
+	Profile               uint8
+	ChannelConfiguration  uint8
+	AdditionalInformation uint8
+}
+
+// AACDescriptor is a decoded DVB AAC_descriptor.
+type AACDescriptor struct {
+	ProfileAndLevel uint8
+	SAOCDE          bool
+	// HasAACType is true if AACType is present.
+	HasAACType     bool
+	AACType        uint8
+	AdditionalInfo []byte
+}
+
+// AC3Descriptor is a decoded ATSC AC-3_audio_stream_descriptor. Only the
+// fixed fields are decoded.
+type AC3Descriptor struct {
+	SampleRateCode uint8
+	BSID           uint8
+	BitRateCode    uint8
+	SurroundMode   uint8
+	BSMod          uint8
+	NumChannels    uint8
+	FullService    bool
+}
+
+// DVBAC3Descriptor is a decoded DVB AC-3_descriptor. The flags indicate
+// which of the optional fields are present.
+type DVBAC3Descriptor struct {
+	HasComponentType bool
+	ComponentType    uint8
+	HasBSID          bool
+	BSID             uint8
+	HasMainID        bool
+	MainID           uint8
+	HasASVC          bool
+	ASVC             uint8
+	AdditionalInfo   []byte
+}
+
+// VideoStreamDescriptor is a decoded video_stream_descriptor.
+type VideoStreamDescriptor struct {
+	MultipleFrameRate    bool
+	FrameRateCode        uint8
+	MPEG1Only            bool
+	ConstrainedParameter bool
+	StillPicture         bool
+	ProfileAndLevel      uint8
+	ChromaFormat         uint8
+	FrameRateExtension   bool
+}
+
+// AudioStreamDescriptor is a decoded audio_stream_descriptor.
+type AudioStreamDescriptor struct {
+	FreeFormat        bool
+	ID                uint8
+	Layer             uint8
+	VariableRateAudio bool
+}
+
+// check returns the descriptor data if the descriptor has the given tag and
+// at least n bytes of data.
+func (descriptor *pmtDescriptor) check(tag uint8, n int) ([]byte, error) {
+	if descriptor.tag != tag || len(descriptor.data) < n {
+		return nil, gots.ErrParsePMTDescriptor
+	}
+	return descriptor.data, nil
+}
+
+// RawData returns the complete descriptor including the tag and length.
+func (descriptor *pmtDescriptor) RawData() []byte {
+	return append([]byte{descriptor.tag, byte(len(descriptor.data))}, descriptor.data...)
+}
+
+// DecodeRegistration decodes a registration_descriptor.
+func (descriptor *pmtDescriptor) DecodeRegistration() (RegistrationDescriptor, error) {
+	data, err := descriptor.check(REGISTRATION, 4)
+	if err != nil {
+		return RegistrationDescriptor{}, err
+	}
+	return RegistrationDescriptor{
+		FormatIdentifier: string(data[0:4]),
+		AdditionalInfo:   data[4:],
+	}, nil
+}
+
+// DecodeAVCVideo decodes an AVC_video_descriptor.
+func (descriptor *pmtDescriptor) DecodeAVCVideo() (AVCVideoDescriptor, error) {
+	data, err := descriptor.check(AVC_VIDEO, 4)
+	if err != nil {
+		return AVCVideoDescriptor{}, err
+	}
+	return AVCVideoDescriptor{
+		ProfileIDC:                data[0],
+		ConstraintSetFlags:        data[1],
+		LevelIDC:                  data[2],
+		StillPresent:              data[3]&0x80 != 0,
+		Picture24Hour:             data[3]&0x40 != 0,
+		FramePackingSEINotPresent: data[3]&0x20 != 0,
+	}, nil
+}
+
+// DecodeHEVCVideo decodes an HEVC_video_descriptor.
+func (descriptor *pmtDescriptor) DecodeHEVCVideo() (HEVCVideoDescriptor, error) {
+	data, err := descriptor.check(HEVC_VIDEO, 13)
+	if err != nil {
+		return HEVCVideoDescriptor{}, err
+	}
+	d := HEVCVideoDescriptor{
+		ProfileSpace:                   data[0] >> 6,
+		TierFlag:                       data[0]&0x20 != 0,
+		ProfileIDC:                     data[0] & 0x1F,
+		ProfileCompatibilityIndication: binary.BigEndian.Uint32(data[1:5]),
+		ProgressiveSource:              data[5]&0x80 != 0,
+		InterlacedSource:               data[5]&0x40 != 0,
+		NonPackedConstraint:            data[5]&0x20 != 0,
+		FrameOnlyConstraint:            data[5]&0x10 != 0,
+		LevelIDC:                       data[11],
+		TemporalLayerSubset:            data[12]&0x80 != 0,
+		StillPresent:                   data[12]&0x40 != 0,
+		Picture24HourPresent:           data[12]&0x20 != 0,
+		SubPicHRDParamsNotPresent:      data[12]&0x10 != 0,
+		HDRWCGIdc:                      data[12] & 0x03,
+	}
+	if d.TemporalLayerSubset {
+		if len(data) < 15 {
+			return HEVCVideoDescriptor{}, gots.ErrParsePMTDescriptor
+		}
+		d.TemporalIDMin = data[13] >> 5
+		d.TemporalIDMax = data[14] >> 5
+	}
+	return d, nil
+}
+
+// DecodeMPEG4Audio decodes an MPEG-4_audio_descriptor and returns its
+// MPEG-4_audio_profile_and_level.
+func (descriptor *pmtDescriptor) DecodeMPEG4Audio() (uint8, error) {
+	data, err := descriptor.check(MPEG4_AUDIO, 1)
+	if err != nil {
+		return 0, err
+	}
+	return data[0], nil
+}
+
+// DecodeMPEG2AAC decodes an MPEG-2_AAC_audio_descriptor.
+func (descriptor *pmtDescriptor) DecodeMPEG2AAC() (MPEG2AACDescriptor, error) {
+	data, err := descriptor.check(MPEG2_AAC_AUDIO, 3)
+	if err != nil {
+		return MPEG2AACDescriptor{}, err
+	}
+	return MPEG2AACDescriptor{
+		Profile:               data[0],
+		ChannelConfiguration:  data[1],
+		AdditionalInformation: data[2],
+	}, nil
+}
+
+// DecodeAAC decodes a DVB AAC_descriptor.
+func (descriptor *pmtDescriptor) DecodeAAC() (AACDescriptor, error) {
+	data, err := descriptor.check(DVB_AAC, 1)
+	if err != nil {
+		return AACDescriptor{}, err
+	}
+	d := AACDescriptor{ProfileAndLevel: data[0]}
+	if len(data) < 2 {
+		return d, nil
+	}
+	d.HasAACType = data[1]&0x80 != 0
+	d.SAOCDE = data[1]&0x40 != 0
+	data = data[2:]
+	if d.HasAACType {
+		if len(data) < 1 {
+			return AACDescriptor{}, gots.ErrParsePMTDescriptor
+		}
+		d.AACType = data[0]
+		data = data[1:]
+	}
+	d.AdditionalInfo = data
+	return d, nil
+}
+
+// DecodeAC3 decodes an ATSC AC-3_audio_stream_descriptor.
+func (descriptor *pmtDescriptor) DecodeAC3() (AC3Descriptor, error) {
+	data, err := descriptor.check(AC3_AUDIO, 3)
+	if err != nil {
+		return AC3Descriptor{}, err
+	}
+	return AC3Descriptor{
+		SampleRateCode: data[0] >> 5,
+		BSID:           data[0] & 0x1F,
+		BitRateCode:    data[1] >> 2,
+		SurroundMode:   data[1] & 0x03,
+		BSMod:          data[2] >> 5,
+		NumChannels:    data[2] >> 1 & 0x0F,
+		FullService:    data[2]&0x01 != 0,
+	}, nil
+}
+
+// DecodeDVBAC3 decodes a DVB AC-3_descriptor.
+func (descriptor *pmtDescriptor) DecodeDVBAC3() (DVBAC3Descriptor, error) {
+	data, err := descriptor.check(DVB_AC3, 1)
+	if err != nil {
+		return DVBAC3Descriptor{}, err
+	}
+	d := DVBAC3Descriptor{
+		HasComponentType: data[0]&0x80 != 0,
+		HasBSID:          data[0]&0x40 != 0,
+		HasMainID:        data[0]&0x20 != 0,
+		HasASVC:          data[0]&0x10 != 0,
+	}
+	data = data[1:]
+	for _, field := range []struct {
+		present bool
+		value   *uint8
+	}{
+		{d.HasComponentType, &d.ComponentType},
+		{d.HasBSID, &d.BSID},
+		{d.HasMainID, &d.MainID},
+		{d.HasASVC, &d.ASVC},
+	} {
+		if !field.present {
+			continue
+		}
+		if len(data) < 1 {
+			return DVBAC3Descriptor{}, gots.ErrParsePMTDescriptor
+		}
+		*field.value = data[0]
+		data = data[1:]
+	}
+	d.AdditionalInfo = data
+	return d, nil
+}
+
+// DecodeDataStreamAlignment decodes a data_stream_alignment_descriptor and
+// returns its alignment_type.
+func (descriptor *pmtDescriptor) DecodeDataStreamAlignment() (uint8, error) {
+	data, err := descriptor.check(DATA_STREAM_ALIGNMENT, 1)
+	if err != nil {
+		return 0, err
+	}
+	return data[0], nil
+}
+
+// DecodeVideoStream decodes a video_stream_descriptor.
+func (descriptor *pmtDescriptor) DecodeVideoStream() (VideoStreamDescriptor, error) {
+	data, err := descriptor.check(VIDEO_STREAM, 1)
+	if err != nil {
+		return VideoStreamDescriptor{}, err
+	}
+	d := VideoStreamDescriptor{
+		MultipleFrameRate:    data[0]&0x80 != 0,
+		FrameRateCode:        data[0] >> 3 & 0x0F,
+		MPEG1Only:            data[0]&0x04 != 0,
+		ConstrainedParameter: data[0]&0x02 != 0,
+		StillPicture:         data[0]&0x01 != 0,
+	}
+	if !d.MPEG1Only {
+		if len(data) < 3 {
+			return VideoStreamDescriptor{}, gots.ErrParsePMTDescriptor
+		}
+		d.ProfileAndLevel = data[1]
+		d.ChromaFormat = data[2] >> 6
+		d.FrameRateExtension = data[2]&0x20 != 0
+	}
+	return d, nil
+}
+
+// DecodeAudioStream decodes an audio_stream_descriptor.
+func (descriptor *pmtDescriptor) DecodeAudioStream() (AudioStreamDescriptor, error) {
+	data, err := descriptor.check(AUDIO_STREAM, 1)
+	if err != nil {
+		return AudioStreamDescriptor{}, err
+	}
+	return AudioStreamDescriptor{
+		FreeFormat:        data[0]&0x80 != 0,
+		ID:                data[0] >> 6 & 0x01,
+		Layer:             data[0] >> 4 & 0x03,
+		VariableRateAudio: data[0]&0x08 != 0,
+	}, nil
+}
+
+// EBPDescriptor is an EBP_descriptor as defined in OC-SP-EBP. The number of
+// partitions is the length of Partitions.
+type EBPDescriptor struct {
+	// TimescaleFlag is true if TicksPerSecond and EBPDistanceWidthMinus1
+	// are present. Otherwise EBP distances are in seconds and one byte wide.
+	TimescaleFlag          bool
+	TicksPerSecond         uint32
+	EBPDistanceWidthMinus1 uint8
+	Partitions             []EBPPartition
+}
+
+// EBPPartition is a partition of an EBP_descriptor. EBPPID is present if
+// EBPDataExplicitFlag is false, the remaining EBP fields otherwise.
+type EBPPartition struct {
+	EBPDataExplicitFlag  bool
+	RepresentationIDFlag bool
+	PartitionID          uint8
+	EBPPID               uint16
+	BoundaryFlag         bool
+	EBPDistance          uint64
+	SAPTypeMax           uint8
+	AcquisitionFlag      bool
+	RepresentationID     uint64
+}
+
+// AV1VideoDescriptor is a decoded AV1_video_descriptor as defined in the
+// AOM specification for carriage of AV1 in MPEG-2 transport streams.
+type AV1VideoDescriptor struct {
+	Version              uint8
+	SeqProfile           uint8
+	SeqLevelIdx0         uint8
+	SeqTier0             bool
+	HighBitdepth         bool
+	TwelveBit            bool
+	Monochrome           bool
+	ChromaSubsamplingX   bool
+	ChromaSubsamplingY   bool
+	ChromaSamplePosition uint8
+	HDRWCGIdc            uint8
+	// InitialPresentationDelayPresent is true if
+	// InitialPresentationDelayMinusOne is present.
+	InitialPresentationDelayPresent  bool
+	InitialPresentationDelayMinusOne uint8
+}
+
+// AC4Descriptor is a decoded DVB AC-4_descriptor.
+type AC4Descriptor struct {
+	// ConfigFlag is true if DialogEnhancementEnabled and ChannelMode are
+	// present.
+	ConfigFlag               bool
+	DialogEnhancementEnabled bool
+	ChannelMode              uint8
+	// TOC holds the ac4_dsi_toc bytes, if present.
+	TOC            []byte
+	AdditionalInfo []byte
+}
+
+// DecodeAV1Video decodes an AV1_video_descriptor.
+func (descriptor *pmtDescriptor) DecodeAV1Video() (AV1VideoDescriptor, error) {
+	data, err := descriptor.check(AV1_VIDEO, 4)
+	if err != nil {
+		return AV1VideoDescriptor{}, err
+	}
+	d := AV1VideoDescriptor{
+		Version:                         data[0] & 0x7F,
+		SeqProfile:                      data[1] >> 5,
+		SeqLevelIdx0:                    data[1] & 0x1F,
+		SeqTier0:                        data[2]&0x80 != 0,
+		HighBitdepth:                    data[2]&0x40 != 0,
+		TwelveBit:                       data[2]&0x20 != 0,
+		Monochrome:                      data[2]&0x10 != 0,
+		ChromaSubsamplingX:              data[2]&0x08 != 0,
+		ChromaSubsamplingY:              data[2]&0x04 != 0,
+		ChromaSamplePosition:            data[2] & 0x03,
+		HDRWCGIdc:                       data[3] >> 6,
+		InitialPresentationDelayPresent: data[3]&0x10 != 0,
+	}
+	if d.InitialPresentationDelayPresent {
+		d.InitialPresentationDelayMinusOne = data[3] & 0x0F
+	}
+	return d, nil
+}
+
+// DecodeAC4 decodes a DVB AC-4_descriptor.
+func (descriptor *pmtDescriptor) DecodeAC4() (AC4Descriptor, error) {
+	if !descriptor.IsExtensionDescriptor(AC4_DESC_TAG_EXTENSION) || len(descriptor.data) < 2 {
+		return AC4Descriptor{}, gots.ErrParsePMTDescriptor
+	}
+	data := descriptor.data[1:]
+	d := AC4Descriptor{ConfigFlag: data[0]&0x80 != 0}
+	tocFlag := data[0]&0x40 != 0
+	data = data[1:]
+	if d.ConfigFlag {
+		if len(data) < 1 {
+			return AC4Descriptor{}, gots.ErrParsePMTDescriptor
+		}
+		d.DialogEnhancementEnabled = data[0]&0x80 != 0
+		d.ChannelMode = data[0] >> 5 & 0x03
+		data = data[1:]
+	}
+	if tocFlag {
+		toc, rest, err := lengthPrefixed(data)
+		if err != nil {
+			return AC4Descriptor{}, err
+		}
+		d.TOC = toc
+		data = rest
+	}
+	d.AdditionalInfo = data
+	return d, nil
+}
+
+// DecodeOpusChannelConfig decodes a DVB Opus audio descriptor and returns
+// its channel_config_code.
+func (descriptor *pmtDescriptor) DecodeOpusChannelConfig() (uint8, error) {
+	if !descriptor.IsExtensionDescriptor(OPUS_DESC_TAG_EXTENSION) || len(descriptor.data) < 2 {
+		return 0, gots.ErrParsePMTDescriptor
+	}
+	return descriptor.data[1], nil
+}
+
+// DecodeEBP decodes an EBP_descriptor.
+func (descriptor *pmtDescriptor) DecodeEBP() (EBPDescriptor, error) {
+	data, err := descriptor.check(EBP, 1)
+	if err != nil {
+		return EBPDescriptor{}, err
+	}
+	numPartitions := int(data[0] >> 3)
+	e := EBPDescriptor{
+		TimescaleFlag: data[0]&0x04 != 0,
+		Partitions:    make([]EBPPartition, 0, numPartitions),
+	}
+	data = data[1:]
+	width := 1
+	if e.TimescaleFlag {
+		if len(data) < 3 {
+			return EBPDescriptor{}, gots.ErrParsePMTDescriptor
+		}
+		v := uint32(data[0])<<16 | uint32(data[1])<<8 | uint32(data[2])
+		e.TicksPerSecond = v >> 3
+		e.EBPDistanceWidthMinus1 = uint8(v & 0x07)
+		width = int(e.EBPDistanceWidthMinus1) + 1
+		data = data[3:]
+	}
+	for i := 0; i < numPartitions; i++ {
+		if len(data) < 1 {
+			return EBPDescriptor{}, gots.ErrParsePMTDescriptor
+		}
+		p := EBPPartition{
+			EBPDataExplicitFlag:  data[0]&0x80 != 0,
+			RepresentationIDFlag: data[0]&0x40 != 0,
+			PartitionID:          data[0] >> 1 & 0x1F,
+		}
+		if !p.EBPDataExplicitFlag {
+			if len(data) < 3 {
+				return EBPDescriptor{}, gots.ErrParsePMTDescriptor
+			}
+			p.EBPPID = uint16(data[1])<<5 | uint16(data[2])>>3
+			data = data[3:]
+		} else {
+			if len(data) < 2+width {
+				return EBPDescriptor{}, gots.ErrParsePMTDescriptor
+			}
+			p.BoundaryFlag = data[0]&0x01 != 0
+			for _, b := range data[1 : 1+width] {
+				p.EBPDistance = p.EBPDistance<<8 | uint64(b)
+			}
+			last := data[1+width]
+			if p.BoundaryFlag {
+				p.SAPTypeMax = last >> 5
+			}
+			p.AcquisitionFlag = last&0x01 != 0
+			data = data[2+width:]
+		}
+		if p.RepresentationIDFlag {
+			if len(data) < 8 {
+				return EBPDescriptor{}, gots.ErrParsePMTDescriptor
+			}
+			p.RepresentationID = binary.BigEndian.Uint64(data)
+			data = data[8:]
+		}
+		e.Partitions = append(e.Partitions, p)
+	}
+	return e, nil
+}
+
+// Partition returns the partition with the given partition_id.
+func (e EBPDescriptor) Partition(id uint8) (EBPPartition, bool) {
+	for _, p := range e.Partitions {
+		if p.PartitionID == id {
+			return p, true
+		}
+	}
+	return EBPPartition{}, false
+}
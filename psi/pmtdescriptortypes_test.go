@@ -0,0 +1,271 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/Comcast/gots/v3"
+)
+
+func TestRawData(t *testing.T) {
+	d := NewPmtDescriptor(REGISTRATION, []byte("CUEI"))
+	want := []byte{0x05, 0x04, 'C', 'U', 'E', 'I'}
+	if got := d.RawData(); !bytes.Equal(got, want) {
+		t.Errorf("RawData() = %X, want %X", got, want)
+	}
+}
+
+func TestDecodeRegistration(t *testing.T) {
+	d := NewPmtDescriptor(REGISTRATION, []byte{'H', 'E', 'V', 'C', 0x01})
+	r, err := d.DecodeRegistration()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.FormatIdentifier != "HEVC" || !bytes.Equal(r.AdditionalInfo, []byte{0x01}) {
+		t.Errorf("unexpected registration %+v", r)
+	}
+
+	if _, err := NewPmtDescriptor(REGISTRATION, []byte("AC3")).DecodeRegistration(); err != gots.ErrParsePMTDescriptor {
+		t.Errorf("expected ErrParsePMTDescriptor for short data, got %v", err)
+	}
+	if _, err := NewPmtDescriptor(LANGUAGE, []byte("engx")).DecodeRegistration(); err != gots.ErrParsePMTDescriptor {
+		t.Errorf("expected ErrParsePMTDescriptor for wrong tag, got %v", err)
+	}
+}
+
+func TestDecodeAVCVideo(t *testing.T) {
+	// High profile, constraint_set1, level 4.0, still present
+	d := NewPmtDescriptor(AVC_VIDEO, []byte{0x64, 0x40, 0x28, 0xBF})
+	v, err := d.DecodeAVCVideo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.ProfileIDC != 100 || v.LevelIDC != 40 {
+		t.Errorf("unexpected profile/level %d/%d", v.ProfileIDC, v.LevelIDC)
+	}
+	if v.ConstraintSet(0) || !v.ConstraintSet(1) || v.ConstraintSet(6) {
+		t.Errorf("unexpected constraint flags %08b", v.ConstraintSetFlags)
+	}
+	if !v.StillPresent || v.Picture24Hour || !v.FramePackingSEINotPresent {
+		t.Errorf("unexpected flags %+v", v)
+	}
+}
+
+func TestDecodeHEVCVideo(t *testing.T) {
+	data := []byte{
+		0x22,                   // profile_space 0, tier 1, Main 10
+		0x20, 0x00, 0x00, 0x00, // profile_compatibility_indication
+		0x90, 0, 0, 0, 0, 0, // progressive, frame only
+		0x99, // level 5.1
+		0x82, // temporal_layer_subset_flag
+		0x3F, // temporal_id_min 1
+		0x5F, // temporal_id_max 2
+	}
+	v, err := NewPmtDescriptor(HEVC_VIDEO, data).DecodeHEVCVideo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.TierFlag || v.ProfileIDC != 2 || v.ProfileCompatibilityIndication != 0x20000000 {
+		t.Errorf("unexpected profile %+v", v)
+	}
+	if !v.ProgressiveSource || v.InterlacedSource || !v.FrameOnlyConstraint {
+		t.Errorf("unexpected source flags %+v", v)
+	}
+	if v.LevelIDC != 153 || v.HDRWCGIdc != 2 {
+		t.Errorf("unexpected level %d or HDR_WCG_idc %d", v.LevelIDC, v.HDRWCGIdc)
+	}
+	if !v.TemporalLayerSubset || v.TemporalIDMin != 1 || v.TemporalIDMax != 2 {
+		t.Errorf("unexpected temporal layers %+v", v)
+	}
+
+	if _, err := NewPmtDescriptor(HEVC_VIDEO, data[:13]).DecodeHEVCVideo(); err != gots.ErrParsePMTDescriptor {
+		t.Errorf("expected ErrParsePMTDescriptor for missing temporal ids, got %v", err)
+	}
+}
+
+func TestDecodeHEVCVideoDescriptorLoop(t *testing.T) {
+	// Main profile level 3.1 descriptor of an elementary stream info loop,
+	// with the reserved bits set and temporal ids 0 through 1
+	loop := []byte{0x38, 0x0F, 0x01, 0x60, 0x00, 0x00, 0x00, 0xB0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x5D, 0xBF, 0x1F, 0x3F}
+	descriptors, err := ParseDescriptors(loop)
+	if err != nil || len(descriptors) != 1 {
+		t.Fatalf("Unexpected descriptors %v, %v", descriptors, err)
+	}
+	v, err := descriptors[0].DecodeHEVCVideo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.ProfileIDC != 1 || v.LevelIDC != 93 || v.HDRWCGIdc != 3 || !v.Picture24HourPresent || !v.SubPicHRDParamsNotPresent {
+		t.Errorf("unexpected descriptor %+v", v)
+	}
+	if !v.TemporalLayerSubset || v.TemporalIDMin != 0 || v.TemporalIDMax != 1 {
+		t.Errorf("unexpected temporal layers %+v", v)
+	}
+}
+
+func TestDecodeAACDescriptors(t *testing.T) {
+	p, err := NewPmtDescriptor(MPEG4_AUDIO, []byte{0x58}).DecodeMPEG4Audio()
+	if err != nil || p != 0x58 {
+		t.Errorf("DecodeMPEG4Audio() = %X, %v", p, err)
+	}
+
+	a, err := NewPmtDescriptor(MPEG2_AAC_AUDIO, []byte{1, 2, 0}).DecodeMPEG2AAC()
+	if err != nil || a.Profile != 1 || a.ChannelConfiguration != 2 {
+		t.Errorf("DecodeMPEG2AAC() = %+v, %v", a, err)
+	}
+
+	aac, err := NewPmtDescriptor(DVB_AAC, []byte{0x51, 0x80, 0x03, 0xAA}).DecodeAAC()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if aac.ProfileAndLevel != 0x51 || !aac.HasAACType || aac.AACType != 3 || !bytes.Equal(aac.AdditionalInfo, []byte{0xAA}) {
+		t.Errorf("unexpected AAC descriptor %+v", aac)
+	}
+
+	aac, err = NewPmtDescriptor(DVB_AAC, []byte{0x51}).DecodeAAC()
+	if err != nil || aac.HasAACType {
+		t.Errorf("DecodeAAC() = %+v, %v", aac, err)
+	}
+}
+
+func TestDecodeAC3(t *testing.T) {
+	// 48kHz, bsid 8, 384 kbit/s, complete main, 3/2 channels, full service
+	d := NewPmtDescriptor(AC3_AUDIO, []byte{0x08, 0x3C, 0x0F})
+	a, err := d.DecodeAC3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := AC3Descriptor{BSID: 8, BitRateCode: 15, NumChannels: 7, FullService: true}
+	if a != want {
+		t.Errorf("DecodeAC3() = %+v, want %+v", a, want)
+	}
+}
+
+func TestDecodeDVBAC3(t *testing.T) {
+	d := NewPmtDescriptor(DVB_AC3, []byte{0xA0, 0x42, 0x01, 0xEE})
+	a, err := d.DecodeDVBAC3()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.HasComponentType || a.ComponentType != 0x42 || a.HasBSID || !a.HasMainID || a.MainID != 1 || a.HasASVC {
+		t.Errorf("unexpected DVB AC-3 descriptor %+v", a)
+	}
+	if !bytes.Equal(a.AdditionalInfo, []byte{0xEE}) {
+		t.Errorf("unexpected additional info %X", a.AdditionalInfo)
+	}
+
+	if _, err := NewPmtDescriptor(DVB_AC3, []byte{0xF0, 0x42}).DecodeDVBAC3(); err != gots.ErrParsePMTDescriptor {
+		t.Errorf("expected ErrParsePMTDescriptor, got %v", err)
+	}
+}
+
+func TestDecodeStreamDescriptors(t *testing.T) {
+	a, err := NewPmtDescriptor(DATA_STREAM_ALIGNMENT, []byte{0x02}).DecodeDataStreamAlignment()
+	if err != nil || a != 2 {
+		t.Errorf("DecodeDataStreamAlignment() = %d, %v", a, err)
+	}
+
+	// frame_rate_code 4 (29.97), Main profile at Main level, 4:2:0
+	v, err := NewPmtDescriptor(VIDEO_STREAM, []byte{0x20, 0x48, 0x5F}).DecodeVideoStream()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := VideoStreamDescriptor{FrameRateCode: 4, ProfileAndLevel: 0x48, ChromaFormat: 1}
+	if v != want {
+		t.Errorf("DecodeVideoStream() = %+v, want %+v", v, want)
+	}
+	if _, err := NewPmtDescriptor(VIDEO_STREAM, []byte{0x20}).DecodeVideoStream(); err != gots.ErrParsePMTDescriptor {
+		t.Errorf("expected ErrParsePMTDescriptor, got %v", err)
+	}
+
+	s, err := NewPmtDescriptor(AUDIO_STREAM, []byte{0x68}).DecodeAudioStream()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.FreeFormat || s.ID != 1 || s.Layer != 2 || !s.VariableRateAudio {
+		t.Errorf("unexpected audio stream descriptor %+v", s)
+	}
+}
+
+func TestDecodeEBP(t *testing.T) {
+	// EBP descriptor of TestPMTIsIFrameStreamPositive
+	d := NewPmtDescriptor(EBP, []byte{0x10, 0x83, 0x01, 0x80, 0x85, 0x01, 0x80})
+	e, err := d.DecodeEBP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.TimescaleFlag || len(e.Partitions) != 2 {
+		t.Fatalf("unexpected EBP descriptor %+v", e)
+	}
+	segment, ok := e.Partition(EBP_PARTITION_SEGMENT)
+	if !ok {
+		t.Fatal("expected a segment partition")
+	}
+	want := EBPPartition{EBPDataExplicitFlag: true, PartitionID: 2, BoundaryFlag: true, EBPDistance: 1, SAPTypeMax: 4}
+	if segment != want {
+		t.Errorf("unexpected segment partition %+v, want %+v", segment, want)
+	}
+
+	e = EBPDescriptor{
+		TimescaleFlag:          true,
+		TicksPerSecond:         90000,
+		EBPDistanceWidthMinus1: 3,
+		Partitions: []EBPPartition{
+			{PartitionID: EBP_PARTITION_FRAGMENT, EBPPID: 0x1FF0},
+			{EBPDataExplicitFlag: true, RepresentationIDFlag: true, PartitionID: EBP_PARTITION_SEGMENT,
+				EBPDistance: 180000, AcquisitionFlag: true, RepresentationID: 42},
+		},
+	}
+	d, err = NewEBPDescriptor(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := d.DecodeEBP(); err != nil || !reflect.DeepEqual(got, e) {
+		t.Errorf("DecodeEBP() = %+v, %v, want %+v", got, err, e)
+	}
+
+	if _, err := NewPmtDescriptor(EBP, []byte{0x10, 0x83, 0x01, 0x80}).DecodeEBP(); err != gots.ErrParsePMTDescriptor {
+		t.Errorf("expected ErrParsePMTDescriptor for a missing partition, got %v", err)
+	}
+}
+
+func TestIsIFrameProfileRepresentationID(t *testing.T) {
+	// A partition with a representation_id precedes the explicit partition.
+	d, err := NewEBPDescriptor(EBPDescriptor{
+		Partitions: []EBPPartition{
+			{RepresentationIDFlag: true, PartitionID: EBP_PARTITION_FRAGMENT, EBPPID: 0x100, RepresentationID: 7},
+			{EBPDataExplicitFlag: true, PartitionID: EBP_PARTITION_SEGMENT, EBPDistance: 1},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.IsIFrameProfile() {
+		t.Error("expected an I-frame profile")
+	}
+}
@@ -36,6 +36,7 @@ type PmtElementaryStream interface {
 	Descriptors() []PmtDescriptor
 	MaxBitRate() uint64
 	IsTTMLSubtitling() bool
+	Codec() Codec
 }
 
 type pmtElementaryStream struct {
@@ -91,6 +92,23 @@ func (es *pmtElementaryStream) IsTTMLSubtitling() bool {
 	return false
 }
 
+// Codec returns the codec of the elementary stream, see LookupCodec.
+func (es *pmtElementaryStream) Codec() Codec {
+	return LookupCodec(es.StreamType(), es.descriptors)
+}
+
+// IsAudioContent returns true if the stream type or the descriptors
+// identify an audio codec.
+func (es *pmtElementaryStream) IsAudioContent() bool {
+	return es.PmtStreamType.IsAudioContent() || es.Codec().IsAudio()
+}
+
+// IsVideoContent returns true if the stream type or the descriptors
+// identify a video codec.
+func (es *pmtElementaryStream) IsVideoContent() bool {
+	return es.PmtStreamType.IsVideoContent() || es.Codec().IsVideo()
+}
+
 func (es *pmtElementaryStream) String() string {
 	descriptors := es.descriptors
 	var descriptorsBuf bytes.Buffer
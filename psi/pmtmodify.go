@@ -0,0 +1,190 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"github.com/Comcast/gots/v3"
+	"github.com/Comcast/gots/v3/packet"
+)
+
+const (
+	pmtTableID = 0x02
+	// pmtStaticLen is the length of the fixed fields following the section
+	// length, including the CRC.
+	pmtStaticLen = 13
+)
+
+// CreatePMT creates a PMT with no descriptors or elementary streams. The PMT
+// is current. Use UpdateData or Packets to encode it after it is modified.
+// ErrFieldOutOfRange is returned if the program number does not fit in 16
+// bits, the PCR PID in 13 bits or the version in 5 bits.
+func CreatePMT(programNumber, pcrPid int, version uint8) (PMT, error) {
+	p := &pmt{
+		programNumber:        programNumber,
+		pcrPid:               pcrPid,
+		versionNumber:        version,
+		currentNextIndicator: true,
+	}
+	if err := p.checkFields(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// checkFields returns ErrFieldOutOfRange if a field of the PMT header does
+// not fit its width in the section.
+func (p *pmt) checkFields() error {
+	if p.programNumber < 0 || p.programNumber > 0xFFFF ||
+		p.pcrPid < 0 || p.pcrPid > 0x1FFF || p.versionNumber > 0x1F {
+		return gots.ErrFieldOutOfRange
+	}
+	return nil
+}
+
+// SetProgramNumber sets the program number of the PMT.
+func (p *pmt) SetProgramNumber(programNumber int) {
+	p.programNumber = programNumber
+}
+
+// SetPCRPid sets the PID carrying the PCR of the program.
+func (p *pmt) SetPCRPid(pid int) {
+	p.pcrPid = pid
+}
+
+// SetVersionNumber sets the 5 bit version number of the PMT.
+func (p *pmt) SetVersionNumber(version uint8) {
+	p.versionNumber = version
+}
+
+// SetCurrentNextIndicator sets whether the PMT is currently applicable.
+func (p *pmt) SetCurrentNextIndicator(cni bool) {
+	p.currentNextIndicator = cni
+}
+
+// SetProgramDescriptors sets the program level descriptors.
+func (p *pmt) SetProgramDescriptors(descriptors []PmtDescriptor) {
+	p.programDescriptors = descriptors
+}
+
+// AddElementaryStream adds an elementary stream to the end of the PMT. If the
+// PMT already has a stream on the same PID it is replaced in place.
+func (p *pmt) AddElementaryStream(es PmtElementaryStream) {
+	for i, s := range p.elementaryStreams {
+		if s.ElementaryPid() == es.ElementaryPid() {
+			p.elementaryStreams[i] = es
+			return
+		}
+	}
+	p.elementaryStreams = append(p.elementaryStreams, es)
+	p.pids = append(p.pids, es.ElementaryPid())
+}
+
+// SetElementaryStreams replaces the elementary streams of the PMT. Streams
+// are encoded in the order given.
+func (p *pmt) SetElementaryStreams(streams []PmtElementaryStream) {
+	p.elementaryStreams = streams
+	p.pids = nil
+	for _, es := range streams {
+		p.pids = append(p.pids, es.ElementaryPid())
+	}
+}
+
+// UpdateData will encode the PMT to bytes, starting with the pointer field,
+// and return them. UpdateData will make the next call to Data() return these
+// new bytes. gots.ErrSectionTooLong is returned if the PMT does not fit in a
+// single section, gots.ErrInvalidDescriptor if a descriptor is longer than
+// 255 bytes and gots.ErrFieldOutOfRange if the program number, PCR PID,
+// version or an elementary PID does not fit its field.
+func (p *pmt) UpdateData() ([]byte, error) {
+	if err := p.checkFields(); err != nil {
+		return nil, err
+	}
+	programInfo, err := descriptorsData(p.programDescriptors)
+	if err != nil {
+		return nil, err
+	}
+	var streams []byte
+	for _, es := range p.elementaryStreams {
+		esInfo, err := descriptorsData(es.Descriptors())
+		if err != nil {
+			return nil, err
+		}
+		pid := es.ElementaryPid()
+		if pid < 0 || pid > 0x1FFF {
+			return nil, gots.ErrFieldOutOfRange
+		}
+		streams = append(streams,
+			es.StreamType(),
+			0xE0|byte(pid>>8)&0x1f, byte(pid),
+			0xF0|byte(len(esInfo)>>8)&0x0f, byte(len(esInfo)))
+		streams = append(streams, esInfo...)
+	}
+
+	sectionLength := pmtStaticLen + len(programInfo) + len(streams)
+	if sectionLength > maxSectionLength {
+		return nil, gots.ErrSectionTooLong
+	}
+
+	th := TableHeader{
+		TableID:                pmtTableID,
+		SectionSyntaxIndicator: true,
+		SectionLength:          uint16(sectionLength),
+	}
+	versionAndCNI := 0xC0 | p.versionNumber&0x1f<<1 // reserved, version_number
+	if p.currentNextIndicator {
+		versionAndCNI |= 0x01
+	}
+	data := append(NewPointerField(0), th.Data()...)
+	data = append(data,
+		byte(p.programNumber>>8), byte(p.programNumber),
+		versionAndCNI,
+		0, // section_number
+		0, // last_section_number
+		0xE0|byte(p.pcrPid>>8)&0x1f, byte(p.pcrPid),
+		0xF0|byte(len(programInfo)>>8)&0x0f, byte(len(programInfo)),
+	)
+	data = append(data, programInfo...)
+	data = append(data, streams...)
+	data = append(data, gots.ComputeCRC(data[1:])...)
+
+	p.data = data
+	return data, nil
+}
+
+// Data returns the bytes of the PMT, starting with the pointer field, as they
+// were parsed or last encoded by UpdateData.
+func (p *pmt) Data() []byte {
+	return p.data
+}
+
+// Packets encodes the PMT and returns it in packets on the given PID with
+// continuity counters starting at cc.
+func (p *pmt) Packets(pid int, cc uint8) ([]packet.Packet, error) {
+	data, err := p.UpdateData()
+	if err != nil {
+		return nil, err
+	}
+	return NewSectionPacketizer(pid, cc).Packetize(SplitSections(data)...), nil
+}
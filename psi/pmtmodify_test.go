@@ -0,0 +1,222 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/Comcast/gots/v3"
+	"github.com/Comcast/gots/v3/packet"
+)
+
+func parseTestPMT(t *testing.T) PMT {
+	pkt := packet.TestPmtPacket
+	pay, err := packet.Payload(&pkt)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	pmt, err := NewPMT(pay)
+	if err != nil {
+		t.Fatalf("Can't parse PMT %v", err)
+	}
+	return pmt
+}
+
+func TestPMTUpdateDataRoundTrip(t *testing.T) {
+	pmt := parseTestPMT(t)
+	original := append([]byte{}, pmt.Data()[:1+3+int(SectionLength(pmt.Data()))]...)
+
+	generated, err := pmt.UpdateData()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.Equal(original, generated) {
+		t.Errorf("Re-encoded PMT does not match\nExpected: %X\n     Got: %X", original, generated)
+	}
+	if !bytes.Equal(generated, pmt.Data()) {
+		t.Error("Data does not return the bytes from UpdateData")
+	}
+}
+
+func TestPMTInjectStreams(t *testing.T) {
+	pmt := parseTestPMT(t)
+	pmt.AddElementaryStream(NewPmtElementaryStream(0x86, 500, nil))
+	pmt.AddElementaryStream(NewPmtElementaryStream(0x15, 501, []PmtDescriptor{
+		NewPmtDescriptor(0x26, []byte{0xff, 0xff, 0x49, 0x44, 0x33, 0x20, 0xff, 0x49, 0x44, 0x33, 0x20, 0x00, 0x1f, 0x00, 0x01}),
+	}))
+	version := pmt.VersionNumber() + 1
+	pmt.SetVersionNumber(version)
+
+	pkts, err := pmt.Packets(100, 7)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(pkts) != 1 {
+		t.Fatalf("Expected 1 packet, got %d", len(pkts))
+	}
+	if packet.Pid(&pkts[0]) != 100 || packet.ContinuityCounter(&pkts[0]) != 7 || !packet.PayloadUnitStartIndicator(&pkts[0]) {
+		t.Errorf("Unexpected packet header %X", pkts[0][:4])
+	}
+
+	pay, _ := packet.Payload(&pkts[0])
+	parsed, err := NewPMT(pay)
+	if err != nil {
+		t.Fatalf("Can't parse generated PMT %v", err)
+	}
+	data := parsed.Data()
+	end := 1 + 3 + int(SectionLength(data))
+	if !bytes.Equal(gots.ComputeCRC(data[1:end-4]), data[end-4:end]) {
+		t.Errorf("Generated PMT has an invalid CRC: %X", data[:end])
+	}
+	if want := []int{101, 102, 110, 500, 501}; !reflect.DeepEqual(want, parsed.Pids()) {
+		t.Errorf("Wrong PIDs got %v, want %v", parsed.Pids(), want)
+	}
+	if parsed.VersionNumber() != version {
+		t.Errorf("Wrong version number got %d, want %d", parsed.VersionNumber(), version)
+	}
+	id3 := parsed.ElementaryStreams()[4]
+	if !id3.IsID3Content() || len(id3.Descriptors()) != 1 || id3.Descriptors()[0].Tag() != 0x26 {
+		t.Errorf("Unexpected ID3 stream %v", id3)
+	}
+}
+
+func TestCreatePMT(t *testing.T) {
+	pmt, err := CreatePMT(1, 0x100, 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	pmt.SetProgramDescriptors([]PmtDescriptor{NewPmtDescriptor(REGISTRATION, []byte("CUEI"))})
+	video := NewPmtElementaryStream(0x1b, 0x100, nil)
+	audio := NewPmtElementaryStream(0x0f, 0x101, []PmtDescriptor{NewPmtDescriptor(LANGUAGE, []byte{'e', 'n', 'g', 0})})
+	pmt.SetElementaryStreams([]PmtElementaryStream{audio, video})
+	pmt.RemoveElementaryStreams([]int{0x101})
+	pmt.AddElementaryStream(audio)
+
+	data, err := pmt.UpdateData()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []byte{
+		0x00,             // pointer field
+		0x02, 0xb0, 0x23, // table id, section length
+		0x00, 0x01, // program number
+		0xc7, 0x00, 0x00, // version, section numbers
+		0xe1, 0x00, // PCR PID
+		0xf0, 0x06, 0x05, 0x04, 'C', 'U', 'E', 'I', // program info
+		0x1b, 0xe1, 0x00, 0xf0, 0x00, // video
+		0x0f, 0xe1, 0x01, 0xf0, 0x06, 0x0a, 0x04, 'e', 'n', 'g', 0x00, // audio
+	}
+	if !bytes.Equal(want, data[:len(data)-4]) {
+		t.Errorf("Unexpected PMT\nExpected: %X\n     Got: %X", want, data)
+	}
+}
+
+func TestCreatePMTOutOfRange(t *testing.T) {
+	tests := []struct {
+		programNumber int
+		pcrPid        int
+		version       uint8
+	}{
+		{0x10000, 0x100, 0},
+		{-1, 0x100, 0},
+		{1, 0x2000, 0},
+		{1, -1, 0},
+		{1, 0x100, 32},
+	}
+	for _, test := range tests {
+		if _, err := CreatePMT(test.programNumber, test.pcrPid, test.version); err != gots.ErrFieldOutOfRange {
+			t.Errorf("CreatePMT(%d, %d, %d): expected ErrFieldOutOfRange, got %v", test.programNumber, test.pcrPid, test.version, err)
+		}
+	}
+
+	pmt, err := CreatePMT(0xFFFF, 0x1FFF, 31)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if _, err := pmt.UpdateData(); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	setters := []func(PMT){
+		func(p PMT) { p.SetProgramNumber(0x10000) },
+		func(p PMT) { p.SetPCRPid(0x2000) },
+		func(p PMT) { p.SetVersionNumber(32) },
+		func(p PMT) { p.AddElementaryStream(NewPmtElementaryStream(0x1b, 0x2000, nil)) },
+	}
+	for i, set := range setters {
+		pmt, _ := CreatePMT(1, 0x100, 0)
+		set(pmt)
+		if _, err := pmt.UpdateData(); err != gots.ErrFieldOutOfRange {
+			t.Errorf("setter %d: expected ErrFieldOutOfRange, got %v", i, err)
+		}
+	}
+}
+
+func TestPMTTooLong(t *testing.T) {
+	pmt, _ := CreatePMT(1, 0x100, 0)
+	for pid := 0x100; pid < 0x200; pid++ {
+		pmt.AddElementaryStream(NewPmtElementaryStream(0x1b, pid, nil))
+	}
+	if _, err := pmt.UpdateData(); err != gots.ErrSectionTooLong {
+		t.Errorf("Expected ErrSectionTooLong, got %v", err)
+	}
+}
+
+func TestPMTDescriptorTooLong(t *testing.T) {
+	pmt, _ := CreatePMT(1, 0x100, 0)
+	pmt.AddElementaryStream(NewPmtElementaryStream(0x1b, 0x100, []PmtDescriptor{NewPmtDescriptor(0x05, make([]byte, 256))}))
+	if _, err := pmt.UpdateData(); err != gots.ErrInvalidDescriptor {
+		t.Errorf("Expected ErrInvalidDescriptor, got %v", err)
+	}
+}
+
+func TestPMTMalformedProgramInfo(t *testing.T) {
+	pmt, _ := CreatePMT(1, 0x100, 0)
+	pmt.SetProgramDescriptors([]PmtDescriptor{
+		NewPmtDescriptor(REGISTRATION, []byte("CUEI")),
+		NewPmtDescriptor(0x05, []byte("HDMV")),
+	})
+	pmt.AddElementaryStream(NewPmtElementaryStream(0x1b, 0x100, nil))
+	data, err := pmt.UpdateData()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Make the second descriptor overrun the program_info loop
+	data[20] = 0x10
+	end := len(data) - 4
+	copy(data[end:], gots.ComputeCRC(data[1:end]))
+
+	parsed, err := NewPMT(data)
+	if err != nil {
+		t.Fatalf("Expected the PMT to parse, got %v", err)
+	}
+	if len(parsed.ProgramDescriptors()) != 1 || parsed.ProgramDescriptors()[0].Tag() != REGISTRATION {
+		t.Errorf("Expected the descriptor preceding the malformed one, got %v", parsed.ProgramDescriptors())
+	}
+	if want := []int{0x100}; !reflect.DeepEqual(want, parsed.Pids()) {
+		t.Errorf("Wrong PIDs got %v, want %v", parsed.Pids(), want)
+	}
+}
@@ -32,10 +32,17 @@ const (
 	PmtStreamTypeMpeg4Video     uint8 = 27 // H264
 	PmtStreamTypeMpeg4VideoH264 uint8 = 27 // H264
 	PmtStreamTypeMpeg4VideoH265 uint8 = 36 // H265
+	PmtStreamTypeVvc            uint8 = 51 // H266
 
-	PmtStreamTypeAac uint8 = 15  // AAC
-	PmtStreamTypeAc3 uint8 = 129 // DD
-	PmtStreamTypeEc3 uint8 = 135 // DD+
+	PmtStreamTypeAac        uint8 = 15  // AAC
+	PmtStreamTypeAacLatm    uint8 = 17  // AAC with LATM transport syntax
+	PmtStreamTypeMpeg4Audio uint8 = 28  // AAC without transport syntax
+	PmtStreamTypeAc3        uint8 = 129 // DD
+	PmtStreamTypeEc3        uint8 = 135 // DD+
+
+	PmtStreamTypeMpegH3dAudio    uint8 = 45  // MPEG-H 3D Audio main stream
+	PmtStreamTypeMpegH3dAudioAux uint8 = 46  // MPEG-H 3D Audio auxiliary stream
+	PmtStreamTypeDtsHd           uint8 = 136 // DTS-HD
 
 	PmtStreamTypeScte35 uint8 = 134 // SCTE-35
 
@@ -75,15 +82,21 @@ func (st pmtStreamType) IsStreamWherePresentationLagsEbp() bool {
 
 func (st pmtStreamType) IsAudioContent() bool {
 	return st.code == PmtStreamTypeAac ||
+		st.code == PmtStreamTypeAacLatm ||
+		st.code == PmtStreamTypeMpeg4Audio ||
 		st.code == PmtStreamTypeAc3 ||
-		st.code == PmtStreamTypeEc3
+		st.code == PmtStreamTypeEc3 ||
+		st.code == PmtStreamTypeMpegH3dAudio ||
+		st.code == PmtStreamTypeMpegH3dAudioAux ||
+		st.code == PmtStreamTypeDtsHd
 }
 
 func (st pmtStreamType) IsVideoContent() bool {
 	return st.code == PmtStreamTypeMpeg4VideoH264 ||
 		st.code == PmtStreamTypeMpeg4VideoH265 ||
 		st.code == PmtStreamTypeMpeg4Video ||
-		st.code == PmtStreamTypeMpeg2VideoH262
+		st.code == PmtStreamTypeMpeg2VideoH262 ||
+		st.code == PmtStreamTypeVvc
 }
 
 func (st pmtStreamType) IsSCTE35Content() bool {
@@ -122,7 +135,7 @@ func newPmtStreamType(code uint8, description string, presentationLagsEbp bool)
 
 func presentationLagsEbp(code uint8) bool {
 	switch code {
-	case 3, 4, 15, 17, 129, 135, 136:
+	case 3, 4, 15, 17, 45, 46, 129, 135, 136:
 		return true
 	}
 	return false
@@ -136,7 +149,7 @@ type atscPmtStreamType struct {
 
 // Code/Descriptions transcribed from ATSC Code Point Registry at
 // http://www.atsc.org/cms/index.php/standards/other-technical-documents/78-atsc-code-point-registry
-// As of 4/8/2014
+// As of 4/8/2014, with later stream types from ISO/IEC 13818-1
 var atscPmtStreamTypes = []atscPmtStreamType{
 	{0, 0, "ITU-T | ISO/IEC Reserved"},
 	{1, 1, "ISO/IEC 11172 Video	"},
@@ -166,8 +179,17 @@ var atscPmtStreamTypes = []atscPmtStreamType{
 	{25, 25, "Metadata carried in ISO/IEC 13818-6 Synchronized Download Protocol"},
 	{26, 26, "IPMP stream (defined in ISO/IEC 13818-11, MPEG-2 IPMP)"},
 	{27, 27, "AVC video stream as defined in ITU-T Rec. H.264 | ISO/IEC 14496-10 Video"},
-	{28, 127, "ITU-T Rec. H.222.0 | ISO/IEC 13818-1 Reserved"},
+	{28, 28, "ISO/IEC 14496-3 Audio, without using any additional transport syntax"},
+	{29, 35, "ITU-T Rec. H.222.0 | ISO/IEC 13818-1 Reserved"},
 	{36, 36, "HEVC video stream as defined in ITU-T Rec. H.265 | ISO/IEC 23008-2 Video"},
+	{37, 37, "HEVC temporal video subset as defined in ITU-T Rec. H.265 | ISO/IEC 23008-2 Video"},
+	{38, 44, "ITU-T Rec. H.222.0 | ISO/IEC 13818-1 Reserved"},
+	{45, 45, "ISO/IEC 23008-3 Audio with MHAS transport syntax - main stream"},
+	{46, 46, "ISO/IEC 23008-3 Audio with MHAS transport syntax - auxiliary stream"},
+	{47, 50, "ITU-T Rec. H.222.0 | ISO/IEC 13818-1 Reserved"},
+	{51, 51, "VVC video stream as defined in ITU-T Rec. H.266 | ISO/IEC 23090-3 Video"},
+	{52, 52, "VVC temporal video subset as defined in ITU-T Rec. H.266 | ISO/IEC 23090-3 Video"},
+	{53, 127, "ITU-T Rec. H.222.0 | ISO/IEC 13818-1 Reserved"},
 	{128, 128, "DigiCipher® II video | Identical to ITU-T Rec. H.262 | ISO/IEC 13818-2 Video"},
 	{129, 129, "ATSC A/53 audio [2] | AC-3 audio"},
 	{130, 130, "SCTE Standard Subtitle"},
@@ -36,6 +36,41 @@ type TableHeader struct {
 	SectionLength          uint16
 }
 
+// ParseOptions controls how tables are parsed.
+type ParseOptions struct {
+	// SkipCRCCheck disables CRC validation, which is useful for forensic
+	// tools that need to inspect corrupted tables.
+	SkipCRCCheck bool
+}
+
+// WithoutCRCCheck is an option function for parsing tables without
+// validating their CRC.
+func WithoutCRCCheck(o *ParseOptions) {
+	o.SkipCRCCheck = true
+}
+
+// NewParseOptions applies the option functions to the default options.
+func NewParseOptions(options ...func(*ParseOptions)) ParseOptions {
+	var o ParseOptions
+	for _, option := range options {
+		option(&o)
+	}
+	return o
+}
+
+// CheckCRC verifies the CRC of the section at the start of b, unless
+// disabled by the options. b must start at the table_id.
+func (o ParseOptions) CheckCRC(b []byte) error {
+	if o.SkipCRCCheck {
+		return nil
+	}
+	end := 3 + int(sectionLength(b))
+	if len(b) < end {
+		return gots.ErrShortPayload
+	}
+	return gots.CheckCRC(b[:end])
+}
+
 func PointerField(psi []byte) uint8 {
 	return psi[0]
 }
@@ -0,0 +1,144 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"sync"
+
+	"github.com/Comcast/gots/v3"
+)
+
+// TableDecoder decodes PSI bytes, starting with the pointer field, into a
+// table such as a PAT, PMT or a proprietary table type.
+type TableDecoder func(psi []byte, options ...func(*ParseOptions)) (interface{}, error)
+
+// TableRegistry maps table_id ranges to table decoders, so that tables can
+// be decoded without knowing their type in advance.
+type TableRegistry interface {
+	// Register registers a decoder for the table_ids first through last
+	// inclusive. A later registration takes precedence over earlier ones
+	// for the table_ids it covers.
+	Register(first, last uint8, decoder TableDecoder)
+	// Decoder returns the decoder registered for the table_id.
+	Decoder(tableID uint8) (TableDecoder, bool)
+	// Decode decodes PSI bytes, starting with the pointer field, with the
+	// decoder registered for the table_id of the first section.
+	// gots.ErrUnknownTableID is returned if there is none.
+	Decode(psi []byte, options ...func(*ParseOptions)) (interface{}, error)
+}
+
+type tableRegistry struct {
+	mu       sync.RWMutex
+	decoders [256]TableDecoder
+}
+
+// NewTableRegistry creates a new empty TableRegistry.
+func NewTableRegistry() TableRegistry {
+	return &tableRegistry{}
+}
+
+func (r *tableRegistry) Register(first, last uint8, decoder TableDecoder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id := int(first); id <= int(last); id++ {
+		r.decoders[id] = decoder
+	}
+}
+
+func (r *tableRegistry) Decoder(tableID uint8) (TableDecoder, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	d := r.decoders[tableID]
+	return d, d != nil
+}
+
+func (r *tableRegistry) Decode(psi []byte, options ...func(*ParseOptions)) (interface{}, error) {
+	if len(psi) < 1 || len(psi) < 2+int(PointerField(psi)) {
+		return nil, gots.ErrShortPayload
+	}
+	d, ok := r.Decoder(TableID(psi))
+	if !ok {
+		return nil, gots.ErrUnknownTableID
+	}
+	return d(psi, options...)
+}
+
+// DefaultTableRegistry is the registry used by RegisterTableDecoder and
+// DecodeTable. The tables of this package are registered in it, and other
+// packages such as psip register their tables when imported.
+var DefaultTableRegistry = NewTableRegistry()
+
+// RegisterTableDecoder registers a decoder for the table_ids first through
+// last inclusive in the DefaultTableRegistry.
+func RegisterTableDecoder(first, last uint8, decoder TableDecoder) {
+	DefaultTableRegistry.Register(first, last, decoder)
+}
+
+// DecodeTable decodes PSI bytes, starting with the pointer field, with the
+// decoder registered in the DefaultTableRegistry for the table_id of the
+// first section. gots.ErrUnknownTableID is returned if there is none.
+func DecodeTable(psi []byte, options ...func(*ParseOptions)) (interface{}, error) {
+	return DefaultTableRegistry.Decode(psi, options...)
+}
+
+// SectionDecoder is a TableDecoder returning the first section of the PSI
+// bytes as a generic long form Section. It can be registered for private
+// tables that have no dedicated decoder.
+func SectionDecoder(psi []byte, options ...func(*ParseOptions)) (interface{}, error) {
+	if len(psi) < 1 || len(psi) < 1+int(PointerField(psi)) {
+		return nil, gots.ErrShortPayload
+	}
+	return NewSection(psi[1+PointerField(psi):], options...)
+}
+
+func init() {
+	RegisterTableDecoder(patTableID, patTableID, func(b []byte, options ...func(*ParseOptions)) (interface{}, error) {
+		return NewPAT(b, options...)
+	})
+	RegisterTableDecoder(CatTableID, CatTableID, func(b []byte, options ...func(*ParseOptions)) (interface{}, error) {
+		return NewCAT(b, options...)
+	})
+	RegisterTableDecoder(pmtTableID, pmtTableID, func(b []byte, options ...func(*ParseOptions)) (interface{}, error) {
+		return NewPMT(b, options...)
+	})
+	RegisterTableDecoder(NitActualTableID, NitOtherTableID, func(b []byte, options ...func(*ParseOptions)) (interface{}, error) {
+		return NewNIT(b, options...)
+	})
+	sdt := func(b []byte, options ...func(*ParseOptions)) (interface{}, error) {
+		return NewSDT(b, options...)
+	}
+	RegisterTableDecoder(SdtActualTableID, SdtActualTableID, sdt)
+	RegisterTableDecoder(SdtOtherTableID, SdtOtherTableID, sdt)
+	RegisterTableDecoder(EitActualPresentFollowingTableID, EitOtherScheduleLastTableID, func(b []byte, options ...func(*ParseOptions)) (interface{}, error) {
+		return NewEIT(b, options...)
+	})
+	// The TDT has no CRC, so there are no options to pass on.
+	RegisterTableDecoder(TdtTableID, TdtTableID, func(b []byte, _ ...func(*ParseOptions)) (interface{}, error) {
+		return NewTDT(b)
+	})
+	RegisterTableDecoder(TotTableID, TotTableID, func(b []byte, options ...func(*ParseOptions)) (interface{}, error) {
+		return NewTOT(b, options...)
+	})
+}
@@ -0,0 +1,109 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"testing"
+
+	"github.com/Comcast/gots/v3"
+	"github.com/Comcast/gots/v3/packet"
+)
+
+func TestDecodeTable(t *testing.T) {
+	pat := packet.TestPatPacket
+	pay, _ := packet.Payload(&pat)
+	table, err := DecodeTable(pay)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := table.(PAT); !ok {
+		t.Errorf("Expected a PAT, got %T", table)
+	}
+
+	pmt := packet.TestPmtPacket
+	pay, _ = packet.Payload(&pmt)
+	table, err = DecodeTable(pay)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := table.(PMT); !ok {
+		t.Errorf("Expected a PMT, got %T", table)
+	}
+
+	sdt := append(NewPointerField(0), makeSection(SdtOtherTableID, 1, 0, 0, 0, []byte{0, 1, 0xFF})...)
+	table, err = DecodeTable(sdt)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := table.(SDT); !ok {
+		t.Errorf("Expected an SDT, got %T", table)
+	}
+
+	if _, err := DecodeTable(append(NewPointerField(0), makeSection(0x90, 1, 0, 0, 0, nil)...)); err != gots.ErrUnknownTableID {
+		t.Errorf("Expected ErrUnknownTableID, got %v", err)
+	}
+	if _, err := DecodeTable([]byte{0x01, 0xFF}); err != gots.ErrShortPayload {
+		t.Errorf("Expected ErrShortPayload, got %v", err)
+	}
+}
+
+func TestTableRegistry(t *testing.T) {
+	r := NewTableRegistry()
+	r.Register(0x80, 0x8F, SectionDecoder)
+	r.Register(0x88, 0x88, func(b []byte, options ...func(*ParseOptions)) (interface{}, error) {
+		return "proprietary", nil
+	})
+	if _, ok := r.Decoder(0x7F); ok {
+		t.Error("Expected no decoder for 0x7F")
+	}
+
+	b := append(NewPointerField(2), makeSection(0x81, 0x1234, 3, 1, 2, []byte{0xAB})...)
+	table, err := r.Decode(b)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	s, ok := table.(Section)
+	if !ok {
+		t.Fatalf("Expected a Section, got %T", table)
+	}
+	if s.TableID() != 0x81 || s.TableIDExtension() != 0x1234 || s.VersionNumber() != 3 || s.SectionNumber() != 1 ||
+		s.LastSectionNumber() != 2 || len(s.Data()) != 1 || s.Data()[0] != 0xAB {
+		t.Errorf("Unexpected section %X", s)
+	}
+
+	b = append(NewPointerField(0), makeSection(0x88, 0, 0, 0, 0, nil)...)
+	if table, err := r.Decode(b); err != nil || table != "proprietary" {
+		t.Errorf("Unexpected table %v, %v", table, err)
+	}
+
+	b = append(NewPointerField(0), makeSection(0x82, 0, 0, 0, 0, nil)...)
+	b[len(b)-1] ^= 0xFF
+	if _, err := r.Decode(b, WithoutCRCCheck); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := r.Decode(b); err == nil {
+		t.Error("Expected CRC error")
+	}
+}
@@ -0,0 +1,209 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"github.com/Comcast/gots/v3"
+)
+
+// SDT table ids
+const (
+	SdtActualTableID = 0x42
+	SdtOtherTableID  = 0x46
+)
+
+// SDT is a DVB Service Description Table, describing the services of a
+// transport stream. See ETSI EN 300 468 section 5.2.3.
+type SDT interface {
+	TableID() uint8
+	// IsActual returns true if the SDT describes the transport stream it
+	// is carried in.
+	IsActual() bool
+	TransportStreamID() int
+	OriginalNetworkID() int
+	VersionNumber() uint8
+	CurrentNextIndicator() bool
+	Services() []SDTService
+}
+
+// SDTService is a service entry of an SDT.
+type SDTService struct {
+	ServiceID int
+	// EITSchedule is true if EIT schedule information for the service is
+	// present in the transport stream.
+	EITSchedule bool
+	// EITPresentFollowing is true if EIT present/following information for
+	// the service is present in the transport stream.
+	EITPresentFollowing bool
+	RunningStatus       RunningStatus
+	// FreeCAMode is true if one or more component streams may be scrambled.
+	FreeCAMode  bool
+	Descriptors []PmtDescriptor
+}
+
+// ServiceDescriptor is a decoded service_descriptor.
+type ServiceDescriptor struct {
+	ServiceType  uint8
+	ProviderName string
+	ServiceName  string
+}
+
+type sdt struct {
+	tableID              uint8
+	transportStreamID    int
+	originalNetworkID    int
+	versionNumber        uint8
+	currentNextIndicator bool
+	services             []SDTService
+}
+
+func isSDTTableID(id uint8) bool {
+	return id == SdtActualTableID || id == SdtOtherTableID
+}
+
+// NewSDT creates a new SDT from the given bytes, which should be concatenated
+// packet payload contents starting with the pointer field. Services of all
+// SDT sections in the bytes are combined, gots.ErrMixedSections is returned
+// if their table_id, transport_stream_id or original_network_id differ. The
+// CRC of each section is validated unless WithoutCRCCheck is given.
+func NewSDT(sdtBytes []byte, options ...func(*ParseOptions)) (SDT, error) {
+	sections, err := parseSections(sdtBytes, isSDTTableID, NewParseOptions(options...))
+	if err != nil {
+		return nil, err
+	}
+
+	s := &sdt{
+		tableID:              sections[0].TableID(),
+		transportStreamID:    int(sections[0].TableIDExtension()),
+		versionNumber:        sections[0].VersionNumber(),
+		currentNextIndicator: sections[0].CurrentNextIndicator(),
+	}
+	for i, section := range sections {
+		if section.TableID() != sections[0].TableID() || section.TableIDExtension() != sections[0].TableIDExtension() {
+			return nil, gots.ErrMixedSections
+		}
+		if err := s.parseSection(section.Data(), i == 0); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// parseSection appends the services of a section. The original_network_id
+// is taken from the first section and must be the same in the others.
+func (s *sdt) parseSection(data []byte, first bool) error {
+	if len(data) < 3 {
+		return gots.ErrShortPayload
+	}
+	originalNetworkID := int(data[0])<<8 | int(data[1])
+	if first {
+		s.originalNetworkID = originalNetworkID
+	} else if originalNetworkID != s.originalNetworkID {
+		return gots.ErrMixedSections
+	}
+	data = data[3:]
+	for len(data) > 0 {
+		if len(data) < 5 {
+			return gots.ErrShortPayload
+		}
+		loopLength := int(data[3]&0x0f)<<8 | int(data[4])
+		if len(data) < 5+loopLength {
+			return gots.ErrShortPayload
+		}
+		descriptors, err := ParseDescriptors(data[5 : 5+loopLength])
+		if err != nil {
+			return err
+		}
+		s.services = append(s.services, SDTService{
+			ServiceID:           int(data[0])<<8 | int(data[1]),
+			EITSchedule:         data[2]&0x02 != 0,
+			EITPresentFollowing: data[2]&0x01 != 0,
+			RunningStatus:       RunningStatus(data[3] >> 5),
+			FreeCAMode:          data[3]&0x10 != 0,
+			Descriptors:         descriptors,
+		})
+		data = data[5+loopLength:]
+	}
+	return nil
+}
+
+func (s *sdt) TableID() uint8 {
+	return s.tableID
+}
+
+func (s *sdt) IsActual() bool {
+	return s.tableID == SdtActualTableID
+}
+
+func (s *sdt) TransportStreamID() int {
+	return s.transportStreamID
+}
+
+func (s *sdt) OriginalNetworkID() int {
+	return s.originalNetworkID
+}
+
+func (s *sdt) VersionNumber() uint8 {
+	return s.versionNumber
+}
+
+func (s *sdt) CurrentNextIndicator() bool {
+	return s.currentNextIndicator
+}
+
+func (s *sdt) Services() []SDTService {
+	return s.services
+}
+
+// ServiceDescriptor returns the decoded service_descriptor of the service.
+// gots.ErrDescriptorNotFound is returned if the service has none.
+func (s SDTService) ServiceDescriptor() (ServiceDescriptor, error) {
+	d, ok := findDescriptor(s.Descriptors, SERVICE)
+	if !ok {
+		return ServiceDescriptor{}, gots.ErrDescriptorNotFound
+	}
+	return DecodeServiceDescriptor(d)
+}
+
+// DecodeServiceDescriptor decodes a service_descriptor.
+func DecodeServiceDescriptor(d PmtDescriptor) (ServiceDescriptor, error) {
+	data := d.Data()
+	if d.Tag() != SERVICE || len(data) < 1 {
+		return ServiceDescriptor{}, gots.ErrParsePMTDescriptor
+	}
+	provider, rest, err := lengthPrefixed(data[1:])
+	if err != nil {
+		return ServiceDescriptor{}, err
+	}
+	name, _, err := lengthPrefixed(rest)
+	if err != nil {
+		return ServiceDescriptor{}, err
+	}
+	return ServiceDescriptor{
+		ServiceType:  data[0],
+		ProviderName: DecodeDVBString(provider),
+		ServiceName:  DecodeDVBString(name),
+	}, nil
+}
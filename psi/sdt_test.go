@@ -0,0 +1,163 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Comcast/gots/v3"
+)
+
+func sdtServiceLoop(serviceID uint16, flags, status byte, freeCA bool, descriptors []byte) []byte {
+	b := []byte{
+		byte(serviceID >> 8), byte(serviceID), 0xFC | flags,
+		status<<5 | byte(len(descriptors)>>8), byte(len(descriptors)),
+	}
+	if freeCA {
+		b[3] |= 0x10
+	}
+	return append(b, descriptors...)
+}
+
+func serviceDescriptor(serviceType byte, provider, name string) []byte {
+	b := []byte{SERVICE, byte(3 + len(provider) + len(name)), serviceType, byte(len(provider))}
+	b = append(b, provider...)
+	b = append(b, byte(len(name)))
+	return append(b, name...)
+}
+
+func TestNewSDT(t *testing.T) {
+	data := []byte{0x20, 0x85, 0xFF}
+	data = append(data, sdtServiceLoop(0x1001, 0x03, byte(RunningStatusRunning), false,
+		serviceDescriptor(0x01, "Provider", "Service One"))...)
+	data = append(data, sdtServiceLoop(0x1002, 0x01, byte(RunningStatusNotRunning), true,
+		serviceDescriptor(0x02, "", "\x05T\xfcrk Radyo"))...)
+	b := append(NewPointerField(0), makeSection(SdtActualTableID, 0x0102, 4, 0, 0, data)...)
+
+	sdt, err := NewSDT(b)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !sdt.IsActual() || sdt.TransportStreamID() != 0x0102 || sdt.OriginalNetworkID() != 0x2085 ||
+		sdt.VersionNumber() != 4 || !sdt.CurrentNextIndicator() {
+		t.Errorf("Unexpected SDT header %+v", sdt)
+	}
+	services := sdt.Services()
+	if len(services) != 2 {
+		t.Fatalf("Expected 2 services, got %d", len(services))
+	}
+
+	s := services[0]
+	if s.ServiceID != 0x1001 || !s.EITSchedule || !s.EITPresentFollowing ||
+		s.RunningStatus != RunningStatusRunning || s.FreeCAMode {
+		t.Errorf("Unexpected service %+v", s)
+	}
+	sd, err := s.ServiceDescriptor()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if sd != (ServiceDescriptor{ServiceType: 1, ProviderName: "Provider", ServiceName: "Service One"}) {
+		t.Errorf("Unexpected service descriptor %+v", sd)
+	}
+
+	s = services[1]
+	if s.ServiceID != 0x1002 || s.EITSchedule || !s.EITPresentFollowing ||
+		s.RunningStatus != RunningStatusNotRunning || !s.FreeCAMode {
+		t.Errorf("Unexpected service %+v", s)
+	}
+	sd, err = s.ServiceDescriptor()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if sd.ServiceType != 2 || sd.ProviderName != "" || sd.ServiceName != "Türk Radyo" {
+		t.Errorf("Unexpected service descriptor %+v", sd)
+	}
+}
+
+func TestNewSDTMultipleSections(t *testing.T) {
+	first := append([]byte{0x20, 0x85, 0xFF}, sdtServiceLoop(1, 0, 0, false, nil)...)
+	second := append([]byte{0x20, 0x85, 0xFF}, sdtServiceLoop(2, 0, 0, false, nil)...)
+	b := NewPointerField(0)
+	b = append(b, makeSection(SdtOtherTableID, 5, 0, 0, 1, first)...)
+	b = append(b, makeSection(SdtOtherTableID, 5, 0, 1, 1, second)...)
+
+	sdt, err := NewSDT(b)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if sdt.IsActual() {
+		t.Error("Expected SDT other")
+	}
+	services := sdt.Services()
+	if len(services) != 2 || services[0].ServiceID != 1 || services[1].ServiceID != 2 {
+		t.Errorf("Unexpected services %+v", services)
+	}
+	if _, err := services[0].ServiceDescriptor(); err != gots.ErrDescriptorNotFound {
+		t.Errorf("Expected ErrDescriptorNotFound, got %v", err)
+	}
+}
+
+func TestNewSDTErrors(t *testing.T) {
+	if _, err := NewSDT(append(NewPointerField(0), makeSection(0x40, 1, 0, 0, 0, nil)...)); err != gots.ErrUnknownTableID {
+		t.Errorf("Expected ErrUnknownTableID, got %v", err)
+	}
+
+	b := append(NewPointerField(0), makeSection(SdtActualTableID, 1, 0, 0, 0, []byte{0x20, 0x85, 0xFF})...)
+	b[len(b)-1] ^= 0xFF
+	if _, err := NewSDT(b); !errors.Is(err, gots.ErrCRCMismatch) {
+		t.Errorf("Expected ErrCRCMismatch, got %v", err)
+	}
+	if _, err := NewSDT(b, WithoutCRCCheck); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	data := append([]byte{0x20, 0x85, 0xFF}, sdtServiceLoop(1, 0, 0, false, []byte{SERVICE})...)
+	b = append(NewPointerField(0), makeSection(SdtActualTableID, 1, 0, 0, 0, data[:len(data)-1])...)
+	if _, err := NewSDT(b); err != gots.ErrShortPayload {
+		t.Errorf("Expected ErrShortPayload, got %v", err)
+	}
+}
+
+func TestNewSDTMixedSections(t *testing.T) {
+	data := func(onid uint16) []byte {
+		return append([]byte{byte(onid >> 8), byte(onid), 0xFF}, sdtServiceLoop(1, 0, 0, false, nil)...)
+	}
+	tests := []struct {
+		name   string
+		second []byte
+	}{
+		{"table_id", makeSection(SdtOtherTableID, 5, 0, 1, 1, data(1))},
+		{"transport_stream_id", makeSection(SdtActualTableID, 6, 0, 1, 1, data(1))},
+		{"original_network_id", makeSection(SdtActualTableID, 5, 0, 1, 1, data(2))},
+	}
+	for _, test := range tests {
+		b := append(NewPointerField(0), makeSection(SdtActualTableID, 5, 0, 0, 1, data(1))...)
+		b = append(b, test.second...)
+		if _, err := NewSDT(b); err != gots.ErrMixedSections {
+			t.Errorf("%s: expected ErrMixedSections, got %v", test.name, err)
+		}
+	}
+}
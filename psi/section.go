@@ -0,0 +1,363 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"github.com/Comcast/gots/v3"
+)
+
+// longSectionHeaderLen is the length of a long form section header, from
+// the table_id through the last_section_number.
+const longSectionHeaderLen = 8
+
+// Section is a single long form PSI section, starting at the table_id and
+// ending with the CRC.
+type Section []byte
+
+// NewSection creates a Section from bytes starting at the table_id. Bytes
+// past the end of the section are dropped. An error is returned if the bytes
+// are not a complete long form section or, unless WithoutCRCCheck is given,
+// if the CRC does not match.
+func NewSection(b []byte, options ...func(*ParseOptions)) (Section, error) {
+	return newSection(b, NewParseOptions(options...))
+}
+
+func newSection(b []byte, options ParseOptions) (Section, error) {
+	if len(b) < 3 {
+		return nil, gots.ErrShortPayload
+	}
+	end := 3 + int(sectionLength(b))
+	if !sectionSyntaxIndicator(b) || end < longSectionHeaderLen+int(CrcLen) {
+		return nil, gots.ErrInvalidSection
+	}
+	if len(b) < end {
+		return nil, gots.ErrShortPayload
+	}
+	if err := options.CheckCRC(b); err != nil {
+		return nil, err
+	}
+	return Section(b[:end]), nil
+}
+
+// SplitSections returns the sections, long or short form, found in PSI
+// bytes starting with the pointer field. Splitting stops at stuffing bytes or
+// at a section that is incomplete.
+func SplitSections(psi []byte) [][]byte {
+	if len(psi) < 1 || len(psi) < 1+int(PointerField(psi)) {
+		return nil
+	}
+	var sections [][]byte
+	b := psi[1+PointerField(psi):]
+	for len(b) > 2 && b[0] != 0xFF {
+		end := 3 + int(sectionLength(b))
+		if len(b) < end {
+			break
+		}
+		sections = append(sections, b[:end])
+		b = b[end:]
+	}
+	return sections
+}
+
+// ParseSections returns the long form sections in PSI bytes, starting with
+// the pointer field, whose table_id is accepted by match. The CRC of each
+// matching section is validated unless WithoutCRCCheck is given.
+// gots.ErrUnknownTableID is returned if no section matches.
+func ParseSections(psi []byte, match func(tableID uint8) bool, options ...func(*ParseOptions)) ([]Section, error) {
+	return parseSections(psi, match, NewParseOptions(options...))
+}
+
+// parseSections returns the long form sections in PSI bytes, starting with
+// the pointer field, whose table_id is accepted by match. The CRC of each
+// matching section is validated unless disabled by the options.
+// gots.ErrUnknownTableID is returned if no section matches.
+func parseSections(psi []byte, match func(tableID uint8) bool, options ParseOptions) ([]Section, error) {
+	var sections []Section
+	for _, b := range SplitSections(psi) {
+		if !match(b[0]) {
+			continue
+		}
+		s, err := newSection(b, options)
+		if err != nil {
+			return nil, err
+		}
+		sections = append(sections, s)
+	}
+	if len(sections) == 0 {
+		return nil, gots.ErrUnknownTableID
+	}
+	return sections, nil
+}
+
+// TableID returns the table_id of the section.
+func (s Section) TableID() uint8 {
+	return s[0]
+}
+
+// TableIDExtension returns the table_id_extension of the section, which is
+// the transport_stream_id for a PAT and the program_number for a PMT.
+func (s Section) TableIDExtension() uint16 {
+	return uint16(s[3])<<8 | uint16(s[4])
+}
+
+// VersionNumber returns the version_number of the section.
+func (s Section) VersionNumber() uint8 {
+	return s[5] & 0x3E >> 1
+}
+
+// CurrentNextIndicator returns true if the section is currently applicable.
+func (s Section) CurrentNextIndicator() bool {
+	return s[5]&0x01 != 0
+}
+
+// SectionNumber returns the section_number of the section.
+func (s Section) SectionNumber() uint8 {
+	return s[6]
+}
+
+// LastSectionNumber returns the section_number of the last section of the table.
+func (s Section) LastSectionNumber() uint8 {
+	return s[7]
+}
+
+// Data returns the section contents following the header, excluding the CRC.
+func (s Section) Data() []byte {
+	return s[longSectionHeaderLen : len(s)-int(CrcLen)]
+}
+
+// Table is a complete table assembled from all of its sections.
+type Table struct {
+	TableID          uint8
+	TableIDExtension uint16
+	// TransportStreamID and OriginalNetworkID identify the transport stream
+	// of DVB SDT and EIT tables and are zero for other tables.
+	TransportStreamID uint16
+	OriginalNetworkID uint16
+	VersionNumber     uint8
+	// Sections holds the sections of the table ordered by section_number.
+	// Sections missing from the segments of an EIT schedule are left out.
+	Sections []Section
+}
+
+// Bytes returns the sections of the table in PSI bytes starting with a
+// pointer field, as accepted by the table constructors such as NewPMT.
+func (t *Table) Bytes() []byte {
+	b := NewPointerField(0)
+	for _, s := range t.Sections {
+		b = append(b, s...)
+	}
+	return b
+}
+
+// SectionCollector assembles tables that span multiple sections. Tables are
+// identified by their table_id and table_id_extension and, for the DVB SDT
+// and EIT, by the transport_stream_id and original_network_id they describe.
+// The sections of an EIT schedule are grouped in segments of 8 sections, a
+// segment is complete once its segment_last_section_number is received.
+type SectionCollector interface {
+	// Add adds a section. When the section completes a table, or a new
+	// version of a table, the table is returned. Otherwise Add returns nil.
+	// Sections with the current_next_indicator unset are ignored.
+	Add(s Section) *Table
+	// Table returns the last complete table with the given table_id and
+	// table_id_extension, or nil if there is none. The transport_stream_id
+	// and original_network_id of DVB tables are taken to be zero, except for
+	// the transport_stream_id of a SDT which is its table_id_extension.
+	Table(tableID uint8, tableIDExtension uint16) *Table
+	// DVBTable returns the last complete DVB SDT or EIT table with the given
+	// identifiers, or nil if there is none.
+	DVBTable(tableID uint8, tableIDExtension, transportStreamID, originalNetworkID uint16) *Table
+	// Reset forgets all tables.
+	Reset()
+}
+
+type tableKey struct {
+	tableID           uint8
+	tableIDExtension  uint16
+	transportStreamID uint16
+	originalNetworkID uint16
+}
+
+// newTableKey returns the key of a table. The transport_stream_id of a SDT
+// is its table_id_extension.
+func newTableKey(tableID uint8, tableIDExtension, transportStreamID, originalNetworkID uint16) tableKey {
+	if isSDTTableID(tableID) {
+		transportStreamID = tableIDExtension
+	}
+	return tableKey{tableID, tableIDExtension, transportStreamID, originalNetworkID}
+}
+
+// sectionKey returns the key of the table a section belongs to. The SDT
+// carries the original_network_id at the start of its data, the EIT carries
+// the transport_stream_id followed by the original_network_id.
+func sectionKey(s Section) tableKey {
+	var tsid, onid uint16
+	data := s.Data()
+	switch {
+	case isSDTTableID(s.TableID()) && len(data) >= 2:
+		onid = uint16(data[0])<<8 | uint16(data[1])
+	case isEITTableID(s.TableID()) && len(data) >= 4:
+		tsid = uint16(data[0])<<8 | uint16(data[1])
+		onid = uint16(data[2])<<8 | uint16(data[3])
+	}
+	return newTableKey(s.TableID(), s.TableIDExtension(), tsid, onid)
+}
+
+// eitSegmentLen is the number of sections in a segment of an EIT schedule.
+const eitSegmentLen = 8
+
+type partialTable struct {
+	version  uint8
+	sections []Section
+	// segmentLast holds the segment_last_section_number of the segments of
+	// an EIT schedule that have been received.
+	segmentLast map[int]int
+}
+
+// complete returns true once all sections of the table are received. The
+// sections of an EIT schedule segment following its
+// segment_last_section_number are not expected.
+func (p *partialTable) complete() bool {
+	if p.segmentLast == nil {
+		for _, s := range p.sections {
+			if s == nil {
+				return false
+			}
+		}
+		return true
+	}
+	for first := 0; first < len(p.sections); first += eitSegmentLen {
+		last, ok := p.segmentLast[first/eitSegmentLen]
+		if !ok {
+			return false
+		}
+		for n := first; n <= last; n++ {
+			if p.sections[n] == nil {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// receivedSections returns the received sections ordered by section_number.
+func (p *partialTable) receivedSections() []Section {
+	sections := make([]Section, 0, len(p.sections))
+	for _, s := range p.sections {
+		if s != nil {
+			sections = append(sections, s)
+		}
+	}
+	return sections
+}
+
+type sectionCollector struct {
+	partial  map[tableKey]*partialTable
+	complete map[tableKey]*Table
+}
+
+// NewSectionCollector creates a new SectionCollector.
+func NewSectionCollector() SectionCollector {
+	c := &sectionCollector{}
+	c.Reset()
+	return c
+}
+
+func (c *sectionCollector) Reset() {
+	c.partial = make(map[tableKey]*partialTable)
+	c.complete = make(map[tableKey]*Table)
+}
+
+func (c *sectionCollector) Table(tableID uint8, tableIDExtension uint16) *Table {
+	return c.DVBTable(tableID, tableIDExtension, 0, 0)
+}
+
+func (c *sectionCollector) DVBTable(tableID uint8, tableIDExtension, transportStreamID, originalNetworkID uint16) *Table {
+	return c.complete[newTableKey(tableID, tableIDExtension, transportStreamID, originalNetworkID)]
+}
+
+func (c *sectionCollector) Add(s Section) *Table {
+	if !s.CurrentNextIndicator() || s.SectionNumber() > s.LastSectionNumber() {
+		return nil
+	}
+	key := sectionKey(s)
+
+	// A repeated section of the current version changes nothing.
+	if t, ok := c.complete[key]; ok && t.VersionNumber == s.VersionNumber() {
+		return nil
+	}
+
+	p, ok := c.partial[key]
+	if !ok || p.version != s.VersionNumber() || len(p.sections) != int(s.LastSectionNumber())+1 {
+		p = &partialTable{
+			version:  s.VersionNumber(),
+			sections: make([]Section, int(s.LastSectionNumber())+1),
+		}
+		if isEITScheduleTableID(s.TableID()) {
+			p.segmentLast = make(map[int]int)
+		}
+		c.partial[key] = p
+	}
+	p.sections[s.SectionNumber()] = s
+	if p.segmentLast != nil {
+		p.segmentLast[int(s.SectionNumber())/eitSegmentLen] = segmentLastSectionNumber(s)
+	}
+
+	if !p.complete() {
+		return nil
+	}
+	delete(c.partial, key)
+	t := &Table{
+		TableID:           key.tableID,
+		TableIDExtension:  key.tableIDExtension,
+		TransportStreamID: key.transportStreamID,
+		OriginalNetworkID: key.originalNetworkID,
+		VersionNumber:     p.version,
+		Sections:          p.receivedSections(),
+	}
+	c.complete[key] = t
+	return t
+}
+
+// segmentLastSectionNumber returns the segment_last_section_number of an EIT
+// schedule section, limited to the segment of the section and to the
+// last_section_number.
+func segmentLastSectionNumber(s Section) int {
+	number := int(s.SectionNumber())
+	last := number
+	if data := s.Data(); len(data) >= 5 {
+		last = int(data[4])
+	}
+	if max := number - number%eitSegmentLen + eitSegmentLen - 1; last > max {
+		last = max
+	}
+	if last > int(s.LastSectionNumber()) {
+		last = int(s.LastSectionNumber())
+	}
+	if last < number {
+		last = number
+	}
+	return last
+}
@@ -0,0 +1,219 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/Comcast/gots/v3"
+)
+
+// makeSection builds a long form section with a valid CRC.
+func makeSection(tableID uint8, ext uint16, version, number, last uint8, data []byte) []byte {
+	length := longSectionHeaderLen - 3 + len(data) + int(CrcLen)
+	b := []byte{
+		tableID, 0xB0 | byte(length>>8), byte(length),
+		byte(ext >> 8), byte(ext),
+		0xC1 | version<<1,
+		number, last,
+	}
+	b = append(b, data...)
+	return append(b, gots.ComputeCRC(b)...)
+}
+
+func TestNewSection(t *testing.T) {
+	b := makeSection(0x42, 7, 3, 1, 2, []byte{1, 2, 3})
+	s, err := NewSection(append(b, 0xFF, 0xFF))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if s.TableID() != 0x42 || s.TableIDExtension() != 7 || s.VersionNumber() != 3 ||
+		!s.CurrentNextIndicator() || s.SectionNumber() != 1 || s.LastSectionNumber() != 2 {
+		t.Errorf("Unexpected section header %X", s)
+	}
+	if !bytes.Equal(s.Data(), []byte{1, 2, 3}) {
+		t.Errorf("Unexpected section data %X", s.Data())
+	}
+
+	if _, err := NewSection(b[:len(b)-1]); err != gots.ErrShortPayload {
+		t.Errorf("Expected ErrShortPayload, got %v", err)
+	}
+	b[9]++
+	if _, err := NewSection(b); !errors.Is(err, gots.ErrCRCMismatch) {
+		t.Errorf("Expected ErrCRCMismatch, got %v", err)
+	}
+	if _, err := NewSection(b, WithoutCRCCheck); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := NewSection([]byte{0x70, 0x70, 0x05, 0, 0, 0, 0, 0}); err != gots.ErrInvalidSection {
+		t.Errorf("Expected ErrInvalidSection, got %v", err)
+	}
+}
+
+func TestSplitSections(t *testing.T) {
+	first := makeSection(0x42, 1, 0, 0, 1, []byte{1})
+	second := makeSection(0x42, 1, 0, 1, 1, []byte{2, 2})
+	psi := append([]byte{2, 0xAA, 0xBB}, first...)
+	psi = append(psi, second...)
+	psi = append(psi, 0xFF, 0xFF, 0xFF)
+
+	sections := SplitSections(psi)
+	if len(sections) != 2 || !bytes.Equal(sections[0], first) || !bytes.Equal(sections[1], second) {
+		t.Errorf("Unexpected sections %X", sections)
+	}
+	if sections := SplitSections(psi[:len(psi)-5]); len(sections) != 1 {
+		t.Errorf("Expected the incomplete section to be dropped, got %X", sections)
+	}
+}
+
+func TestSectionCollector(t *testing.T) {
+	c := NewSectionCollector()
+	add := func(b []byte) *Table {
+		s, err := NewSection(b)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return c.Add(s)
+	}
+
+	if add(makeSection(0x42, 1, 0, 2, 2, []byte{2})) != nil {
+		t.Error("Table complete after 1 of 3 sections")
+	}
+	if add(makeSection(0x42, 1, 0, 0, 2, []byte{0})) != nil {
+		t.Error("Table complete after 2 of 3 sections")
+	}
+	if add(makeSection(0x42, 1, 0, 0, 2, []byte{0})) != nil {
+		t.Error("Table complete after a repeated section")
+	}
+	// A different table_id_extension is a different table
+	if add(makeSection(0x42, 2, 0, 1, 2, []byte{1})) != nil {
+		t.Error("Sections of different tables were combined")
+	}
+	table := add(makeSection(0x42, 1, 0, 1, 2, []byte{1}))
+	if table == nil {
+		t.Fatal("Expected a complete table")
+	}
+	if table.TableID != 0x42 || table.TableIDExtension != 1 || table.VersionNumber != 0 || len(table.Sections) != 3 {
+		t.Errorf("Unexpected table %+v", table)
+	}
+	for i, s := range table.Sections {
+		if s.SectionNumber() != uint8(i) || s.Data()[0] != byte(i) {
+			t.Errorf("Unexpected section %d: %X", i, s)
+		}
+	}
+
+	// Repetitions of a complete table do not return it again
+	if add(makeSection(0x42, 1, 0, 1, 2, []byte{1})) != nil {
+		t.Error("Repeated table returned")
+	}
+
+	// Sections that are not yet applicable are ignored
+	next := makeSection(0x42, 1, 1, 0, 0, []byte{9})
+	next[5] &^= 0x01
+	next = append(next[:len(next)-4], gots.ComputeCRC(next[:len(next)-4])...)
+	if add(next) != nil {
+		t.Error("Table returned for a section that is not current")
+	}
+
+	// A new version with a different number of sections
+	table = add(makeSection(0x42, 1, 1, 0, 0, []byte{9}))
+	if table == nil || table.VersionNumber != 1 || len(table.Sections) != 1 {
+		t.Errorf("Expected version 1 of the table, got %+v", table)
+	}
+	if c.Table(0x42, 1) != table {
+		t.Error("Table does not return the latest table")
+	}
+	if c.Table(0x42, 2) != nil {
+		t.Error("Table returned an incomplete table")
+	}
+
+	c.Reset()
+	if c.Table(0x42, 1) != nil {
+		t.Error("Reset did not forget the table")
+	}
+}
+
+func TestSectionCollectorDVBTables(t *testing.T) {
+	c := NewSectionCollector()
+	add := func(b []byte) *Table {
+		s, err := NewSection(b)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return c.Add(s)
+	}
+
+	// SDTs of the same transport stream on different networks
+	if add(makeSection(0x42, 1, 0, 0, 1, []byte{0x00, 0x01, 0xFF})) != nil {
+		t.Error("Table complete after 1 of 2 sections")
+	}
+	if add(makeSection(0x42, 1, 0, 1, 1, []byte{0x00, 0x02, 0xFF})) != nil {
+		t.Error("Sections of different networks were combined")
+	}
+	sdt := add(makeSection(0x42, 1, 0, 1, 1, []byte{0x00, 0x01, 0xFF}))
+	if sdt == nil || sdt.TransportStreamID != 1 || sdt.OriginalNetworkID != 1 {
+		t.Fatalf("Unexpected SDT %+v", sdt)
+	}
+	if c.Table(0x42, 1) != nil || c.DVBTable(0x42, 1, 0, 1) != sdt {
+		t.Error("Unexpected SDT lookup")
+	}
+
+	// An EIT schedule with segments 0 and 2 ending after 2 and 1 sections
+	// and an empty segment 1
+	eit := func(number, segmentLast uint8) []byte {
+		return makeSection(0x50, 7, 0, number, 16, []byte{0x00, 0x03, 0x00, 0x04, segmentLast, 0x50})
+	}
+	for _, b := range [][]byte{eit(0, 1), eit(8, 8), eit(16, 16)} {
+		if add(b) != nil {
+			t.Error("EIT schedule complete before all segments are complete")
+		}
+	}
+	schedule := add(eit(1, 1))
+	if schedule == nil || len(schedule.Sections) != 4 || schedule.TransportStreamID != 3 || schedule.OriginalNetworkID != 4 {
+		t.Fatalf("Unexpected EIT schedule %+v", schedule)
+	}
+	if schedule.Sections[2].SectionNumber() != 8 || c.DVBTable(0x50, 7, 3, 4) != schedule {
+		t.Errorf("Unexpected EIT schedule sections %X", schedule.Sections)
+	}
+}
+
+func TestMultiSectionPMT(t *testing.T) {
+	pmtData := func(pid int) []byte {
+		return []byte{0xE1, 0x00, 0xF0, 0x00, 0x1b, 0xE0 | byte(pid>>8), byte(pid), 0xF0, 0x00}
+	}
+	psi := []byte{0}
+	psi = append(psi, makeSection(0x02, 1, 0, 0, 1, pmtData(0x100))...)
+	psi = append(psi, makeSection(0x02, 1, 0, 1, 1, pmtData(0x101))...)
+
+	pmt, err := NewPMT(psi)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if pids := pmt.Pids(); len(pids) != 2 || pids[0] != 0x100 || pids[1] != 0x101 {
+		t.Errorf("Expected the streams of both sections, got %v", pids)
+	}
+}
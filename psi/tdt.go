@@ -0,0 +1,189 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"time"
+
+	"github.com/Comcast/gots/v3"
+)
+
+// Time table ids
+const (
+	TdtTableID = 0x70
+	TotTableID = 0x73
+)
+
+const (
+	// timeTableLen is the length of a TDT section and the minimum length of
+	// a TOT section: the header and UTC_time.
+	timeTableLen = 8
+)
+
+// TDT is a DVB Time and Date Table, carrying the current UTC time.
+// See ETSI EN 300 468 section 5.2.5.
+type TDT interface {
+	UTCTime() time.Time
+}
+
+// TOT is a DVB Time Offset Table, carrying the current UTC time and the
+// local time offsets. See ETSI EN 300 468 section 5.2.6.
+type TOT interface {
+	TDT
+	Descriptors() []PmtDescriptor
+	// LocalTimeOffsets returns the decoded local_time_offset_descriptors, or
+	// gots.ErrDescriptorNotFound if there are none.
+	LocalTimeOffsets() ([]LocalTimeOffset, error)
+}
+
+// LocalTimeOffset is a region of a local_time_offset_descriptor.
+type LocalTimeOffset struct {
+	CountryCode     string
+	CountryRegionID uint8
+	// Offset is the current offset of local time from UTC.
+	Offset time.Duration
+	// TimeOfChange is the UTC time when Offset changes to NextOffset.
+	TimeOfChange time.Time
+	NextOffset   time.Duration
+}
+
+type tdt struct {
+	utcTime time.Time
+}
+
+type tot struct {
+	tdt
+	descriptors []PmtDescriptor
+}
+
+// timeTableSection returns the first section in PSI bytes, starting with the
+// pointer field, with the given table_id.
+func timeTableSection(psi []byte, tableID uint8) ([]byte, error) {
+	for _, s := range SplitSections(psi) {
+		if s[0] != tableID {
+			continue
+		}
+		if len(s) < timeTableLen {
+			return nil, gots.ErrShortPayload
+		}
+		return s, nil
+	}
+	return nil, gots.ErrUnknownTableID
+}
+
+// NewTDT creates a new TDT from the given bytes, which should be packet
+// payload contents starting with the pointer field. The TDT carries no CRC.
+func NewTDT(tdtBytes []byte) (TDT, error) {
+	s, err := timeTableSection(tdtBytes, TdtTableID)
+	if err != nil {
+		return nil, err
+	}
+	return &tdt{utcTime: DecodeDVBTime(s[3:8])}, nil
+}
+
+// NewTOT creates a new TOT from the given bytes, which should be packet
+// payload contents starting with the pointer field. The CRC is validated
+// unless WithoutCRCCheck is given.
+func NewTOT(totBytes []byte, options ...func(*ParseOptions)) (TOT, error) {
+	s, err := timeTableSection(totBytes, TotTableID)
+	if err != nil {
+		return nil, err
+	}
+	if len(s) < timeTableLen+2+int(CrcLen) {
+		return nil, gots.ErrShortPayload
+	}
+	if err := NewParseOptions(options...).CheckCRC(s); err != nil {
+		return nil, err
+	}
+	length, err := loopLength(s[timeTableLen : len(s)-int(CrcLen)])
+	if err != nil {
+		return nil, err
+	}
+	descriptors, err := ParseDescriptors(s[timeTableLen+2 : timeTableLen+2+length])
+	if err != nil {
+		return nil, err
+	}
+	return &tot{
+		tdt:         tdt{utcTime: DecodeDVBTime(s[3:8])},
+		descriptors: descriptors,
+	}, nil
+}
+
+func (t *tdt) UTCTime() time.Time {
+	return t.utcTime
+}
+
+func (t *tot) Descriptors() []PmtDescriptor {
+	return t.descriptors
+}
+
+func (t *tot) LocalTimeOffsets() ([]LocalTimeOffset, error) {
+	var offsets []LocalTimeOffset
+	for _, d := range t.descriptors {
+		if d.Tag() != LOCAL_TIME_OFFSET {
+			continue
+		}
+		o, err := DecodeLocalTimeOffsetDescriptor(d)
+		if err != nil {
+			return nil, err
+		}
+		offsets = append(offsets, o...)
+	}
+	if len(offsets) == 0 {
+		return nil, gots.ErrDescriptorNotFound
+	}
+	return offsets, nil
+}
+
+// DecodeLocalTimeOffsetDescriptor decodes a local_time_offset_descriptor.
+func DecodeLocalTimeOffsetDescriptor(d PmtDescriptor) ([]LocalTimeOffset, error) {
+	data := d.Data()
+	if d.Tag() != LOCAL_TIME_OFFSET || len(data)%13 != 0 {
+		return nil, gots.ErrParsePMTDescriptor
+	}
+	offsets := make([]LocalTimeOffset, 0, len(data)/13)
+	for i := 0; i < len(data); i += 13 {
+		e := data[i : i+13]
+		o := LocalTimeOffset{
+			CountryCode:     string(e[0:3]),
+			CountryRegionID: e[3] >> 2,
+			Offset:          decodeTimeOffset(e[4:6]),
+			TimeOfChange:    DecodeDVBTime(e[6:11]),
+			NextOffset:      decodeTimeOffset(e[11:13]),
+		}
+		// local_time_offset_polarity
+		if e[3]&0x01 != 0 {
+			o.Offset, o.NextOffset = -o.Offset, -o.NextOffset
+		}
+		offsets = append(offsets, o)
+	}
+	return offsets, nil
+}
+
+// decodeTimeOffset decodes a 16 bit time offset of four 4-bit BCD digits
+// of hours and minutes.
+func decodeTimeOffset(b []byte) time.Duration {
+	return time.Duration(decodeBCD(b[0:1], 2))*time.Hour + time.Duration(decodeBCD(b[1:2], 2))*time.Minute
+}
@@ -0,0 +1,161 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Comcast/gots/v3"
+	"github.com/Comcast/gots/v3/packet"
+)
+
+var testUTC = time.Date(2024, time.March, 31, 0, 59, 58, 0, time.UTC)
+
+func tdtBytes(utc time.Time) []byte {
+	return append([]byte{0x00, TdtTableID, 0x70, 0x05}, EncodeDVBTime(utc)...)
+}
+
+func totBytes(utc time.Time, descriptors []byte) []byte {
+	length := 5 + 2 + len(descriptors) + int(CrcLen)
+	b := []byte{TotTableID, 0x70 | byte(length>>8), byte(length)}
+	b = append(b, EncodeDVBTime(utc)...)
+	b = append(b, withLoopLength(descriptors)...)
+	b = append(b, gots.ComputeCRC(b)...)
+	return append(NewPointerField(0), b...)
+}
+
+func TestNewTDT(t *testing.T) {
+	tdt, err := NewTDT(tdtBytes(testUTC))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !tdt.UTCTime().Equal(testUTC) {
+		t.Errorf("Expected %v, got %v", testUTC, tdt.UTCTime())
+	}
+	if _, err := NewTDT(tdtBytes(testUTC)[:6]); err != gots.ErrUnknownTableID {
+		t.Errorf("Expected ErrUnknownTableID, got %v", err)
+	}
+	if _, err := NewTDT(totBytes(testUTC, nil)); err != gots.ErrUnknownTableID {
+		t.Errorf("Expected ErrUnknownTableID, got %v", err)
+	}
+	if decoded, err := DecodeTable(tdtBytes(testUTC), WithoutCRCCheck); err != nil || !decoded.(TDT).UTCTime().Equal(testUTC) {
+		t.Errorf("Unexpected decoded TDT %v, %v", decoded, err)
+	}
+}
+
+func TestNewTOT(t *testing.T) {
+	lto := []byte{LOCAL_TIME_OFFSET, 26,
+		'G', 'B', 'R', 0x02, 0x00, 0x00, 0xEB, 0xF0, 0x01, 0x00, 0x00, 0x01, 0x00,
+		'U', 'S', 'A', 0x07, 0x05, 0x00, 0xEB, 0xDB, 0x02, 0x00, 0x00, 0x04, 0x00,
+	}
+	b := totBytes(testUTC, lto)
+	tot, err := NewTOT(b)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !tot.UTCTime().Equal(testUTC) || len(tot.Descriptors()) != 1 {
+		t.Errorf("Unexpected TOT %+v", tot)
+	}
+	offsets, err := tot.LocalTimeOffsets()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []LocalTimeOffset{
+		{"GBR", 0, 0, time.Date(2024, time.March, 31, 1, 0, 0, 0, time.UTC), time.Hour},
+		{"USA", 1, -5 * time.Hour, time.Date(2024, time.March, 10, 2, 0, 0, 0, time.UTC), -4 * time.Hour},
+	}
+	if len(offsets) != len(want) || offsets[0] != want[0] || offsets[1] != want[1] {
+		t.Errorf("Expected %+v, got %+v", want, offsets)
+	}
+
+	b[len(b)-1] ^= 0xFF
+	if _, err := NewTOT(b); !errors.Is(err, gots.ErrCRCMismatch) {
+		t.Errorf("Expected ErrCRCMismatch, got %v", err)
+	}
+	if _, err := NewTOT(b, WithoutCRCCheck); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	tot, err = NewTOT(totBytes(testUTC, nil))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := tot.LocalTimeOffsets(); err != gots.ErrDescriptorNotFound {
+		t.Errorf("Expected ErrDescriptorNotFound, got %v", err)
+	}
+}
+
+func TestTimeCorrelator(t *testing.T) {
+	c := NewTimeCorrelator(101)
+	if _, err := c.UTC(0); err != gots.ErrNoTimeReference {
+		t.Errorf("Expected ErrNoTimeReference, got %v", err)
+	}
+
+	write := func(pkt *packet.Packet) {
+		if _, err := c.WritePacket(pkt); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	// time tables before the first PCR can not be correlated
+	write(&NewSectionPacketizer(TdtPid, 0).Packetize(tdtBytes(testUTC.Add(-time.Hour))[1:])[0])
+
+	pcr := packet.Create(101)
+	pcr.SetAdaptationFieldControl(packet.AdaptationFieldFlag)
+	af, _ := pcr.AdaptationField()
+	af.SetHasPCR(true)
+	af.SetPCR(300 * 90000)
+	write(pcr)
+	if _, err := c.UTC(0); err != gots.ErrNoTimeReference {
+		t.Errorf("Expected ErrNoTimeReference, got %v", err)
+	}
+
+	write(&NewSectionPacketizer(TdtPid, 1).Packetize(totBytes(testUTC, nil)[1:])[0])
+	tests := []struct {
+		pts  gots.PTS
+		want time.Time
+	}{
+		{90000, testUTC},
+		{90000 * 3, testUTC.Add(2 * time.Second)},
+		{45000, testUTC.Add(-500 * time.Millisecond)},
+		// rolled over PTS
+		{gots.MaxPtsValue, testUTC.Add(-time.Second - time.Second/90000)},
+	}
+	for _, test := range tests {
+		got, err := c.UTC(test.pts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !got.Equal(test.want) {
+			t.Errorf("UTC(%d) = %v, want %v", test.pts, got, test.want)
+		}
+	}
+
+	c.SetReference(testUTC.Add(time.Hour), 0)
+	if got, _ := c.UTC(gots.MaxPtsValue - 89999); !got.Equal(testUTC.Add(time.Hour - time.Second)) {
+		t.Errorf("Unexpected UTC after rollover %v", got)
+	}
+}
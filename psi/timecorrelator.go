@@ -0,0 +1,118 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psi
+
+import (
+	"time"
+
+	"github.com/Comcast/gots/v3"
+	"github.com/Comcast/gots/v3/packet"
+)
+
+// TimeCorrelator maps presentation timestamps to UTC. Each TDT or TOT is
+// correlated with the most recent PCR of the program, and timestamps are
+// mapped relative to the latest correlation.
+type TimeCorrelator interface {
+	packet.PacketWriter
+	// SetReference correlates a UTC time with a 27MHz PCR value, as done
+	// for each TDT or TOT received.
+	SetReference(utc time.Time, pcr uint64)
+	// UTC returns the UTC time of a PTS. gots.ErrNoTimeReference is
+	// returned if no time table has been correlated yet.
+	UTC(pts gots.PTS) (time.Time, error)
+}
+
+type timeCorrelator struct {
+	pcrPid  int
+	pcr     uint64
+	hasPCR  bool
+	acc     packet.Accumulator
+	utc     time.Time
+	base    gots.PTS
+	hasBase bool
+}
+
+// NewTimeCorrelator creates a new TimeCorrelator for the program with the
+// given PCR PID. Packets of the PCR PID and of TdtPid should be written to
+// it.
+func NewTimeCorrelator(pcrPid int) TimeCorrelator {
+	return &timeCorrelator{
+		pcrPid: pcrPid,
+		acc:    packet.NewAccumulator(PmtAccumulatorDoneFunc),
+	}
+}
+
+func (c *timeCorrelator) WritePacket(pkt *packet.Packet) (int, error) {
+	pid := packet.Pid(pkt)
+	if pid == c.pcrPid {
+		if af, err := pkt.AdaptationField(); err == nil {
+			if pcr, err := af.PCR(); err == nil {
+				c.pcr, c.hasPCR = pcr, true
+			}
+		}
+	}
+	if pid == TdtPid {
+		if b, ok := accumulate(c.acc, pkt); ok && c.hasPCR {
+			if utc, err := timeTableUTC(b); err == nil {
+				c.SetReference(utc, c.pcr)
+			}
+		}
+	}
+	return packet.PacketSize, nil
+}
+
+// timeTableUTC returns the UTC time of a TDT or TOT.
+func timeTableUTC(b []byte) (time.Time, error) {
+	if TableID(b) == TotTableID {
+		tot, err := NewTOT(b)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return tot.UTCTime(), nil
+	}
+	tdt, err := NewTDT(b)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return tdt.UTCTime(), nil
+}
+
+func (c *timeCorrelator) SetReference(utc time.Time, pcr uint64) {
+	c.utc = utc
+	c.base = gots.PTS(pcr/300) & gots.MaxPtsValue
+	c.hasBase = true
+}
+
+func (c *timeCorrelator) UTC(pts gots.PTS) (time.Time, error) {
+	if !c.hasBase {
+		return time.Time{}, gots.ErrNoTimeReference
+	}
+	// the shortest distance on the 33 bit timeline, which may be negative
+	delta := int64((pts - c.base) & gots.MaxPtsValue)
+	if delta >= gots.MaxPtsTicks/2 {
+		delta -= gots.MaxPtsTicks
+	}
+	return c.utc.Add(time.Duration(delta) * time.Second / gots.PtsClockRate), nil
+}
@@ -0,0 +1,216 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psip
+
+import (
+	"time"
+
+	"github.com/Comcast/gots/v3"
+	"github.com/Comcast/gots/v3/psi"
+)
+
+// EIT is an ATSC Event Information Table, describing the events of a
+// virtual channel during a three hour time slot.
+type EIT interface {
+	SourceID() int
+	VersionNumber() uint8
+	CurrentNextIndicator() bool
+	Events() []Event
+}
+
+// Event is an event entry of an EIT.
+type Event struct {
+	EventID int
+	// StartTime is the number of GPS seconds since 1980-01-06 00:00:00 UTC.
+	StartTime   uint32
+	ETMLocation uint8
+	Length      time.Duration
+	Title       MultipleString
+	Descriptors []psi.PmtDescriptor
+}
+
+// UTCStartTime returns the start time converted to UTC, given the
+// GPS_UTC_offset of the current STT.
+func (e Event) UTCStartTime(gpsUTCOffset uint8) time.Time {
+	return GPSTime(e.StartTime, gpsUTCOffset)
+}
+
+// ETMID returns the ETM_id of the extended text message of the event on the
+// given source.
+func (e Event) ETMID(sourceID int) uint32 {
+	return ChannelETMID(sourceID) | uint32(e.EventID)<<2 | 0x2
+}
+
+// ChannelETMID returns the ETM_id of the extended text message of a
+// virtual channel.
+func ChannelETMID(sourceID int) uint32 {
+	return uint32(sourceID) << 16
+}
+
+type eit struct {
+	sourceID             int
+	versionNumber        uint8
+	currentNextIndicator bool
+	events               []Event
+}
+
+// NewEIT creates a new EIT from the given bytes, which should be
+// concatenated packet payload contents starting with the pointer field.
+// Events of all sections in the bytes are combined. The CRC of each section
+// is validated unless psi.WithoutCRCCheck is given. Titles that can not be
+// decoded, because of an unsupported compression, are left empty.
+func NewEIT(eitBytes []byte, options ...func(*psi.ParseOptions)) (EIT, error) {
+	sections, err := parseSections(eitBytes, EitTableID, options...)
+	if err != nil {
+		return nil, err
+	}
+	e := &eit{
+		sourceID:             int(sections[0].TableIDExtension()),
+		versionNumber:        sections[0].VersionNumber(),
+		currentNextIndicator: sections[0].CurrentNextIndicator(),
+	}
+	for _, s := range sections {
+		if err := e.parseSection(s.Data()[1:]); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+func (e *eit) parseSection(data []byte) error {
+	if len(data) < 1 {
+		return gots.ErrShortPayload
+	}
+	n := int(data[0])
+	data = data[1:]
+	for i := 0; i < n; i++ {
+		if len(data) < 10 || len(data) < 10+int(data[9]) {
+			return gots.ErrShortPayload
+		}
+		ev := Event{
+			EventID:     int(data[0]&0x3F)<<8 | int(data[1]),
+			StartTime:   uint32(data[2])<<24 | uint32(data[3])<<16 | uint32(data[4])<<8 | uint32(data[5]),
+			ETMLocation: data[6] >> 4 & 0x03,
+			Length:      time.Duration(int(data[6]&0x0F)<<16|int(data[7])<<8|int(data[8])) * time.Second,
+		}
+		title := data[10 : 10+int(data[9])]
+		if len(title) > 0 {
+			m, err := DecodeMultipleString(title)
+			if err != nil && err != gots.ErrUnsupportedStringCompression {
+				return err
+			}
+			ev.Title = m
+		}
+		var err error
+		ev.Descriptors, data, err = parseDescriptors(data[10+len(title):], 12)
+		if err != nil {
+			return err
+		}
+		e.events = append(e.events, ev)
+	}
+	return nil
+}
+
+func (e *eit) SourceID() int {
+	return e.sourceID
+}
+
+func (e *eit) VersionNumber() uint8 {
+	return e.versionNumber
+}
+
+func (e *eit) CurrentNextIndicator() bool {
+	return e.currentNextIndicator
+}
+
+func (e *eit) Events() []Event {
+	return e.events
+}
+
+// ETT is an Extended Text Table, carrying the extended text message of a
+// virtual channel or event.
+type ETT interface {
+	VersionNumber() uint8
+	// ETMID identifies the channel or event described, see ChannelETMID
+	// and Event.ETMID.
+	ETMID() uint32
+	// SourceID returns the source_id of the virtual channel.
+	SourceID() int
+	// EventID returns the event_id of the event, or false for the extended
+	// text message of a channel.
+	EventID() (int, bool)
+	// ExtendedText returns the decoded extended_text_message.
+	// gots.ErrUnsupportedStringCompression is returned if the message can
+	// not be decoded.
+	ExtendedText() (MultipleString, error)
+}
+
+type ett struct {
+	versionNumber uint8
+	etmID         uint32
+	text          []byte
+}
+
+// NewETT creates a new ETT from the given bytes, which should be
+// concatenated packet payload contents starting with the pointer field.
+// The CRC is validated unless psi.WithoutCRCCheck is given.
+func NewETT(ettBytes []byte, options ...func(*psi.ParseOptions)) (ETT, error) {
+	sections, err := parseSections(ettBytes, EttTableID, options...)
+	if err != nil {
+		return nil, err
+	}
+	data := sections[0].Data()[1:]
+	if len(data) < 5 {
+		return nil, gots.ErrShortPayload
+	}
+	return &ett{
+		versionNumber: sections[0].VersionNumber(),
+		etmID:         uint32(data[0])<<24 | uint32(data[1])<<16 | uint32(data[2])<<8 | uint32(data[3]),
+		text:          data[4:],
+	}, nil
+}
+
+func (e *ett) VersionNumber() uint8 {
+	return e.versionNumber
+}
+
+func (e *ett) ETMID() uint32 {
+	return e.etmID
+}
+
+func (e *ett) SourceID() int {
+	return int(e.etmID >> 16)
+}
+
+func (e *ett) EventID() (int, bool) {
+	if e.etmID&0x3 != 0x2 {
+		return 0, false
+	}
+	return int(e.etmID>>2) & 0x3FFF, true
+}
+
+func (e *ett) ExtendedText() (MultipleString, error) {
+	return DecodeMultipleString(e.text)
+}
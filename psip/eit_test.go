@@ -0,0 +1,94 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psip
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Comcast/gots/v3"
+)
+
+func TestNewEIT(t *testing.T) {
+	title := []byte{1, 'e', 'n', 'g', 1, CompressionNone, 0x00, 4, 'N', 'e', 'w', 's'}
+	huffman := []byte{1, 'e', 'n', 'g', 1, CompressionHuffmanC5, 0x00, 1, 0x00}
+	data := []byte{2,
+		0xC0, 0x05, 0x52, 0xBC, 0xC3, 0x12, 0xD0, 0x07, 0x08, byte(len(title))}
+	data = append(data, title...)
+	data = append(data, 0xF0, 0x00,
+		0xC0, 0x06, 0x52, 0xBC, 0xD1, 0x22, 0xC0, 0x0E, 0x10, byte(len(huffman)))
+	data = append(data, huffman...)
+	data = append(data, 0xF0, 0x03, 0x86, 0x01, 0x00)
+
+	eit, err := NewEIT(makeTable(EitTableID, 3, 2, data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if eit.SourceID() != 3 || eit.VersionNumber() != 2 || !eit.CurrentNextIndicator() || len(eit.Events()) != 2 {
+		t.Fatalf("Unexpected EIT %+v", eit)
+	}
+	ev := eit.Events()[0]
+	if ev.EventID != 5 || ev.ETMLocation != ETMInPTC || ev.Length != 30*time.Minute || ev.Title.String() != "News" ||
+		!ev.UTCStartTime(18).Equal(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected event %+v", ev)
+	}
+	if ev.ETMID(3) != 0x00030016 {
+		t.Errorf("Unexpected ETM_id 0x%08X", ev.ETMID(3))
+	}
+	ev = eit.Events()[1]
+	if ev.EventID != 6 || ev.ETMLocation != ETMNone || ev.Length != time.Hour || ev.Title != nil ||
+		len(ev.Descriptors) != 1 {
+		t.Errorf("Unexpected event %+v", ev)
+	}
+}
+
+func TestNewETT(t *testing.T) {
+	text := []byte{1, 'e', 'n', 'g', 1, CompressionNone, 0x00, 5, 'H', 'e', 'l', 'l', 'o'}
+	ett, err := NewETT(makeTable(EttTableID, 0, 1, append([]byte{0x00, 0x03, 0x00, 0x16}, text...)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ett.VersionNumber() != 1 || ett.ETMID() != 0x00030016 || ett.SourceID() != 3 {
+		t.Errorf("Unexpected ETT %+v", ett)
+	}
+	if id, ok := ett.EventID(); !ok || id != 5 {
+		t.Errorf("Unexpected event id %d", id)
+	}
+	m, err := ett.ExtendedText()
+	if err != nil || m.String() != "Hello" {
+		t.Errorf("Unexpected extended text %+v, %v", m, err)
+	}
+
+	ett, err = NewETT(makeTable(EttTableID, 0, 1, append([]byte{0x00, 0x03, 0x00, 0x00}, text...)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := ett.EventID(); ok || ett.ETMID() != ChannelETMID(3) {
+		t.Errorf("Expected a channel ETM, got 0x%08X", ett.ETMID())
+	}
+	if _, err := NewETT(makeTable(EttTableID, 0, 1, []byte{0x00, 0x03})); err != gots.ErrShortPayload {
+		t.Errorf("Expected ErrShortPayload, got %v", err)
+	}
+}
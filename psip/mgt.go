@@ -0,0 +1,147 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psip
+
+import (
+	"github.com/Comcast/gots/v3"
+	"github.com/Comcast/gots/v3/psi"
+)
+
+// MGT table types
+const (
+	TableTypeTVCTCurrent = 0x0000
+	TableTypeTVCTNext    = 0x0001
+	TableTypeCVCTCurrent = 0x0002
+	TableTypeCVCTNext    = 0x0003
+	TableTypeChannelETT  = 0x0004
+	TableTypeDCCSCT      = 0x0005
+	TableTypeEITFirst    = 0x0100
+	TableTypeEITLast     = 0x017F
+	TableTypeETTFirst    = 0x0200
+	TableTypeETTLast     = 0x027F
+	TableTypeRRTFirst    = 0x0301
+	TableTypeRRTLast     = 0x03FF
+	TableTypeDCCTFirst   = 0x1400
+	TableTypeDCCTLast    = 0x14FF
+)
+
+// MGT is a Master Guide Table, listing the PIDs and versions of all other
+// PSIP tables except the STT.
+type MGT interface {
+	VersionNumber() uint8
+	Tables() []MGTTable
+	Descriptors() []psi.PmtDescriptor
+	// TablePID returns the PID of the table with the given table type.
+	TablePID(tableType uint16) (int, bool)
+	// EITPids returns the PIDs of EIT-0 and onwards, in order.
+	EITPids() []int
+}
+
+// MGTTable is a table entry of an MGT.
+type MGTTable struct {
+	Type          uint16
+	PID           int
+	VersionNumber uint8
+	NumberBytes   uint32
+	Descriptors   []psi.PmtDescriptor
+}
+
+type mgt struct {
+	versionNumber uint8
+	tables        []MGTTable
+	descriptors   []psi.PmtDescriptor
+}
+
+// NewMGT creates a new MGT from the given bytes, which should be
+// concatenated packet payload contents starting with the pointer field.
+// The CRC is validated unless psi.WithoutCRCCheck is given.
+func NewMGT(mgtBytes []byte, options ...func(*psi.ParseOptions)) (MGT, error) {
+	sections, err := parseSections(mgtBytes, MgtTableID, options...)
+	if err != nil {
+		return nil, err
+	}
+	m := &mgt{versionNumber: sections[0].VersionNumber()}
+	// the MGT is a single section table
+	data := sections[0].Data()[1:]
+	if len(data) < 2 {
+		return nil, gots.ErrShortPayload
+	}
+	n := int(data[0])<<8 | int(data[1])
+	data = data[2:]
+	for i := 0; i < n; i++ {
+		if len(data) < 9 {
+			return nil, gots.ErrShortPayload
+		}
+		t := MGTTable{
+			Type:          uint16(data[0])<<8 | uint16(data[1]),
+			PID:           int(data[2]&0x1F)<<8 | int(data[3]),
+			VersionNumber: data[4] & 0x1F,
+			NumberBytes:   uint32(data[5])<<24 | uint32(data[6])<<16 | uint32(data[7])<<8 | uint32(data[8]),
+		}
+		t.Descriptors, data, err = parseDescriptors(data[9:], 12)
+		if err != nil {
+			return nil, err
+		}
+		m.tables = append(m.tables, t)
+	}
+	m.descriptors, _, err = parseDescriptors(data, 12)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *mgt) VersionNumber() uint8 {
+	return m.versionNumber
+}
+
+func (m *mgt) Tables() []MGTTable {
+	return m.tables
+}
+
+func (m *mgt) Descriptors() []psi.PmtDescriptor {
+	return m.descriptors
+}
+
+func (m *mgt) TablePID(tableType uint16) (int, bool) {
+	for _, t := range m.tables {
+		if t.Type == tableType {
+			return t.PID, true
+		}
+	}
+	return 0, false
+}
+
+func (m *mgt) EITPids() []int {
+	var pids []int
+	for tableType := uint16(TableTypeEITFirst); tableType <= TableTypeEITLast; tableType++ {
+		pid, ok := m.TablePID(tableType)
+		if !ok {
+			break
+		}
+		pids = append(pids, pid)
+	}
+	return pids
+}
@@ -0,0 +1,64 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psip
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewMGT(t *testing.T) {
+	data := []byte{
+		0x00, 0x03,
+		0x00, 0x00, 0xFF, 0xFB, 0xE1, 0x00, 0x00, 0x01, 0x00, 0xF0, 0x00,
+		0x01, 0x00, 0xE1, 0xD0, 0xE2, 0x00, 0x00, 0x02, 0x00, 0xF0, 0x03, 0x80, 0x01, 0xAA,
+		0x01, 0x01, 0xE1, 0xD1, 0xE3, 0x00, 0x00, 0x03, 0x00, 0xF0, 0x00,
+		0xF0, 0x00,
+	}
+	mgt, err := NewMGT(makeTable(MgtTableID, 0, 4, data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if mgt.VersionNumber() != 4 || len(mgt.Tables()) != 3 || len(mgt.Descriptors()) != 0 {
+		t.Errorf("Unexpected MGT %+v", mgt)
+	}
+	tvct := mgt.Tables()[0]
+	if tvct.Type != TableTypeTVCTCurrent || tvct.PID != BasePid || tvct.VersionNumber != 1 || tvct.NumberBytes != 0x100 {
+		t.Errorf("Unexpected table %+v", tvct)
+	}
+	eit0 := mgt.Tables()[1]
+	if eit0.VersionNumber != 2 || len(eit0.Descriptors) != 1 || eit0.Descriptors[0].Tag() != 0x80 {
+		t.Errorf("Unexpected table %+v", eit0)
+	}
+	if pid, ok := mgt.TablePID(TableTypeEITFirst); !ok || pid != 0x1D0 {
+		t.Errorf("Unexpected EIT-0 PID %d", pid)
+	}
+	if _, ok := mgt.TablePID(TableTypeCVCTCurrent); ok {
+		t.Error("Expected no CVCT")
+	}
+	if pids := mgt.EITPids(); !reflect.DeepEqual(pids, []int{0x1D0, 0x1D1}) {
+		t.Errorf("Unexpected EIT PIDs %v", pids)
+	}
+}
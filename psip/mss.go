@@ -0,0 +1,129 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psip
+
+import (
+	"strings"
+
+	"github.com/Comcast/gots/v3"
+)
+
+// Compression types of a multiple_string_structure segment.
+const (
+	CompressionNone      = 0x00
+	CompressionHuffmanC4 = 0x01
+	CompressionHuffmanC5 = 0x02
+)
+
+// Modes of a multiple_string_structure segment. Modes 0x00 to 0x33 select
+// the upper byte of the Unicode code points of the segment's characters.
+const (
+	ModeSCSU  = 0x3E
+	ModeUTF16 = 0x3F
+)
+
+// LanguageString is a string of a multiple_string_structure.
+type LanguageString struct {
+	// Language is the ISO 639.2 language code.
+	Language string
+	Text     string
+}
+
+// MultipleString is a decoded multiple_string_structure, holding the same
+// text in one or more languages.
+type MultipleString []LanguageString
+
+// String returns the text of the first string, or "" if there is none.
+func (m MultipleString) String() string {
+	if len(m) == 0 {
+		return ""
+	}
+	return m[0].Text
+}
+
+// Text returns the text for the given ISO 639.2 language code.
+func (m MultipleString) Text(language string) (string, bool) {
+	for _, s := range m {
+		if s.Language == language {
+			return s.Text, true
+		}
+	}
+	return "", false
+}
+
+// DecodeMultipleString decodes a multiple_string_structure as defined in
+// ATSC A/65 section 6.10. Only uncompressed segments using a Unicode upper
+// byte mode or UTF-16 are supported; gots.ErrUnsupportedStringCompression
+// is returned for Huffman compressed or SCSU segments.
+func DecodeMultipleString(b []byte) (MultipleString, error) {
+	m, _, err := decodeMultipleString(b)
+	return m, err
+}
+
+// decodeMultipleString decodes a multiple_string_structure at the start of b
+// and returns the remainder of b.
+func decodeMultipleString(b []byte) (MultipleString, []byte, error) {
+	if len(b) < 1 {
+		return nil, nil, gots.ErrShortPayload
+	}
+	n := int(b[0])
+	b = b[1:]
+	m := make(MultipleString, 0, n)
+	for i := 0; i < n; i++ {
+		if len(b) < 4 {
+			return nil, nil, gots.ErrShortPayload
+		}
+		s := LanguageString{Language: string(b[0:3])}
+		segments := int(b[3])
+		b = b[4:]
+		var sb strings.Builder
+		for j := 0; j < segments; j++ {
+			if len(b) < 3 || len(b) < 3+int(b[2]) {
+				return nil, nil, gots.ErrShortPayload
+			}
+			if err := decodeSegment(&sb, b[0], b[1], b[3:3+int(b[2])]); err != nil {
+				return nil, nil, err
+			}
+			b = b[3+int(b[2]):]
+		}
+		s.Text = sb.String()
+		m = append(m, s)
+	}
+	return m, b, nil
+}
+
+func decodeSegment(sb *strings.Builder, compression, mode byte, b []byte) error {
+	if compression != CompressionNone || mode == ModeSCSU || (mode > 0x33 && mode != ModeUTF16) {
+		return gots.ErrUnsupportedStringCompression
+	}
+	if mode == ModeUTF16 {
+		sb.WriteString(decodeUTF16(b))
+		return nil
+	}
+	for _, c := range b {
+		sb.WriteRune(rune(mode)<<8 | rune(c))
+	}
+	return nil
+}
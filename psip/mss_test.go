@@ -0,0 +1,66 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psip
+
+import (
+	"testing"
+
+	"github.com/Comcast/gots/v3"
+)
+
+func TestDecodeMultipleString(t *testing.T) {
+	b := []byte{
+		2,
+		'e', 'n', 'g', 2,
+		CompressionNone, 0x00, 4, 'C', 'a', 'f', 0xE9,
+		CompressionNone, ModeUTF16, 4, 0x00, '!', 0x26, 0x3A,
+		's', 'p', 'a', 1,
+		CompressionNone, 0x04, 2, 0x10, 0x11,
+	}
+	m, err := DecodeMultipleString(b)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(m) != 2 || m[0] != (LanguageString{"eng", "Café!☺"}) || m[1] != (LanguageString{"spa", "АБ"}) {
+		t.Errorf("Unexpected strings %+v", m)
+	}
+	if m.String() != "Café!☺" {
+		t.Errorf("Unexpected string %q", m.String())
+	}
+	if text, ok := m.Text("spa"); !ok || text != "АБ" {
+		t.Errorf("Unexpected text %q", text)
+	}
+	if _, ok := m.Text("fra"); ok {
+		t.Error("Expected no text for fra")
+	}
+
+	huffman := []byte{1, 'e', 'n', 'g', 1, CompressionHuffmanC4, 0x00, 1, 0x00}
+	if _, err := DecodeMultipleString(huffman); err != gots.ErrUnsupportedStringCompression {
+		t.Errorf("Expected ErrUnsupportedStringCompression, got %v", err)
+	}
+	if _, err := DecodeMultipleString(b[:10]); err != gots.ErrShortPayload {
+		t.Errorf("Expected ErrShortPayload, got %v", err)
+	}
+}
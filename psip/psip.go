@@ -0,0 +1,126 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psip
+
+import (
+	"time"
+	"unicode/utf16"
+
+	"github.com/Comcast/gots/v3"
+	"github.com/Comcast/gots/v3/psi"
+)
+
+// BasePid is the PID carrying the MGT, VCT and STT.
+const BasePid = 0x1FFB
+
+// PSIP table ids
+const (
+	MgtTableID  = 0xC7
+	TvctTableID = 0xC8
+	CvctTableID = 0xC9
+	EitTableID  = 0xCB
+	EttTableID  = 0xCC
+	SttTableID  = 0xCD
+)
+
+func init() {
+	psi.RegisterTableDecoder(MgtTableID, MgtTableID, func(b []byte, options ...func(*psi.ParseOptions)) (interface{}, error) {
+		return NewMGT(b, options...)
+	})
+	psi.RegisterTableDecoder(TvctTableID, CvctTableID, func(b []byte, options ...func(*psi.ParseOptions)) (interface{}, error) {
+		return NewVCT(b, options...)
+	})
+	psi.RegisterTableDecoder(EitTableID, EitTableID, func(b []byte, options ...func(*psi.ParseOptions)) (interface{}, error) {
+		return NewEIT(b, options...)
+	})
+	psi.RegisterTableDecoder(EttTableID, EttTableID, func(b []byte, options ...func(*psi.ParseOptions)) (interface{}, error) {
+		return NewETT(b, options...)
+	})
+	psi.RegisterTableDecoder(SttTableID, SttTableID, func(b []byte, options ...func(*psi.ParseOptions)) (interface{}, error) {
+		return NewSTT(b, options...)
+	})
+}
+
+// gpsEpoch is the start of GPS time.
+var gpsEpoch = time.Date(1980, time.January, 6, 0, 0, 0, 0, time.UTC)
+
+// GPSTime converts a count of GPS seconds to UTC, given the GPS_UTC_offset
+// of the current STT.
+func GPSTime(seconds uint32, gpsUTCOffset uint8) time.Time {
+	return gpsEpoch.Add(time.Duration(seconds)*time.Second - time.Duration(gpsUTCOffset)*time.Second)
+}
+
+// parseSections returns the sections in PSI bytes, starting with the pointer
+// field, with the given table_id. The CRC of each section is validated
+// unless psi.WithoutCRCCheck is given. Sections with a non-zero
+// protocol_version are ignored, as required for current decoders.
+// gots.ErrUnknownTableID is returned if no section matches.
+func parseSections(b []byte, tableID uint8, options ...func(*psi.ParseOptions)) ([]psi.Section, error) {
+	all, err := psi.ParseSections(b, func(id uint8) bool { return id == tableID }, options...)
+	if err != nil {
+		return nil, err
+	}
+	var sections []psi.Section
+	for _, section := range all {
+		// skip sections of an unknown protocol_version
+		if len(section.Data()) < 1 {
+			return nil, gots.ErrShortPayload
+		}
+		if section.Data()[0] != 0 {
+			continue
+		}
+		sections = append(sections, section)
+	}
+	if len(sections) == 0 {
+		return nil, gots.ErrUnknownTableID
+	}
+	return sections, nil
+}
+
+// parseDescriptors parses the descriptor loop following a length of the
+// given number of bits at the start of b, and returns the remainder of b.
+func parseDescriptors(b []byte, bits uint) ([]psi.PmtDescriptor, []byte, error) {
+	if len(b) < 2 {
+		return nil, nil, gots.ErrShortPayload
+	}
+	length := int(uint16(b[0])<<8|uint16(b[1])) & (1<<bits - 1)
+	if len(b) < 2+length {
+		return nil, nil, gots.ErrShortPayload
+	}
+	descriptors, err := psi.ParseDescriptors(b[2 : 2+length])
+	if err != nil {
+		return nil, nil, err
+	}
+	return descriptors, b[2+length:], nil
+}
+
+// decodeUTF16 decodes big endian UTF-16.
+func decodeUTF16(b []byte) string {
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		u[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+	}
+	return string(utf16.Decode(u))
+}
@@ -0,0 +1,99 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psip
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Comcast/gots/v3"
+	"github.com/Comcast/gots/v3/psi"
+)
+
+// makeTable returns PSI bytes holding a single PSIP section with the given
+// data following protocol_version.
+func makeTable(tableID uint8, ext uint16, version uint8, data []byte) []byte {
+	length := 5 + 1 + len(data) + 4
+	b := []byte{
+		0x00, tableID, 0xF0 | byte(length>>8), byte(length),
+		byte(ext >> 8), byte(ext), 0xC1 | version<<1, 0x00, 0x00, 0x00,
+	}
+	b = append(b, data...)
+	return append(b, gots.ComputeCRC(b[1:])...)
+}
+
+func TestNewSTT(t *testing.T) {
+	// 2024-01-01 00:00:00 UTC with 18 leap seconds
+	data := []byte{0x52, 0xBC, 0xC3, 0x12, 18, 0x81, 0x0A, 0xA0, 0x02, 0x00, 0x00}
+	stt, err := NewSTT(makeTable(SttTableID, 0, 0, data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if stt.SystemTime() != 0x52BCC312 || stt.GPSUTCOffset() != 18 || !stt.UTCTime().Equal(want) {
+		t.Errorf("Unexpected STT time %v, want %v", stt.UTCTime(), want)
+	}
+	if ds, day, hour := stt.DaylightSaving(); !ds || day != 1 || hour != 10 {
+		t.Errorf("Unexpected daylight saving %v %d %d", ds, day, hour)
+	}
+	if len(stt.Descriptors()) != 1 || stt.Descriptors()[0].Tag() != 0xA0 {
+		t.Errorf("Unexpected descriptors %v", stt.Descriptors())
+	}
+
+	b := makeTable(SttTableID, 0, 0, data)
+	b[len(b)-1] ^= 0xFF
+	if _, err := NewSTT(b); !errors.Is(err, gots.ErrCRCMismatch) {
+		t.Errorf("Expected ErrCRCMismatch, got %v", err)
+	}
+	if _, err := NewSTT(b, psi.WithoutCRCCheck); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := NewSTT(makeTable(MgtTableID, 0, 0, data)); err != gots.ErrUnknownTableID {
+		t.Errorf("Expected ErrUnknownTableID, got %v", err)
+	}
+	if _, err := NewSTT(makeTable(SttTableID, 0, 0, data[:6])); err != gots.ErrShortPayload {
+		t.Errorf("Expected ErrShortPayload, got %v", err)
+	}
+
+	// sections of a future protocol_version are ignored
+	b = makeTable(SttTableID, 0, 0, data)
+	b[9] = 1
+	b = append(b[:len(b)-4], gots.ComputeCRC(b[1:len(b)-4])...)
+	if _, err := NewSTT(b); err != gots.ErrUnknownTableID {
+		t.Errorf("Expected ErrUnknownTableID, got %v", err)
+	}
+}
+
+func TestDecodeTable(t *testing.T) {
+	data := []byte{0x52, 0xBC, 0xC3, 0x12, 18, 0x00, 0x00}
+	table, err := psi.DecodeTable(makeTable(SttTableID, 0, 0, data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := table.(STT); !ok {
+		t.Errorf("Expected an STT, got %T", table)
+	}
+}
@@ -0,0 +1,98 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psip
+
+import (
+	"time"
+
+	"github.com/Comcast/gots/v3"
+	"github.com/Comcast/gots/v3/psi"
+)
+
+// STT is a System Time Table, carrying the current GPS time.
+type STT interface {
+	// SystemTime is the number of GPS seconds since 1980-01-06 00:00:00 UTC.
+	SystemTime() uint32
+	// GPSUTCOffset is the number of leap seconds between GPS and UTC.
+	GPSUTCOffset() uint8
+	// UTCTime returns the system time converted to UTC.
+	UTCTime() time.Time
+	// DaylightSaving returns the DS_status, DS_day_of_month and DS_hour
+	// fields of the daylight_saving field.
+	DaylightSaving() (inDaylightSaving bool, dayOfMonth uint8, hour uint8)
+	Descriptors() []psi.PmtDescriptor
+}
+
+type stt struct {
+	systemTime     uint32
+	gpsUTCOffset   uint8
+	daylightSaving uint16
+	descriptors    []psi.PmtDescriptor
+}
+
+// NewSTT creates a new STT from the given bytes, which should be packet
+// payload contents starting with the pointer field. The CRC is validated
+// unless psi.WithoutCRCCheck is given.
+func NewSTT(sttBytes []byte, options ...func(*psi.ParseOptions)) (STT, error) {
+	sections, err := parseSections(sttBytes, SttTableID, options...)
+	if err != nil {
+		return nil, err
+	}
+	data := sections[0].Data()[1:]
+	if len(data) < 7 {
+		return nil, gots.ErrShortPayload
+	}
+	s := &stt{
+		systemTime:     uint32(data[0])<<24 | uint32(data[1])<<16 | uint32(data[2])<<8 | uint32(data[3]),
+		gpsUTCOffset:   data[4],
+		daylightSaving: uint16(data[5])<<8 | uint16(data[6]),
+	}
+	// the descriptors extend to the CRC without a length field
+	s.descriptors, err = psi.ParseDescriptors(data[7:])
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *stt) SystemTime() uint32 {
+	return s.systemTime
+}
+
+func (s *stt) GPSUTCOffset() uint8 {
+	return s.gpsUTCOffset
+}
+
+func (s *stt) UTCTime() time.Time {
+	return GPSTime(s.systemTime, s.gpsUTCOffset)
+}
+
+func (s *stt) DaylightSaving() (bool, uint8, uint8) {
+	return s.daylightSaving&0x8000 != 0, uint8(s.daylightSaving>>8) & 0x1F, uint8(s.daylightSaving)
+}
+
+func (s *stt) Descriptors() []psi.PmtDescriptor {
+	return s.descriptors
+}
@@ -0,0 +1,213 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psip
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Comcast/gots/v3"
+	"github.com/Comcast/gots/v3/psi"
+)
+
+// Service types of a virtual channel
+const (
+	ServiceTypeAnalogTelevision  = 0x01
+	ServiceTypeATSCDigitalTV     = 0x02
+	ServiceTypeATSCAudio         = 0x03
+	ServiceTypeATSCDataBroadcast = 0x04
+	ServiceTypeATSCSoftwareDL    = 0x05
+)
+
+// ETM locations
+const (
+	ETMNone        = 0x0
+	ETMInPTC       = 0x1
+	ETMInChannelTS = 0x2
+)
+
+const (
+	// shortNameLen is the length in bytes of the 7 UTF-16 code units of a
+	// virtual channel short_name.
+	shortNameLen = 14
+	// channelLen is the length of a channel entry up to its descriptors.
+	channelLen = 32
+)
+
+// VCT is a Terrestrial or Cable Virtual Channel Table, describing the
+// virtual channels of a transport stream.
+type VCT interface {
+	TableID() uint8
+	// IsCable returns true for a CVCT.
+	IsCable() bool
+	TransportStreamID() int
+	VersionNumber() uint8
+	CurrentNextIndicator() bool
+	Channels() []VirtualChannel
+	AdditionalDescriptors() []psi.PmtDescriptor
+}
+
+// VirtualChannel is a channel entry of a VCT.
+type VirtualChannel struct {
+	ShortName          string
+	MajorChannelNumber int
+	MinorChannelNumber int
+	ModulationMode     uint8
+	// CarrierFrequency is deprecated and should be zero.
+	CarrierFrequency uint32
+	ChannelTSID      int
+	ProgramNumber    int
+	ETMLocation      uint8
+	AccessControlled bool
+	Hidden           bool
+	// PathSelect and OutOfBand are only defined for a CVCT.
+	PathSelect  bool
+	OutOfBand   bool
+	HideGuide   bool
+	ServiceType uint8
+	SourceID    int
+	Descriptors []psi.PmtDescriptor
+}
+
+// String returns the channel number and short name.
+func (c VirtualChannel) String() string {
+	return fmt.Sprintf("%d.%d %s", c.MajorChannelNumber, c.MinorChannelNumber, c.ShortName)
+}
+
+type vct struct {
+	tableID               uint8
+	transportStreamID     int
+	versionNumber         uint8
+	currentNextIndicator  bool
+	channels              []VirtualChannel
+	additionalDescriptors []psi.PmtDescriptor
+}
+
+// NewVCT creates a new TVCT or CVCT from the given bytes, which should be
+// concatenated packet payload contents starting with the pointer field.
+// Channels of all sections in the bytes are combined. The CRC of each
+// section is validated unless psi.WithoutCRCCheck is given.
+func NewVCT(vctBytes []byte, options ...func(*psi.ParseOptions)) (VCT, error) {
+	if len(vctBytes) < 1 || len(vctBytes) <= 1+int(psi.PointerField(vctBytes)) {
+		return nil, gots.ErrShortPayload
+	}
+	tableID := uint8(TvctTableID)
+	if psi.TableID(vctBytes) == CvctTableID {
+		tableID = CvctTableID
+	}
+	sections, err := parseSections(vctBytes, tableID, options...)
+	if err != nil {
+		return nil, err
+	}
+	v := &vct{
+		tableID:              tableID,
+		transportStreamID:    int(sections[0].TableIDExtension()),
+		versionNumber:        sections[0].VersionNumber(),
+		currentNextIndicator: sections[0].CurrentNextIndicator(),
+	}
+	for _, s := range sections {
+		if err := v.parseSection(s.Data()[1:]); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+func (v *vct) parseSection(data []byte) error {
+	if len(data) < 1 {
+		return gots.ErrShortPayload
+	}
+	n := int(data[0])
+	data = data[1:]
+	for i := 0; i < n; i++ {
+		if len(data) < channelLen {
+			return gots.ErrShortPayload
+		}
+		c := VirtualChannel{
+			ShortName:          decodeShortName(data[0:shortNameLen]),
+			MajorChannelNumber: int(data[14]&0x0F)<<6 | int(data[15]>>2),
+			MinorChannelNumber: int(data[15]&0x03)<<8 | int(data[16]),
+			ModulationMode:     data[17],
+			CarrierFrequency:   uint32(data[18])<<24 | uint32(data[19])<<16 | uint32(data[20])<<8 | uint32(data[21]),
+			ChannelTSID:        int(data[22])<<8 | int(data[23]),
+			ProgramNumber:      int(data[24])<<8 | int(data[25]),
+			ETMLocation:        data[26] >> 6,
+			AccessControlled:   data[26]&0x20 != 0,
+			Hidden:             data[26]&0x10 != 0,
+			HideGuide:          data[26]&0x02 != 0,
+			ServiceType:        data[27] & 0x3F,
+			SourceID:           int(data[28])<<8 | int(data[29]),
+		}
+		if v.IsCable() {
+			c.PathSelect = data[26]&0x08 != 0
+			c.OutOfBand = data[26]&0x04 != 0
+		}
+		var err error
+		c.Descriptors, data, err = parseDescriptors(data[30:], 10)
+		if err != nil {
+			return err
+		}
+		v.channels = append(v.channels, c)
+	}
+	descriptors, _, err := parseDescriptors(data, 10)
+	if err != nil {
+		return err
+	}
+	v.additionalDescriptors = append(v.additionalDescriptors, descriptors...)
+	return nil
+}
+
+// decodeShortName decodes a UTF-16 short_name, which is padded with NUL.
+func decodeShortName(b []byte) string {
+	return strings.TrimRight(decodeUTF16(b), "\x00")
+}
+
+func (v *vct) TableID() uint8 {
+	return v.tableID
+}
+
+func (v *vct) IsCable() bool {
+	return v.tableID == CvctTableID
+}
+
+func (v *vct) TransportStreamID() int {
+	return v.transportStreamID
+}
+
+func (v *vct) VersionNumber() uint8 {
+	return v.versionNumber
+}
+
+func (v *vct) CurrentNextIndicator() bool {
+	return v.currentNextIndicator
+}
+
+func (v *vct) Channels() []VirtualChannel {
+	return v.channels
+}
+
+func (v *vct) AdditionalDescriptors() []psi.PmtDescriptor {
+	return v.additionalDescriptors
+}
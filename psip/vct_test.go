@@ -0,0 +1,97 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package psip
+
+import (
+	"testing"
+
+	"github.com/Comcast/gots/v3"
+)
+
+func channel(name string, major, minor int, flags byte, serviceType byte, sourceID uint16, descriptors []byte) []byte {
+	b := make([]byte, shortNameLen)
+	for i, r := range name {
+		b[2*i+1] = byte(r)
+	}
+	b = append(b,
+		0xF0|byte(major>>6), byte(major<<2)|byte(minor>>8), byte(minor),
+		0x04,                   // 8-VSB
+		0x00, 0x00, 0x00, 0x00, // carrier_frequency
+		0x0C, 0x35, // channel_TSID
+		0x00, 0x03, // program_number
+		flags, 0xC0|serviceType,
+		byte(sourceID>>8), byte(sourceID),
+		0xFC|byte(len(descriptors)>>8), byte(len(descriptors)),
+	)
+	return append(b, descriptors...)
+}
+
+func TestNewVCT(t *testing.T) {
+	data := []byte{2}
+	data = append(data, channel("KQED-HD", 9, 1, 0x7D, ServiceTypeATSCDigitalTV, 1, []byte{0xA1, 0x01, 0xE0})...)
+	data = append(data, channel("KQED", 9, 1000, 0x9F, ServiceTypeATSCAudio, 2, nil)...)
+	data = append(data, 0xFC, 0x03, 0xA0, 0x01, 0x00)
+
+	vct, err := NewVCT(makeTable(TvctTableID, 0x0C35, 1, data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if vct.IsCable() || vct.TransportStreamID() != 0x0C35 || vct.VersionNumber() != 1 || !vct.CurrentNextIndicator() ||
+		len(vct.AdditionalDescriptors()) != 1 {
+		t.Errorf("Unexpected VCT %+v", vct)
+	}
+	channels := vct.Channels()
+	if len(channels) != 2 {
+		t.Fatalf("Expected 2 channels, got %d", len(channels))
+	}
+	c := channels[0]
+	if c.String() != "9.1 KQED-HD" || c.ModulationMode != 4 || c.ChannelTSID != 0x0C35 || c.ProgramNumber != 3 ||
+		c.ETMLocation != ETMInPTC || !c.AccessControlled || !c.Hidden || c.PathSelect || c.OutOfBand || c.HideGuide ||
+		c.ServiceType != ServiceTypeATSCDigitalTV || c.SourceID != 1 || len(c.Descriptors) != 1 {
+		t.Errorf("Unexpected channel %+v", c)
+	}
+	c = channels[1]
+	if c.MajorChannelNumber != 9 || c.MinorChannelNumber != 1000 || c.ETMLocation != ETMInChannelTS ||
+		c.AccessControlled || !c.Hidden || !c.HideGuide || c.ServiceType != ServiceTypeATSCAudio || len(c.Descriptors) != 0 {
+		t.Errorf("Unexpected channel %+v", c)
+	}
+
+	cvct, err := NewVCT(makeTable(CvctTableID, 0x0C35, 1, data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if c := cvct.Channels()[0]; !cvct.IsCable() || !c.PathSelect || !c.OutOfBand {
+		t.Errorf("Unexpected CVCT channel %+v", c)
+	}
+
+	if _, err := NewVCT(makeTable(TvctTableID, 0x0C35, 1, data[:40])); err != gots.ErrShortPayload {
+		t.Errorf("Expected ErrShortPayload, got %v", err)
+	}
+	for _, b := range [][]byte{nil, {0x00}, {0x02, 0xFF, 0xFF}} {
+		if _, err := NewVCT(b); err != gots.ErrShortPayload {
+			t.Errorf("NewVCT(%X): expected ErrShortPayload, got %v", b, err)
+		}
+	}
+}
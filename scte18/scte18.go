@@ -0,0 +1,264 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package scte18
+
+import (
+	"time"
+
+	"github.com/Comcast/gots/v3"
+	"github.com/Comcast/gots/v3/internal/fields"
+	"github.com/Comcast/gots/v3/psi"
+	"github.com/Comcast/gots/v3/psip"
+	"github.com/Comcast/gots/v3/scte35"
+)
+
+func init() {
+	psi.RegisterTableDecoder(TableID, TableID, func(b []byte, options ...func(*psi.ParseOptions)) (interface{}, error) {
+		return NewEmergencyAlert(b, options...)
+	})
+}
+
+// SCTE18AccumulatorDoneFunc is the same as for SCTE-35 since both are
+// carried in PSI sections.
+func SCTE18AccumulatorDoneFunc(b []byte) (bool, error) {
+	return scte35.SCTE35AccumulatorDoneFunc(b)
+}
+
+type emergencyAlert struct {
+	sequenceNumber     uint8
+	eventID            int
+	originatorCode     string
+	eventCode          string
+	natureOfActivation psip.MultipleString
+	timeRemaining      time.Duration
+	eventStartTime     uint32
+	eventDuration      time.Duration
+	alertPriority      uint8
+	detailsOOBSourceID int
+	detailsMajor       int
+	detailsMinor       int
+	audioOOBSourceID   int
+	alertText          psip.MultipleString
+	locations          []Location
+	exceptions         []Exception
+	descriptors        []psi.PmtDescriptor
+	data               []byte
+}
+
+// NewEmergencyAlert creates a new EmergencyAlert from the given bytes, which
+// should be concatenated packet payload contents starting with the pointer
+// field, as accumulated with SCTE18AccumulatorDoneFunc. The CRC is
+// validated unless psi.WithoutCRCCheck is given. Multiple string structures
+// using an unsupported compression are left empty.
+func NewEmergencyAlert(data []byte, options ...func(*psi.ParseOptions)) (EmergencyAlert, error) {
+	for _, b := range psi.SplitSections(data) {
+		if b[0] != TableID {
+			continue
+		}
+		s, err := psi.NewSection(b, options...)
+		if err != nil {
+			return nil, err
+		}
+		e := &emergencyAlert{
+			sequenceNumber: s.VersionNumber(),
+			data:           s,
+		}
+		if err := e.parse(s.Data()); err != nil {
+			return nil, err
+		}
+		return e, nil
+	}
+	return nil, gots.ErrUnknownTableID
+}
+
+// decodeText decodes a multiple_string_structure, ignoring unsupported
+// compression.
+func decodeText(b []byte) (psip.MultipleString, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	m, err := psip.DecodeMultipleString(b)
+	if err == gots.ErrUnsupportedStringCompression {
+		return nil, nil
+	}
+	return m, err
+}
+
+func (e *emergencyAlert) parse(b []byte) error {
+	// protocol_version, EAS_event_ID and EAS_originator_code
+	if len(b) < 6 {
+		return gots.ErrShortPayload
+	}
+	e.eventID = int(b[1])<<8 | int(b[2])
+	e.originatorCode = string(b[3:6])
+	eventCode, b, err := fields.LengthPrefixed(b[6:])
+	if err != nil {
+		return err
+	}
+	e.eventCode = string(eventCode)
+	text, b, err := fields.LengthPrefixed(b)
+	if err != nil {
+		return err
+	}
+	if e.natureOfActivation, err = decodeText(text); err != nil {
+		return err
+	}
+
+	if len(b) < 17 {
+		return gots.ErrShortPayload
+	}
+	e.timeRemaining = time.Duration(b[0]) * time.Second
+	e.eventStartTime = uint32(b[1])<<24 | uint32(b[2])<<16 | uint32(b[3])<<8 | uint32(b[4])
+	e.eventDuration = time.Duration(int(b[5])<<8|int(b[6])) * time.Minute
+	e.alertPriority = b[8] & 0x0F
+	e.detailsOOBSourceID = int(b[9])<<8 | int(b[10])
+	e.detailsMajor = int(b[11]&0x03)<<8 | int(b[12])
+	e.detailsMinor = int(b[13]&0x03)<<8 | int(b[14])
+	e.audioOOBSourceID = int(b[15])<<8 | int(b[16])
+	b = b[17:]
+
+	if len(b) < 2 || len(b) < 2+(int(b[0])<<8|int(b[1])) {
+		return gots.ErrShortPayload
+	}
+	length := int(b[0])<<8 | int(b[1])
+	if e.alertText, err = decodeText(b[2 : 2+length]); err != nil {
+		return err
+	}
+	b = b[2+length:]
+
+	if len(b) < 1 || len(b) < 1+3*int(b[0]) {
+		return gots.ErrShortPayload
+	}
+	for i := 0; i < int(b[0]); i++ {
+		l := b[1+3*i:]
+		e.locations = append(e.locations, Location{
+			StateCode:         l[0],
+			CountySubdivision: l[1] >> 4,
+			CountyCode:        uint16(l[1]&0x03)<<8 | uint16(l[2]),
+		})
+	}
+	b = b[1+3*int(b[0]):]
+
+	if len(b) < 1 || len(b) < 1+5*int(b[0]) {
+		return gots.ErrShortPayload
+	}
+	for i := 0; i < int(b[0]); i++ {
+		x := b[1+5*i:]
+		if x[0]&0x80 != 0 {
+			e.exceptions = append(e.exceptions, Exception{
+				InBand:             true,
+				MajorChannelNumber: int(x[1]&0x03)<<8 | int(x[2]),
+				MinorChannelNumber: int(x[3]&0x03)<<8 | int(x[4]),
+			})
+		} else {
+			e.exceptions = append(e.exceptions, Exception{
+				OOBSourceID: int(x[3])<<8 | int(x[4]),
+			})
+		}
+	}
+	b = b[1+5*int(b[0]):]
+
+	if len(b) < 2 || len(b) < 2+(int(b[0]&0x03)<<8|int(b[1])) {
+		return gots.ErrShortPayload
+	}
+	descriptors, err := psi.ParseDescriptors(b[2 : 2+(int(b[0]&0x03)<<8|int(b[1]))])
+	if err != nil {
+		return gots.ErrInvalidSCTE18Descriptor
+	}
+	e.descriptors = descriptors
+	return nil
+}
+
+func (e *emergencyAlert) SequenceNumber() uint8 {
+	return e.sequenceNumber
+}
+
+func (e *emergencyAlert) EventID() int {
+	return e.eventID
+}
+
+func (e *emergencyAlert) OriginatorCode() string {
+	return e.originatorCode
+}
+
+func (e *emergencyAlert) EventCode() string {
+	return e.eventCode
+}
+
+func (e *emergencyAlert) NatureOfActivation() psip.MultipleString {
+	return e.natureOfActivation
+}
+
+func (e *emergencyAlert) TimeRemaining() time.Duration {
+	return e.timeRemaining
+}
+
+func (e *emergencyAlert) EventStartTime() uint32 {
+	return e.eventStartTime
+}
+
+func (e *emergencyAlert) EventDuration() time.Duration {
+	return e.eventDuration
+}
+
+func (e *emergencyAlert) AlertPriority() uint8 {
+	return e.alertPriority
+}
+
+func (e *emergencyAlert) DetailsOOBSourceID() int {
+	return e.detailsOOBSourceID
+}
+
+func (e *emergencyAlert) DetailsMajorChannelNumber() int {
+	return e.detailsMajor
+}
+
+func (e *emergencyAlert) DetailsMinorChannelNumber() int {
+	return e.detailsMinor
+}
+
+func (e *emergencyAlert) AudioOOBSourceID() int {
+	return e.audioOOBSourceID
+}
+
+func (e *emergencyAlert) AlertText() psip.MultipleString {
+	return e.alertText
+}
+
+func (e *emergencyAlert) Locations() []Location {
+	return e.locations
+}
+
+func (e *emergencyAlert) Exceptions() []Exception {
+	return e.exceptions
+}
+
+func (e *emergencyAlert) Descriptors() []psi.PmtDescriptor {
+	return e.descriptors
+}
+
+func (e *emergencyAlert) Data() []byte {
+	return e.data
+}
@@ -0,0 +1,162 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package scte18
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Comcast/gots/v3"
+	"github.com/Comcast/gots/v3/packet"
+	"github.com/Comcast/gots/v3/psi"
+	"github.com/Comcast/gots/v3/psip"
+)
+
+func multipleString(text string) []byte {
+	b := []byte{1, 'e', 'n', 'g', 1, psip.CompressionNone, 0x00, byte(len(text))}
+	return append(b, text...)
+}
+
+// testAlert returns a cable_emergency_alert section starting with the
+// pointer field.
+func testAlert(alertText string) []byte {
+	nature := multipleString("Tornado Warning")
+	text := multipleString(alertText)
+	data := []byte{0x00, 0x12, 0x34, 'W', 'X', 'R', 3, 'T', 'O', 'R', byte(len(nature))}
+	data = append(data, nature...)
+	data = append(data,
+		120,                    // alert_message_time_remaining
+		0x52, 0xBC, 0xC3, 0x12, // event_start_time
+		0x00, 0x3C, // event_duration
+		0xFF, 0xF0|PriorityHigh,
+		0x00, 0x07, // details_OOB_source_ID
+		0xFC, 0x09, 0xFC, 0x01, // details channel 9.1
+		0x00, 0x08, // audio_OOB_source_ID
+		byte(len(text)>>8), byte(len(text)),
+	)
+	data = append(data, text...)
+	data = append(data,
+		2, 29, 0x0C, 0x4D, 6, 0x00, 0x00, // locations
+		2, 0xFF, 0xFC, 0x0C, 0xFC, 0x02, 0x7F, 0xFF, 0xFF, 0x00, 0x09, // exceptions
+		0xFC, 0x02, 0x80, 0x00, // descriptors
+	)
+	length := 5 + len(data) + 4
+	b := []byte{TableID, 0xB0 | byte(length>>8), byte(length), 0x00, 0x00, 0xC1 | 5<<1, 0x00, 0x00}
+	b = append(b, data...)
+	b = append(b, gots.ComputeCRC(b)...)
+	return append([]byte{0x00}, b...)
+}
+
+func TestNewEmergencyAlert(t *testing.T) {
+	e, err := NewEmergencyAlert(testAlert("A tornado warning is in effect"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if e.SequenceNumber() != 5 || e.EventID() != 0x1234 || e.OriginatorCode() != "WXR" || e.EventCode() != "TOR" {
+		t.Errorf("Unexpected event %d %d %s %s", e.SequenceNumber(), e.EventID(), e.OriginatorCode(), e.EventCode())
+	}
+	if e.NatureOfActivation().String() != "Tornado Warning" || e.AlertText().String() != "A tornado warning is in effect" {
+		t.Errorf("Unexpected text %q %q", e.NatureOfActivation(), e.AlertText())
+	}
+	if e.TimeRemaining() != 2*time.Minute || e.EventDuration() != time.Hour || e.AlertPriority() != PriorityHigh ||
+		!psip.GPSTime(e.EventStartTime(), 18).Equal(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected timing %v %v %d", e.TimeRemaining(), e.EventDuration(), e.AlertPriority())
+	}
+	if e.DetailsOOBSourceID() != 7 || e.DetailsMajorChannelNumber() != 9 || e.DetailsMinorChannelNumber() != 1 ||
+		e.AudioOOBSourceID() != 8 {
+		t.Errorf("Unexpected details %d %d.%d %d", e.DetailsOOBSourceID(), e.DetailsMajorChannelNumber(),
+			e.DetailsMinorChannelNumber(), e.AudioOOBSourceID())
+	}
+	locations := e.Locations()
+	if len(locations) != 2 || locations[0].String() != "029077" || locations[1] != (Location{StateCode: 6}) {
+		t.Errorf("Unexpected locations %v", locations)
+	}
+	exceptions := e.Exceptions()
+	if len(exceptions) != 2 || exceptions[0] != (Exception{InBand: true, MajorChannelNumber: 12, MinorChannelNumber: 2}) ||
+		exceptions[1] != (Exception{OOBSourceID: 9}) {
+		t.Errorf("Unexpected exceptions %+v", exceptions)
+	}
+	if len(e.Descriptors()) != 1 || e.Descriptors()[0].Tag() != 0x80 {
+		t.Errorf("Unexpected descriptors %v", e.Descriptors())
+	}
+}
+
+func TestNewEmergencyAlertErrors(t *testing.T) {
+	b := testAlert("")
+	b[len(b)-1] ^= 0xFF
+	if _, err := NewEmergencyAlert(b); !errors.Is(err, gots.ErrCRCMismatch) {
+		t.Errorf("Expected ErrCRCMismatch, got %v", err)
+	}
+	e, err := NewEmergencyAlert(b, psi.WithoutCRCCheck)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if e.AlertText().String() != "" {
+		t.Errorf("Unexpected alert text %q", e.AlertText())
+	}
+	// The descriptor overruns the descriptor loop
+	b = testAlert("")
+	b[len(b)-5] = 0x01
+	if _, err := NewEmergencyAlert(b, psi.WithoutCRCCheck); err != gots.ErrInvalidSCTE18Descriptor {
+		t.Errorf("Expected ErrInvalidSCTE18Descriptor, got %v", err)
+	}
+	if _, err := NewEmergencyAlert([]byte{0x00, 0xFC, 0x30, 0x00}); err != gots.ErrUnknownTableID {
+		t.Errorf("Expected ErrUnknownTableID, got %v", err)
+	}
+}
+
+func TestEmergencyAlertAccumulator(t *testing.T) {
+	text := strings.Repeat("Take shelter now. ", 12)
+	b := testAlert(text)
+	acc := packet.NewAccumulator(SCTE18AccumulatorDoneFunc)
+	for i := 0; len(b) > 0; i++ {
+		options := []func(*packet.Packet){packet.WithHasPayloadFlag}
+		if i == 0 {
+			options = append(options, packet.WithPUSI)
+		}
+		pkt := packet.Create(0x1FFB, options...)
+		pkt.SetContinuityCounter(i)
+		for j := 4; j < packet.PacketSize; j++ {
+			pkt[j] = 0xFF
+		}
+		b = b[packet.SetPayload(pkt, b):]
+		_, err := acc.WritePacket(pkt)
+		if len(b) > 0 && err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(b) == 0 && err != gots.ErrAccumulatorDone {
+			t.Fatalf("Expected ErrAccumulatorDone, got %v", err)
+		}
+	}
+	e, err := NewEmergencyAlert(acc.Bytes())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if e.AlertText().String() != text {
+		t.Errorf("Unexpected alert text %q", e.AlertText())
+	}
+}
@@ -0,0 +1,123 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package scte18
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Comcast/gots/v3/psi"
+	"github.com/Comcast/gots/v3/psip"
+)
+
+// TableID is the table_id of a cable_emergency_alert section.
+const TableID = 0xD8
+
+// Alert priorities
+const (
+	PriorityTest    = 0
+	PriorityLow     = 3
+	PriorityMedium  = 7
+	PriorityHigh    = 11
+	PriorityMaximum = 15
+)
+
+// OriginatorNames maps EAS originator codes to their names.
+var OriginatorNames = map[string]string{
+	"EAN": "Emergency Action Notification Network",
+	"PEP": "Primary Entry Point System",
+	"CIV": "Civil authorities",
+	"WXR": "National Weather Service",
+	"EAS": "EAS Participant",
+}
+
+// EmergencyAlert is a cable_emergency_alert message.
+type EmergencyAlert interface {
+	// SequenceNumber changes whenever the content of the alert changes.
+	SequenceNumber() uint8
+	// EventID identifies the EAS event.
+	EventID() int
+	// OriginatorCode is the three character EAS originator code.
+	OriginatorCode() string
+	// EventCode is the EAS event code, such as "TOR" or "RWT".
+	EventCode() string
+	// NatureOfActivation is a short description of the alert, or nil if
+	// absent or not decodable.
+	NatureOfActivation() psip.MultipleString
+	// TimeRemaining is the time remaining in the alert message.
+	TimeRemaining() time.Duration
+	// EventStartTime is the start of the event in GPS seconds since
+	// 1980-01-06 00:00:00 UTC, or zero if it starts immediately.
+	EventStartTime() uint32
+	// EventDuration is the duration of the event, or zero if indefinite.
+	EventDuration() time.Duration
+	// AlertPriority is the priority of the alert from PriorityTest to
+	// PriorityMaximum.
+	AlertPriority() uint8
+	// DetailsOOBSourceID is the source_id of the channel carrying the
+	// details of the alert out-of-band, or zero.
+	DetailsOOBSourceID() int
+	// DetailsMajorChannelNumber and DetailsMinorChannelNumber identify the
+	// virtual channel carrying the details of the alert.
+	DetailsMajorChannelNumber() int
+	DetailsMinorChannelNumber() int
+	// AudioOOBSourceID is the source_id of the alert audio, or zero.
+	AudioOOBSourceID() int
+	// AlertText is the text of the alert, or nil if absent or not
+	// decodable.
+	AlertText() psip.MultipleString
+	// Locations are the affected locations.
+	Locations() []Location
+	// Exceptions are the channels that are not to be interrupted.
+	Exceptions() []Exception
+	Descriptors() []psi.PmtDescriptor
+	// Data returns the bytes of the section.
+	Data() []byte
+}
+
+// Location is an affected location of an alert.
+type Location struct {
+	// StateCode is the FIPS state code, or zero for all states.
+	StateCode uint8
+	// CountySubdivision is the part of the county, or zero for all.
+	CountySubdivision uint8
+	// CountyCode is the FIPS county code, or zero for the whole state.
+	CountyCode uint16
+}
+
+// String returns the location in the PSSCCC form used by EAS.
+func (l Location) String() string {
+	return fmt.Sprintf("%d%02d%03d", l.CountySubdivision, l.StateCode, l.CountyCode)
+}
+
+// Exception is a channel that is not to be interrupted by an alert.
+type Exception struct {
+	// InBand is true if the channel is identified by its channel numbers,
+	// and false if it is identified by OOBSourceID.
+	InBand             bool
+	MajorChannelNumber int
+	MinorChannelNumber int
+	OOBSourceID        int
+}
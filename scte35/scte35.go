@@ -63,9 +63,9 @@ type scte35 struct {
 }
 
 // NewSCTE35 creates a new SCTE35 signal from the provided byte slice. The byte slice is parsed and relevant info is made available fir the SCTE35 interface. If the message cannot me parsed, an error is returned.
-func NewSCTE35(data []byte) (SCTE35, error) {
+func NewSCTE35(data []byte, options ...func(*psi.ParseOptions)) (SCTE35, error) {
 	s := &scte35{}
-	err := s.parseTable(data)
+	err := s.parseTable(data, psi.NewParseOptions(options...))
 	if err != nil {
 		return nil, err
 	}
@@ -73,7 +73,7 @@ func NewSCTE35(data []byte) (SCTE35, error) {
 }
 
 // parseTable will parse bytes into a scte35 message struct
-func (s *scte35) parseTable(data []byte) error {
+func (s *scte35) parseTable(data []byte, options psi.ParseOptions) error {
 	buf := bytes.NewBuffer(data)
 	// closure to ignore EOF error from buf.ReadByte().  We've already checked
 	// the length, we don't need to continually check it after every ReadByte
@@ -93,7 +93,13 @@ func (s *scte35) parseTable(data []byte) error {
 	if err != nil {
 		return err
 	}
-	if s.tableHeader.TableID == 0xfc {
+	if s.tableHeader.TableID == TableID {
+		if err := options.CheckCRC(data[psi.PointerField(data)+1:]); err != nil {
+			if err == gots.ErrShortPayload {
+				return gots.ErrInvalidSCTE35Length
+			}
+			return err
+		}
 		s.protocolVersion = readByte()
 		if readByte()&0x80 != 0 {
 			return gots.ErrSCTE35EncryptionUnsupported
@@ -172,7 +178,6 @@ func (s *scte35) parseTable(data []byte) error {
 	} else {
 		return gots.ErrUnknownTableID
 	}
-	// Check CRC?
 	// remove the pointer field and associated data off the top so we only get the
 	// table data
 	s.data = data[psi.PointerField(data)+1:]
@@ -26,11 +26,13 @@ package scte35
 import (
 	"bytes"
 	"encoding/base64"
+	"errors"
 	"io"
 	"strings"
 	"testing"
 
 	"github.com/Comcast/gots/v3"
+	"github.com/Comcast/gots/v3/psi"
 )
 
 var testScte = []byte{
@@ -121,7 +123,7 @@ func TestSpliceInsertSignal(t *testing.T) {
 }
 
 func TestBasicSignal(t *testing.T) {
-	s, err := NewSCTE35(testScte)
+	s, err := NewSCTE35(testScte, psi.WithoutCRCCheck)
 	if err != nil {
 		t.Error(err)
 		t.FailNow()
@@ -203,12 +205,12 @@ func TestParseSpliceNull(t *testing.T) {
 }
 
 func TestSCTEExpanded(t *testing.T) {
-	s1, err1 := NewSCTE35(testScte)
+	s1, err1 := NewSCTE35(testScte, psi.WithoutCRCCheck)
 	if err1 != nil {
 		t.Error(err1)
 		t.FailNow()
 	}
-	s2, err2 := NewSCTE35(testScte2)
+	s2, err2 := NewSCTE35(testScte2, psi.WithoutCRCCheck)
 	if err2 != nil {
 		t.Error(err2)
 		t.FailNow()
@@ -271,7 +273,7 @@ func TestSCTEExpanded(t *testing.T) {
 }
 
 func TestSCTEMultipleDescriptors(t *testing.T) {
-	s, err := NewSCTE35(testScte3)
+	s, err := NewSCTE35(testScte3, psi.WithoutCRCCheck)
 	if err != nil {
 		t.Error(err)
 		t.FailNow()
@@ -408,3 +410,18 @@ func TestParseSegmentationDescriptor_Segments(t *testing.T) {
 		t.Errorf("want segment_num %d, got %d", want, got)
 	}
 }
+
+func TestCRCValidation(t *testing.T) {
+	data := append(psi.NewPointerField(0), CreateSCTE35().UpdateData()...)
+	if _, err := NewSCTE35(data); err != nil {
+		t.Fatalf("Unexpected error parsing created SCTE35: %v", err)
+	}
+
+	data[len(data)-1] ^= 0xff
+	if _, err := NewSCTE35(data); !errors.Is(err, gots.ErrCRCMismatch) {
+		t.Errorf("Expected ErrCRCMismatch, got %v", err)
+	}
+	if _, err := NewSCTE35(data, psi.WithoutCRCCheck); err != nil {
+		t.Errorf("Unexpected error parsing SCTE35 leniently: %v", err)
+	}
+}
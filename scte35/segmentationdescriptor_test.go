@@ -26,6 +26,8 @@ package scte35
 import (
 	"strings"
 	"testing"
+
+	"github.com/Comcast/gots/v3/psi"
 )
 
 var csp = []byte{
@@ -108,9 +110,9 @@ var vssSingleUPID = []byte{
 }
 
 func TestNoInRules(t *testing.T) {
-	out, err := NewSCTE35(poOpen1)
+	out, err := NewSCTE35(poOpen1, psi.WithoutCRCCheck)
 	if err != nil {
-		t.Error("NewSCTE35(poOpen1) returned err:", err.Error())
+		t.Error("NewSCTE35(poOpen1, psi.WithoutCRCCheck) returned err:", err.Error())
 		t.FailNow()
 	}
 
@@ -127,9 +129,9 @@ func TestNoInRules(t *testing.T) {
 }
 
 func TestOutNoRules(t *testing.T) {
-	out, err := NewSCTE35(poOpen1)
+	out, err := NewSCTE35(poOpen1, psi.WithoutCRCCheck)
 	if err != nil {
-		t.Error("NewSCTE35(poOpen1) returned err:", err.Error())
+		t.Error("NewSCTE35(poOpen1, psi.WithoutCRCCheck) returned err:", err.Error())
 		t.FailNow()
 	}
 
@@ -147,16 +149,16 @@ func TestOutNoRules(t *testing.T) {
 
 func TestCloseUnconditional(t *testing.T) {
 	// Create a 0x40 (unscheduled event start)
-	unschedEvent, err := NewSCTE35(unscheduled_event_start)
+	unschedEvent, err := NewSCTE35(unscheduled_event_start, psi.WithoutCRCCheck)
 	if err != nil {
-		t.Error("NewSCTE35(unscheduled_event_start) returned err:", err.Error())
+		t.Error("NewSCTE35(unscheduled_event_start, psi.WithoutCRCCheck) returned err:", err.Error())
 		t.FailNow()
 	}
 
 	// Create a 0x51 (network end)
-	networkEnd, err := NewSCTE35(network_end)
+	networkEnd, err := NewSCTE35(network_end, psi.WithoutCRCCheck)
 	if err != nil {
-		t.Error("NewSCTE35(network_end) returned err:", err.Error())
+		t.Error("NewSCTE35(network_end, psi.WithoutCRCCheck) returned err:", err.Error())
 		t.FailNow()
 	}
 
@@ -168,16 +170,16 @@ func TestCloseUnconditional(t *testing.T) {
 
 func TestCloseEventId(t *testing.T) {
 	// Create a 0x10 (program start)
-	programStart, err := NewSCTE35(program_start)
+	programStart, err := NewSCTE35(program_start, psi.WithoutCRCCheck)
 	if err != nil {
-		t.Error("NewSCTE35(program_start) returned err:", err.Error())
+		t.Error("NewSCTE35(program_start, psi.WithoutCRCCheck) returned err:", err.Error())
 		t.FailNow()
 	}
 
 	// Create a 0x11 (program end)
-	programEnd, err := NewSCTE35(program_end)
+	programEnd, err := NewSCTE35(program_end, psi.WithoutCRCCheck)
 	if err != nil {
-		t.Error("NewSCTE35(program_end) returned err:", err.Error())
+		t.Error("NewSCTE35(program_end, psi.WithoutCRCCheck) returned err:", err.Error())
 		t.FailNow()
 	}
 
@@ -197,16 +199,16 @@ func TestCloseEventId(t *testing.T) {
 
 func TestCloseDifferentPTS(t *testing.T) {
 	// Create a 0x30 (provider ad start)
-	adStart, err := NewSCTE35(provider_ad_start)
+	adStart, err := NewSCTE35(provider_ad_start, psi.WithoutCRCCheck)
 	if err != nil {
-		t.Error("NewSCTE35(provider_ad_start) returned err:", err.Error())
+		t.Error("NewSCTE35(provider_ad_start, psi.WithoutCRCCheck) returned err:", err.Error())
 		t.FailNow()
 	}
 
 	// Create a 0x36 (distributor PO start)
-	poStart, err := NewSCTE35(distributor_po_start)
+	poStart, err := NewSCTE35(distributor_po_start, psi.WithoutCRCCheck)
 	if err != nil {
-		t.Error("NewSCTE35(distributor_po_start) returned err:", err.Error())
+		t.Error("NewSCTE35(distributor_po_start, psi.WithoutCRCCheck) returned err:", err.Error())
 		t.FailNow()
 	}
 
@@ -226,16 +228,16 @@ func TestCloseDifferentPTS(t *testing.T) {
 
 func TestCloseBreakaway(t *testing.T) {
 	// Create a 0x10 (program start)
-	programStart, err := NewSCTE35(program_start)
+	programStart, err := NewSCTE35(program_start, psi.WithoutCRCCheck)
 	if err != nil {
-		t.Error("NewSCTE35(program_start) returned err:", err.Error())
+		t.Error("NewSCTE35(program_start, psi.WithoutCRCCheck) returned err:", err.Error())
 		t.FailNow()
 	}
 
 	// Create a 0x14 (program resumption)
-	programResumption, err := NewSCTE35(program_resumption)
+	programResumption, err := NewSCTE35(program_resumption, psi.WithoutCRCCheck)
 	if err != nil {
-		t.Error("NewSCTE35(program_resumption) returned err:", err.Error())
+		t.Error("NewSCTE35(program_resumption, psi.WithoutCRCCheck) returned err:", err.Error())
 		t.FailNow()
 	}
 
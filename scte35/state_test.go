@@ -29,6 +29,7 @@ import (
 	"testing"
 
 	"github.com/Comcast/gots/v3"
+	"github.com/Comcast/gots/v3/psi"
 )
 
 // All signal data generated with scte_creator: https://github.comcast.com/mniebu200/scte_creator
@@ -158,9 +159,9 @@ var scteDesc51Signal = "DBQAAAAAAAAAABwBQb/LrIZ4QA6AhtDVUVJQAAAAH+fCgwUd4vl4/YAA
 
 func TestOutIn(t *testing.T) {
 	st := NewState()
-	open, e := NewSCTE35(poOpen1)
+	open, e := NewSCTE35(poOpen1, psi.WithoutCRCCheck)
 	if e != nil {
-		t.Fatal("NewSCTE35(poOpen1) returned err:", e)
+		t.Fatal("NewSCTE35(poOpen1, psi.WithoutCRCCheck) returned err:", e)
 	}
 	c, e := st.ProcessDescriptor(open.Descriptors()[0])
 	if e != nil {
@@ -174,9 +175,9 @@ func TestOutIn(t *testing.T) {
 	} else if st.Open()[0] != open.Descriptors()[0] {
 		t.Error("Open returned unexpected descriptor")
 	}
-	close, e := NewSCTE35(poClose1)
+	close, e := NewSCTE35(poClose1, psi.WithoutCRCCheck)
 	if e != nil {
-		t.Fatal("NewSCTE35(poClose1) returned err:", e)
+		t.Fatal("NewSCTE35(poClose1, psi.WithoutCRCCheck) returned err:", e)
 	}
 	c, e = st.ProcessDescriptor(close.Descriptors()[0])
 	if e != nil {
@@ -194,9 +195,9 @@ func TestOutIn(t *testing.T) {
 
 func TestOutInIn(t *testing.T) {
 	st := NewState()
-	open, e := NewSCTE35(poOpen1)
+	open, e := NewSCTE35(poOpen1, psi.WithoutCRCCheck)
 	if e != nil {
-		t.Fatal("NewSCTE35(poOpen1) returned err:", e)
+		t.Fatal("NewSCTE35(poOpen1, psi.WithoutCRCCheck) returned err:", e)
 	}
 	c, e := st.ProcessDescriptor(open.Descriptors()[0])
 	if e != nil {
@@ -210,9 +211,9 @@ func TestOutInIn(t *testing.T) {
 	} else if st.Open()[0] != open.Descriptors()[0] {
 		t.Error("Open returned unexpected descriptor")
 	}
-	close1, e := NewSCTE35(poClose12)
+	close1, e := NewSCTE35(poClose12, psi.WithoutCRCCheck)
 	if e != nil {
-		t.Fatal("NewSCTE35(poClose12) returned unexpected err:", e)
+		t.Fatal("NewSCTE35(poClose12, psi.WithoutCRCCheck) returned unexpected err:", e)
 	}
 	c, e = st.ProcessDescriptor(close1.Descriptors()[0])
 	if e != nil {
@@ -226,9 +227,9 @@ func TestOutInIn(t *testing.T) {
 	} else if st.Open()[0] != open.Descriptors()[0] {
 		t.Error("Open returned unexpected descriptor")
 	}
-	close2, e := NewSCTE35(poClose22)
+	close2, e := NewSCTE35(poClose22, psi.WithoutCRCCheck)
 	if e != nil {
-		t.Fatal("NewSCTE35(poClose22) returned unexpected err:", e)
+		t.Fatal("NewSCTE35(poClose22, psi.WithoutCRCCheck) returned unexpected err:", e)
 	}
 	c, e = st.ProcessDescriptor(close2.Descriptors()[0])
 	if e != nil {
@@ -246,9 +247,9 @@ func TestOutInIn(t *testing.T) {
 
 func TestDuplicateOut(t *testing.T) {
 	st := NewState()
-	open, e := NewSCTE35(poOpen1)
+	open, e := NewSCTE35(poOpen1, psi.WithoutCRCCheck)
 	if e != nil {
-		t.Fatal("NewSCTE35(poOpen1) returned err:", e)
+		t.Fatal("NewSCTE35(poOpen1, psi.WithoutCRCCheck) returned err:", e)
 	}
 	_, e = st.ProcessDescriptor(open.Descriptors()[0])
 	if e != nil {
@@ -361,9 +362,9 @@ func TestSubsegments(t *testing.T) {
 	state := NewState()
 
 	// 0x34
-	ppoStart, err := NewSCTE35(ppoStartSubsegments)
+	ppoStart, err := NewSCTE35(ppoStartSubsegments, psi.WithoutCRCCheck)
 	if err != nil {
-		t.Fatal("NewSCTE35(ppoStartSubsegments) return err:", err.Error())
+		t.Fatal("NewSCTE35(ppoStartSubsegments, psi.WithoutCRCCheck) return err:", err.Error())
 	}
 
 	closed, err := state.ProcessDescriptor(ppoStart.Descriptors()[0])
@@ -378,9 +379,9 @@ func TestSubsegments(t *testing.T) {
 	}
 
 	// 0x36
-	dpoStart, err := NewSCTE35(dpoStartSubsegments)
+	dpoStart, err := NewSCTE35(dpoStartSubsegments, psi.WithoutCRCCheck)
 	if err != nil {
-		t.Fatal("NewSCTE35(dpoStartSubsegments) return err:", err.Error())
+		t.Fatal("NewSCTE35(dpoStartSubsegments, psi.WithoutCRCCheck) return err:", err.Error())
 	}
 
 	closed, err = state.ProcessDescriptor(dpoStart.Descriptors()[0])
@@ -395,9 +396,9 @@ func TestSubsegments(t *testing.T) {
 	}
 
 	// 0x37
-	dpoFirstEnd, err := NewSCTE35(dpoFirstEndSubsegments)
+	dpoFirstEnd, err := NewSCTE35(dpoFirstEndSubsegments, psi.WithoutCRCCheck)
 	if err != nil {
-		t.Fatal("NewSCTE35(dpoFirstEndSubsegments) return err:", err.Error())
+		t.Fatal("NewSCTE35(dpoFirstEndSubsegments, psi.WithoutCRCCheck) return err:", err.Error())
 	}
 
 	closed, err = state.ProcessDescriptor(dpoFirstEnd.Descriptors()[0])
@@ -412,9 +413,9 @@ func TestSubsegments(t *testing.T) {
 	}
 
 	// 0x37
-	dpoSecondEnd, err := NewSCTE35(dpoSecondEndSubsegments)
+	dpoSecondEnd, err := NewSCTE35(dpoSecondEndSubsegments, psi.WithoutCRCCheck)
 	if err != nil {
-		t.Fatal("NewSCTE35(dpoSecondEndSubsegments) return err:", err.Error())
+		t.Fatal("NewSCTE35(dpoSecondEndSubsegments, psi.WithoutCRCCheck) return err:", err.Error())
 	}
 
 	closed, err = state.ProcessDescriptor(dpoSecondEnd.Descriptors()[0])
@@ -429,9 +430,9 @@ func TestSubsegments(t *testing.T) {
 	}
 
 	// 0x35
-	ppoEnd, err := NewSCTE35(ppoEndSubsegments)
+	ppoEnd, err := NewSCTE35(ppoEndSubsegments, psi.WithoutCRCCheck)
 	if err != nil {
-		t.Fatal("NewSCTE35(ppoEndSubsegments) return err:", err.Error())
+		t.Fatal("NewSCTE35(ppoEndSubsegments, psi.WithoutCRCCheck) return err:", err.Error())
 	}
 	closed, err = state.ProcessDescriptor(ppoEnd.Descriptors()[0])
 	if err != nil {
@@ -918,7 +919,7 @@ func Test11Closing34_36_40_44(t *testing.T) {
 
 	// 0x44
 	scteDesc44SignalBytes, _ := base64.StdEncoding.DecodeString(scteDesc44Signal)
-	inSignal, err = NewSCTE35(append([]byte{0x0}, scteDesc44SignalBytes...))
+	inSignal, err = NewSCTE35(append([]byte{0x0}, scteDesc44SignalBytes...), psi.WithoutCRCCheck)
 	if err != nil {
 		t.Fatal("Error creating SCTE-35 signal, return err:", err.Error())
 	}
@@ -959,7 +960,7 @@ func Test45ClosingLogic(t *testing.T) {
 
 	//0x44
 	scteDesc44SignalBytes , _ := base64.StdEncoding.DecodeString(scteDesc44Signal)
-	inSignal, err := NewSCTE35(append([]byte{0x0}, scteDesc44SignalBytes...))
+	inSignal, err := NewSCTE35(append([]byte{0x0}, scteDesc44SignalBytes...), psi.WithoutCRCCheck)
 	if err != nil {
 		t.Fatal("Error creating SCTE-35 signal, return err:", err.Error())
 	}
@@ -995,7 +996,7 @@ func Test45ClosingLogic(t *testing.T) {
 
 	// 0x45 - closes 0x44 and 0x30
 	scteDesc45SignalBytes, _ := base64.StdEncoding.DecodeString(scteDesc45Signal)
-	inSignal, err = NewSCTE35(append([]byte{0x0}, scteDesc45SignalBytes...))
+	inSignal, err = NewSCTE35(append([]byte{0x0}, scteDesc45SignalBytes...), psi.WithoutCRCCheck)
 	if err != nil {
 		t.Fatal("Error creating SCTE-35 signal, return err:", err.Error())
 	}
@@ -1017,7 +1018,7 @@ func Test44ClosingLogic(t *testing.T) {
 
 	// 0x44
 	scteDesc44SignalBytes , _ := base64.StdEncoding.DecodeString(scteDesc44Signal)
-	inSignal, err := NewSCTE35(append([]byte{0x0}, scteDesc44SignalBytes...))
+	inSignal, err := NewSCTE35(append([]byte{0x0}, scteDesc44SignalBytes...), psi.WithoutCRCCheck)
 	if err != nil {
 		t.Fatal("Error creating SCTE-35 signal, return err:", err.Error())
 	}
@@ -1053,7 +1054,7 @@ func Test44ClosingLogic(t *testing.T) {
 
 	// Different 0x44 - closes the earlier 0x44 and 0x30
 	scteDesc44SignalBytes1, _ := base64.StdEncoding.DecodeString(scteDesc44Signal1)
-	inSignal, err = NewSCTE35(append([]byte{0x0}, scteDesc44SignalBytes1...))
+	inSignal, err = NewSCTE35(append([]byte{0x0}, scteDesc44SignalBytes1...), psi.WithoutCRCCheck)
 	if err != nil {
 		t.Fatal("Error creating SCTE-35 signal, return err:", err.Error())
 	}
@@ -1112,7 +1113,7 @@ func Test23ClosingLogic_30_34_36_44(t *testing.T) {
 
 	// 0x44
 	scteDesc44SignalBytes , _ := base64.StdEncoding.DecodeString(scteDesc44Signal)
-	inSignal, err = NewSCTE35(append([]byte{0x0}, scteDesc44SignalBytes...))
+	inSignal, err = NewSCTE35(append([]byte{0x0}, scteDesc44SignalBytes...), psi.WithoutCRCCheck)
 	if err != nil {
 		t.Fatal("Error creating SCTE-35 signal, return err:", err.Error())
 	}
@@ -1246,7 +1247,7 @@ func Test22ClosingLogic_30_34_36_44_22(t *testing.T){
 
 	// 0x44
 	scteDesc44SignalBytes , _ := base64.StdEncoding.DecodeString(scteDesc44Signal)
-	inSignal, err = NewSCTE35(append([]byte{0x0}, scteDesc44SignalBytes...))
+	inSignal, err = NewSCTE35(append([]byte{0x0}, scteDesc44SignalBytes...), psi.WithoutCRCCheck)
 	if err != nil {
 		t.Fatal("Error creating SCTE-35 signal, return err:", err.Error())
 	}
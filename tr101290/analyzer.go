@@ -0,0 +1,672 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package tr101290
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Comcast/gots/v3"
+	"github.com/Comcast/gots/v3/packet"
+	"github.com/Comcast/gots/v3/pes"
+	"github.com/Comcast/gots/v3/psi"
+)
+
+// Timing constraints from TR 101 290
+const (
+	PATInterval         = 500 * time.Millisecond
+	PMTInterval         = 500 * time.Millisecond
+	DefaultPIDTimeout   = 5 * time.Second
+	PCRInterval         = 100 * time.Millisecond
+	PTSInterval         = 700 * time.Millisecond
+	NITInterval         = 10 * time.Second
+	SDTInterval         = 2 * time.Second
+	EITInterval         = 2 * time.Second
+	TDTInterval         = 30 * time.Second
+	SIMinInterval       = 25 * time.Millisecond
+	UnreferencedPIDTime = 500 * time.Millisecond
+	rstPid              = 0x13
+	pcrAccuracyTicks    = 13.5 // 500ns on the 27MHz clock
+	pcrModulus          = uint64(1<<33) * 300
+	syncLossThreshold   = 2
+	syncRegainThreshold = 5
+)
+
+// siTable describes the sections allowed on a DVB SI PID and the check
+// raised for other sections.
+type siTable struct {
+	check   Check
+	allowed func(tableID uint8) bool
+}
+
+// DVB SI PIDs whose sections are verified. The stuffing table 0x72 may
+// occur on all of them.
+var siPids = map[int]siTable{
+	psi.NitPid: {NITActualError, func(id uint8) bool { return id == 0x40 || id == 0x41 || id == 0x72 }},
+	psi.SdtPid: {SDTActualError, func(id uint8) bool { return id == 0x42 || id == 0x46 || id == 0x4A || id == 0x72 }},
+	psi.EitPid: {EITActualError, func(id uint8) bool { return id >= 0x4E && id <= 0x6F || id == 0x72 }},
+	rstPid:     {RSTError, func(id uint8) bool { return id == 0x71 || id == 0x72 }},
+	psi.TdtPid: {TDTError, func(id uint8) bool { return id == 0x70 || id == 0x72 || id == 0x73 }},
+}
+
+// siKey identifies a section of a DVB SI table. Short sections have no
+// extension and a section number of -1.
+type siKey struct {
+	pid       int
+	tableID   uint8
+	extension uint16
+	section   int
+}
+
+// siTimeout is the maximum interval of a DVB SI table section.
+type siTimeout struct {
+	check    Check
+	interval time.Duration
+}
+
+// siTimeouts are checked once the section has been seen, so that streams
+// without DVB SI do not raise errors.
+var siTimeouts = map[siKey]siTimeout{
+	{pid: psi.NitPid, tableID: 0x40, section: -1}: {NITActualError, NITInterval},
+	{pid: psi.SdtPid, tableID: 0x42, section: -1}: {SDTActualError, SDTInterval},
+	{pid: psi.EitPid, tableID: 0x4E, section: 0}:  {EITActualError, EITInterval},
+	{pid: psi.EitPid, tableID: 0x4E, section: 1}:  {EITActualError, EITInterval},
+	{pid: psi.TdtPid, tableID: 0x70, section: -1}: {TDTError, TDTInterval},
+}
+
+// Analyzer performs TR 101 290 checks on a transport stream.
+// Analyzer is not thread safe.
+type Analyzer interface {
+	// WritePacket analyzes the next packet in the stream, using the
+	// analyzer's clock as the packet's arrival time.
+	WritePacket(pkt *packet.Packet) (int, error)
+	// WritePacketAt analyzes the next packet in the stream that arrived at time t.
+	WritePacketAt(pkt *packet.Packet, t time.Time) (int, error)
+	// OnEvent registers a handler that is called for every failed check.
+	OnEvent(func(Event))
+	// Count returns the number of times a check has failed.
+	Count(c Check) uint64
+	// Counters returns the number of times each check has failed.
+	Counters() map[Check]uint64
+	// Packets returns the number of packets analyzed.
+	Packets() uint64
+}
+
+// Option configures an Analyzer.
+type Option func(*analyzer)
+
+// WithClock sets the clock used by WritePacket to timestamp packets.
+// The default clock is time.Now. Arrival times are used for the table and
+// PID timeouts, PCR and PTS intervals are measured on the PCR.
+func WithClock(clock func() time.Time) Option {
+	return func(a *analyzer) {
+		a.clock = clock
+	}
+}
+
+// WithPIDTimeout sets how long a PID referenced by a PMT may be absent
+// before a PID_error is raised.
+func WithPIDTimeout(d time.Duration) Option {
+	return func(a *analyzer) {
+		a.pidTimeout = d
+	}
+}
+
+type pcrState struct {
+	pcr    uint64
+	packet uint64
+	// ticksPerPacket is the PCR rate measured between the last two PCRs
+	ticksPerPacket float64
+}
+
+type analyzer struct {
+	clock      func() time.Time
+	pidTimeout time.Duration
+	handlers   []func(Event)
+	counts     map[Check]uint64
+
+	packets uint64
+	now     time.Time
+	start   time.Time
+
+	badSync  int
+	goodSync int
+	syncLost bool
+
+	cc       packet.ContinuityTracker
+	sections map[int]packet.Accumulator
+
+	lastPAT  time.Time
+	pmts     map[int]time.Time // PMT PID to last occurrence
+	esPids   map[int][]int     // PMT PID to elementary PIDs
+	lastSeen map[int]time.Time // referenced elementary PID to last occurrence
+	pcrPids  map[int]int       // elementary PID to the PCR PID of its program
+	lastPTS  map[int]uint64    // elementary PID to the PCR at the last PTS
+	pcrs     map[int]*pcrState
+	catSeen  bool
+	noCAT    map[int]bool // scrambled PIDs reported before a CAT was seen
+
+	pmtRefs      map[int][]int // PMT PID to referenced PCR and ECM PIDs
+	catRefs      map[int]bool  // EMM PIDs referenced by the CAT
+	unreferenced map[int]time.Time
+	reported     map[int]bool // unreferenced PIDs that have been reported
+
+	siSections map[siKey]time.Time // last occurrence of every SI section
+	siLast     map[siKey]time.Time // last occurrence of the SI sections with a timeout
+}
+
+// NewAnalyzer creates a new Analyzer.
+func NewAnalyzer(opts ...Option) Analyzer {
+	a := &analyzer{
+		clock:      time.Now,
+		pidTimeout: DefaultPIDTimeout,
+		counts:     make(map[Check]uint64),
+		cc:         packet.NewContinuityTracker(),
+		sections:   make(map[int]packet.Accumulator),
+		pmts:       make(map[int]time.Time),
+		esPids:     make(map[int][]int),
+		lastSeen:   make(map[int]time.Time),
+		pcrPids:    make(map[int]int),
+		lastPTS:    make(map[int]uint64),
+		pcrs:       make(map[int]*pcrState),
+		noCAT:      make(map[int]bool),
+
+		pmtRefs:      make(map[int][]int),
+		catRefs:      make(map[int]bool),
+		unreferenced: make(map[int]time.Time),
+		reported:     make(map[int]bool),
+		siSections:   make(map[siKey]time.Time),
+		siLast:       make(map[siKey]time.Time),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+func (a *analyzer) OnEvent(h func(Event)) {
+	a.handlers = append(a.handlers, h)
+}
+
+func (a *analyzer) Count(c Check) uint64 {
+	return a.counts[c]
+}
+
+func (a *analyzer) Counters() map[Check]uint64 {
+	counts := make(map[Check]uint64, len(Checks))
+	for _, c := range Checks {
+		counts[c] = a.counts[c]
+	}
+	return counts
+}
+
+func (a *analyzer) Packets() uint64 {
+	return a.packets
+}
+
+func (a *analyzer) report(c Check, pid int, format string, args ...interface{}) {
+	a.counts[c]++
+	e := Event{
+		Check:       c,
+		PID:         pid,
+		Time:        a.now,
+		Packet:      a.packets,
+		Description: fmt.Sprintf(format, args...),
+	}
+	for _, h := range a.handlers {
+		h(e)
+	}
+}
+
+func (a *analyzer) WritePacket(pkt *packet.Packet) (int, error) {
+	return a.WritePacketAt(pkt, a.clock())
+}
+
+func (a *analyzer) WritePacketAt(pkt *packet.Packet, t time.Time) (int, error) {
+	a.now = t
+	if a.packets == 0 {
+		a.start = t
+		a.lastPAT = t
+	}
+	defer func() { a.packets++ }()
+
+	if !a.checkSync(pkt) {
+		return packet.PacketSize, nil
+	}
+
+	pid := packet.Pid(pkt)
+	if pkt.TransportErrorIndicator() {
+		a.report(TransportError, pid, "transport_error_indicator is set")
+		// the rest of the packet can not be trusted
+		return packet.PacketSize, nil
+	}
+
+	a.checkTimers()
+
+	if packet.IsNull(pkt) {
+		return packet.PacketSize, nil
+	}
+
+	event := a.cc.Update(pkt)
+	switch event.Result {
+	case packet.ContinuityLoss:
+		a.report(CCError, pid, "expected continuity counter %d, got %d (%d packets lost)", event.Expected, event.Got, event.Missing)
+	case packet.ContinuityIllegalRepeat:
+		a.report(CCError, pid, "continuity counter %d repeated more than once", event.Got)
+	}
+
+	scrambled := pkt.TransportScramblingControl() != packet.NoScrambleFlag
+	if scrambled && !a.catSeen && !a.noCAT[pid] {
+		a.noCAT[pid] = true
+		a.report(CATError, pid, "scrambled packet found without a CAT")
+	}
+
+	if _, ok := a.lastSeen[pid]; ok {
+		a.lastSeen[pid] = t
+	}
+	a.checkReferenced(pid)
+
+	a.checkPCR(pid, pkt)
+
+	switch {
+	case pid == psi.PatPid:
+		if scrambled {
+			a.report(PATError, pid, "PID 0 is scrambled")
+		}
+		a.writeSection(pid, pkt)
+	case pid == psi.CatPid:
+		a.writeSection(pid, pkt)
+	case siPids[pid].allowed != nil:
+		a.writeSection(pid, pkt)
+	case a.isPMTPid(pid):
+		if scrambled {
+			a.report(PMTError, pid, "PMT PID is scrambled")
+		}
+		a.writeSection(pid, pkt)
+	default:
+		if _, ok := a.lastSeen[pid]; ok && !scrambled {
+			a.checkPTS(pid, pkt)
+		}
+	}
+
+	return packet.PacketSize, nil
+}
+
+// checkSync returns true if the packet is in sync and should be analyzed further.
+func (a *analyzer) checkSync(pkt *packet.Packet) bool {
+	if pkt[0] != packet.SyncByte {
+		a.goodSync = 0
+		a.badSync++
+		a.report(SyncByteError, packet.Pid(pkt), "sync byte is 0x%02X", pkt[0])
+		if a.badSync == syncLossThreshold && !a.syncLost {
+			a.syncLost = true
+			a.report(TSSyncLoss, packet.Pid(pkt), "%d consecutive corrupted sync bytes", a.badSync)
+		}
+		return false
+	}
+	a.badSync = 0
+	if a.syncLost {
+		a.goodSync++
+		if a.goodSync < syncRegainThreshold {
+			return false
+		}
+		a.syncLost = false
+	}
+	return true
+}
+
+// checkTimers raises errors for tables and PIDs that have not occurred in time.
+func (a *analyzer) checkTimers() {
+	if a.now.Sub(a.lastPAT) > PATInterval {
+		a.report(PATError, psi.PatPid, "no PAT for %v", a.now.Sub(a.lastPAT))
+		a.lastPAT = a.now
+	}
+	for pid, last := range a.pmts {
+		if a.now.Sub(last) > PMTInterval {
+			a.report(PMTError, pid, "no PMT for %v", a.now.Sub(last))
+			a.pmts[pid] = a.now
+		}
+	}
+	for pid, last := range a.lastSeen {
+		if a.now.Sub(last) > a.pidTimeout {
+			a.report(PIDError, pid, "referenced PID missing for %v", a.now.Sub(last))
+			a.lastSeen[pid] = a.now
+		}
+	}
+	for key, last := range a.siLast {
+		timeout := siTimeouts[key]
+		if a.now.Sub(last) > timeout.interval {
+			a.report(timeout.check, key.pid, "no section %d of table 0x%02X for %v", key.section, key.tableID, a.now.Sub(last))
+			a.siLast[key] = a.now
+		}
+	}
+	for pid, first := range a.unreferenced {
+		if a.now.Sub(first) > UnreferencedPIDTime {
+			a.report(UnreferencedPID, pid, "PID not referenced for %v", a.now.Sub(first))
+			delete(a.unreferenced, pid)
+			a.reported[pid] = true
+		}
+	}
+}
+
+// isReferenced returns true for reserved PIDs and PIDs referenced by the
+// PAT, a PMT or the CAT.
+func (a *analyzer) isReferenced(pid int) bool {
+	if pid < 0x20 || pid == packet.NullPacketPid || a.isPMTPid(pid) || a.catRefs[pid] {
+		return true
+	}
+	if _, ok := a.lastSeen[pid]; ok {
+		return true
+	}
+	for _, refs := range a.pmtRefs {
+		for _, ref := range refs {
+			if ref == pid {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkReferenced starts the Unreferenced_PID timer of a PID when it is
+// first seen.
+func (a *analyzer) checkReferenced(pid int) {
+	if a.reported[pid] {
+		return
+	}
+	if _, ok := a.unreferenced[pid]; !ok && !a.isReferenced(pid) {
+		a.unreferenced[pid] = a.now
+	}
+}
+
+// updateReferences stops the Unreferenced_PID timers of PIDs that have
+// become referenced.
+func (a *analyzer) updateReferences() {
+	for pid := range a.unreferenced {
+		if a.isReferenced(pid) {
+			delete(a.unreferenced, pid)
+		}
+	}
+	for pid := range a.reported {
+		if a.isReferenced(pid) {
+			delete(a.reported, pid)
+		}
+	}
+}
+
+func (a *analyzer) isPMTPid(pid int) bool {
+	_, ok := a.pmts[pid]
+	return ok
+}
+
+func (a *analyzer) writeSection(pid int, pkt *packet.Packet) {
+	acc, ok := a.sections[pid]
+	if !ok {
+		acc = packet.NewAccumulator(psi.PmtAccumulatorDoneFunc)
+		a.sections[pid] = acc
+	}
+	if _, err := acc.WritePacket(pkt); err != gots.ErrAccumulatorDone {
+		return
+	}
+	b := acc.Bytes()
+	acc.Reset()
+
+	for _, section := range psi.SplitSections(b) {
+		a.checkSection(pid, section)
+	}
+}
+
+func (a *analyzer) checkSection(pid int, section []byte) {
+	tableID := section[0]
+	switch {
+	case pid == psi.PatPid && tableID != 0x00:
+		a.report(PATError, pid, "section with table_id 0x%02X found on PID 0", tableID)
+		return
+	case pid == psi.CatPid && tableID != 0x01:
+		a.report(CATError, pid, "section with table_id 0x%02X found on PID 1", tableID)
+		return
+	case a.isPMTPid(pid) && tableID != 0x02:
+		a.report(PMTError, pid, "section with table_id 0x%02X found on PMT PID", tableID)
+		return
+	case siPids[pid].allowed != nil && !siPids[pid].allowed(tableID):
+		a.report(siPids[pid].check, pid, "section with table_id 0x%02X found on PID 0x%02X", tableID, pid)
+		return
+	}
+
+	// TDT sections have no CRC, TOT sections use the short syntax but carry one.
+	hasCRC := section[1]&0x80 != 0 || tableID == 0x73
+	if hasCRC && len(section) >= 7 {
+		if gots.CheckCRC(section) != nil {
+			a.report(CRCError, pid, "CRC mismatch in section with table_id 0x%02X", tableID)
+			return
+		}
+	}
+
+	switch {
+	case pid == psi.PatPid:
+		a.lastPAT = a.now
+		a.updatePAT(section)
+	case pid == psi.CatPid:
+		a.catSeen = true
+		a.updateCAT(section)
+	case a.isPMTPid(pid):
+		a.pmts[pid] = a.now
+		a.updatePMT(pid, section)
+	case siPids[pid].allowed != nil:
+		a.updateSI(pid, section)
+	}
+}
+
+// updateSI checks the repetition of a DVB SI section and restarts its timeout.
+func (a *analyzer) updateSI(pid int, section []byte) {
+	key := siKey{pid: pid, tableID: section[0], section: -1}
+	if section[1]&0x80 != 0 && len(section) >= 8 {
+		key.extension = uint16(section[3])<<8 | uint16(section[4])
+		key.section = int(section[6])
+	}
+	if key.tableID != 0x72 {
+		if last, ok := a.siSections[key]; ok && a.now.Sub(last) < SIMinInterval {
+			a.report(SIRepetitionError, pid, "section %d of table 0x%02X repeated after %v", key.section, key.tableID, a.now.Sub(last))
+		}
+		a.siSections[key] = a.now
+	}
+
+	for _, k := range []siKey{{pid: pid, tableID: key.tableID, section: -1}, {pid: pid, tableID: key.tableID, section: key.section}} {
+		if _, ok := siTimeouts[k]; ok {
+			a.siLast[k] = a.now
+		}
+	}
+}
+
+func (a *analyzer) updateCAT(section []byte) {
+	cat, err := psi.NewCAT(append([]byte{0}, section...))
+	if err != nil {
+		return
+	}
+	cas, _ := psi.DecodeCADescriptors(cat.Descriptors())
+	a.catRefs = make(map[int]bool)
+	for _, ca := range cas {
+		a.catRefs[ca.PID] = true
+	}
+	a.updateReferences()
+}
+
+func (a *analyzer) updatePAT(section []byte) {
+	pat, err := psi.NewPAT(append([]byte{0}, section...))
+	if err != nil {
+		return
+	}
+	programs := pat.ProgramMap()
+	for pid := range a.pmts {
+		found := false
+		for _, pmtPid := range programs {
+			found = found || pmtPid == pid
+		}
+		if !found {
+			a.removePMT(pid)
+		}
+	}
+	for _, pid := range programs {
+		if !a.isPMTPid(pid) {
+			a.pmts[pid] = a.now
+		}
+	}
+	a.updateReferences()
+}
+
+func (a *analyzer) removePMT(pid int) {
+	for _, esPid := range a.esPids[pid] {
+		delete(a.lastSeen, esPid)
+		delete(a.pcrPids, esPid)
+		delete(a.lastPTS, esPid)
+	}
+	delete(a.esPids, pid)
+	delete(a.pmtRefs, pid)
+	delete(a.pmts, pid)
+	delete(a.sections, pid)
+}
+
+func (a *analyzer) updatePMT(pid int, section []byte) {
+	pmt, err := psi.NewPMT(append([]byte{0}, section...))
+	if err != nil {
+		return
+	}
+	for _, esPid := range a.esPids[pid] {
+		if !pmt.PIDExists(esPid) {
+			delete(a.lastSeen, esPid)
+			delete(a.pcrPids, esPid)
+			delete(a.lastPTS, esPid)
+		}
+	}
+	a.esPids[pid] = pmt.Pids()
+	for _, esPid := range pmt.Pids() {
+		if _, ok := a.lastSeen[esPid]; !ok {
+			a.lastSeen[esPid] = a.now
+		}
+		a.pcrPids[esPid] = pmt.PCRPid()
+	}
+
+	refs := []int{pmt.PCRPid()}
+	descriptors := pmt.ProgramDescriptors()
+	for _, es := range pmt.ElementaryStreams() {
+		descriptors = append(descriptors, es.Descriptors()...)
+	}
+	cas, _ := psi.DecodeCADescriptors(descriptors)
+	for _, ca := range cas {
+		refs = append(refs, ca.PID)
+	}
+	a.pmtRefs[pid] = refs
+	a.updateReferences()
+}
+
+func (a *analyzer) checkPTS(pid int, pkt *packet.Packet) {
+	if !packet.PayloadUnitStartIndicator(pkt) {
+		return
+	}
+	b, err := packet.PESHeader(pkt)
+	if err != nil {
+		return
+	}
+	header, err := pes.NewPESHeader(b)
+	if err != nil || !header.HasPTS() {
+		return
+	}
+	pcrPid, ok := a.pcrPids[pid]
+	if !ok {
+		return
+	}
+	now, ok := a.pcrNow(pcrPid)
+	if !ok {
+		return
+	}
+	if last, ok := a.lastPTS[pid]; ok {
+		if d := pcrDuration(last, now); d > PTSInterval {
+			a.report(PTSError, pid, "%v between PTSs", d)
+		}
+	}
+	a.lastPTS[pid] = now
+}
+
+// pcrNow estimates the PCR at the current packet from the last PCR on
+// pcrPid and the PCR rate. It returns false before the first PCR.
+func (a *analyzer) pcrNow(pcrPid int) (uint64, bool) {
+	last, ok := a.pcrs[pcrPid]
+	if !ok {
+		return 0, false
+	}
+	elapsed := uint64(last.ticksPerPacket * float64(a.packets-last.packet))
+	return (last.pcr + elapsed) % pcrModulus, true
+}
+
+// pcrDuration returns the time between two PCR values, allowing for
+// wrap around.
+func pcrDuration(from, to uint64) time.Duration {
+	ticks := (to + pcrModulus - from) % pcrModulus
+	return time.Duration(ticks * 1000 / 27)
+}
+
+func (a *analyzer) checkPCR(pid int, pkt *packet.Packet) {
+	af, err := pkt.AdaptationField()
+	if err != nil {
+		return
+	}
+	pcr, err := af.PCR()
+	if err != nil {
+		return
+	}
+	if discontinuous, _ := af.Discontinuity(); discontinuous {
+		a.pcrs[pid] = &pcrState{pcr: pcr, packet: a.packets}
+		// PTS intervals can not be measured across the time base change
+		for esPid, pcrPid := range a.pcrPids {
+			if pcrPid == pid {
+				delete(a.lastPTS, esPid)
+			}
+		}
+		return
+	}
+
+	last, ok := a.pcrs[pid]
+	if !ok {
+		a.pcrs[pid] = &pcrState{pcr: pcr, packet: a.packets}
+		return
+	}
+
+	if d := pcrDuration(last.pcr, pcr); d > PCRInterval {
+		a.report(PCRRepetitionError, pid, "%v between PCRs", d)
+	}
+
+	ticks := float64((pcr + pcrModulus - last.pcr) % pcrModulus)
+	packets := float64(a.packets - last.packet)
+	if last.ticksPerPacket > 0 {
+		expected := last.ticksPerPacket * packets
+		if diff := ticks - expected; diff > pcrAccuracyTicks || diff < -pcrAccuracyTicks {
+			a.report(PCRAccuracyError, pid, "PCR is off by %.0fns", diff*1000/27)
+		}
+	}
+
+	last.ticksPerPacket = ticks / packets
+	last.pcr = pcr
+	last.packet = a.packets
+}
@@ -0,0 +1,394 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package tr101290
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Comcast/gots/v3"
+	"github.com/Comcast/gots/v3/packet"
+)
+
+var epoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// testStream feeds packets to an analyzer, keeping track of continuity
+// counters and advancing time by a fixed amount per packet.
+type testStream struct {
+	t        *testing.T
+	analyzer Analyzer
+	now      time.Time
+	step     time.Duration
+	cc       map[int]int
+}
+
+func newTestStream(t *testing.T, step time.Duration, opts ...Option) *testStream {
+	return &testStream{
+		t:        t,
+		analyzer: NewAnalyzer(opts...),
+		now:      epoch,
+		step:     step,
+		cc:       make(map[int]int),
+	}
+}
+
+func (s *testStream) write(pkt packet.Packet) {
+	pid := pkt.PID()
+	pkt.SetContinuityCounter(s.cc[pid])
+	s.cc[pid]++
+	s.writeRaw(pkt)
+}
+
+// writeAdaptationOnly writes a packet without payload, whose continuity
+// counter repeats the one of the previous packet on the PID.
+func (s *testStream) writeAdaptationOnly(pkt packet.Packet) {
+	pid := pkt.PID()
+	pkt.SetContinuityCounter(s.cc[pid] + 15)
+	s.writeRaw(pkt)
+}
+
+func (s *testStream) writeRaw(pkt packet.Packet) {
+	if _, err := s.analyzer.WritePacketAt(&pkt, s.now); err != nil {
+		s.t.Fatalf("WritePacketAt returned an error: %v", err)
+	}
+	s.now = s.now.Add(s.step)
+}
+
+// writeTables writes the test PAT and PMT, which reference PIDs 101, 102 and 110.
+func (s *testStream) writeTables() {
+	s.write(packet.TestPatPacket)
+	s.write(packet.TestPmtPacket)
+}
+
+func (s *testStream) writeES(pids ...int) {
+	for _, pid := range pids {
+		s.write(*packet.Create(pid, packet.WithHasPayloadFlag))
+	}
+}
+
+func (s *testStream) expect(counts map[Check]uint64) {
+	s.t.Helper()
+	for _, c := range Checks {
+		if got := s.analyzer.Count(c); got != counts[c] {
+			s.t.Errorf("%v: expected %d errors, got %d", c, counts[c], got)
+		}
+	}
+}
+
+func pcrPacket(pid int, pcr uint64) packet.Packet {
+	pkt := packet.Create(pid)
+	pkt.SetAdaptationFieldControl(packet.AdaptationFieldFlag)
+	af, _ := pkt.AdaptationField()
+	af.SetHasPCR(true)
+	af.SetPCR(pcr)
+	return *pkt
+}
+
+func TestAnalyzerCleanStream(t *testing.T) {
+	s := newTestStream(t, 10*time.Millisecond)
+	for i := 0; i < 100; i++ {
+		if i%10 == 0 {
+			s.writeTables()
+		}
+		s.writeES(101, 102, 110)
+	}
+	s.expect(nil)
+	if s.analyzer.Packets() != 320 {
+		t.Errorf("expected 320 packets, got %d", s.analyzer.Packets())
+	}
+}
+
+func TestAnalyzerSync(t *testing.T) {
+	s := newTestStream(t, time.Millisecond)
+	s.writeTables()
+
+	bad := *packet.Create(101, packet.WithHasPayloadFlag)
+	bad[0] = 0x46
+	s.writeRaw(bad)
+	s.writeES(101)
+	s.writeRaw(bad)
+	s.writeRaw(bad)
+	s.writeRaw(bad)
+	// Packets are ignored until sync is regained
+	s.writeES(102, 102, 102, 102)
+	s.write(packet.TestPatPacket)
+	s.writeES(101)
+
+	s.expect(map[Check]uint64{
+		SyncByteError: 4,
+		TSSyncLoss:    1,
+	})
+}
+
+func TestAnalyzerContinuity(t *testing.T) {
+	s := newTestStream(t, time.Millisecond)
+	s.writeTables()
+	s.writeES(101, 101)
+	s.cc[101]++
+	s.writeES(101, 101)
+	s.expect(map[Check]uint64{
+		CCError: 1,
+	})
+}
+
+func TestAnalyzerTransportError(t *testing.T) {
+	s := newTestStream(t, time.Millisecond)
+	s.writeTables()
+	pkt := *packet.Create(101, packet.WithHasPayloadFlag)
+	pkt.SetTransportErrorIndicator(true)
+	s.write(pkt)
+	s.expect(map[Check]uint64{
+		TransportError: 1,
+	})
+}
+
+func TestAnalyzerPATAndPMTRepetition(t *testing.T) {
+	s := newTestStream(t, 100*time.Millisecond)
+	s.writeTables()
+	s.writeES(101, 102, 110, 101, 102, 110)
+	s.expect(map[Check]uint64{
+		PATError: 1,
+		PMTError: 1,
+	})
+}
+
+func TestAnalyzerTableIDs(t *testing.T) {
+	s := newTestStream(t, time.Millisecond)
+	s.writeTables()
+
+	// A PMT on PID 0
+	pkt := packet.TestPmtPacket
+	pkt.SetPID(0)
+	s.write(pkt)
+
+	// A PAT on the PMT PID
+	pkt = packet.TestPatPacket
+	pkt.SetPID(100)
+	s.write(pkt)
+
+	// A PAT on the CAT PID
+	pkt = packet.TestPatPacket
+	pkt.SetPID(1)
+	s.write(pkt)
+
+	s.expect(map[Check]uint64{
+		PATError: 1,
+		PMTError: 1,
+		CATError: 1,
+	})
+}
+
+func TestAnalyzerCRC(t *testing.T) {
+	s := newTestStream(t, time.Millisecond)
+	pkt := packet.TestPatPacket
+	pkt[10]++
+	s.write(pkt)
+	s.expect(map[Check]uint64{
+		CRCError: 1,
+	})
+}
+
+func TestAnalyzerPIDError(t *testing.T) {
+	s := newTestStream(t, 100*time.Millisecond, WithPIDTimeout(time.Second))
+	for i := 0; i < 4; i++ {
+		s.writeTables()
+		s.writeES(101, 102)
+	}
+	s.expect(map[Check]uint64{
+		PIDError: 1,
+	})
+
+	var events []Event
+	s.analyzer.OnEvent(func(e Event) {
+		events = append(events, e)
+	})
+	for i := 0; i < 3; i++ {
+		s.writeTables()
+		s.writeES(101, 102)
+	}
+	if len(events) != 1 || events[0].Check != PIDError || events[0].PID != 110 {
+		t.Errorf("expected a single PID_error on PID 110, got %v", events)
+	}
+}
+
+func TestAnalyzerPCR(t *testing.T) {
+	s := newTestStream(t, 10*time.Millisecond)
+	s.writeTables()
+
+	var pcr uint64
+	for i := 0; i < 5; i++ {
+		s.write(pcrPacket(101, pcr))
+		s.writeES(102)
+		pcr += 2 * 10 * 27000 // two packets at 10ms each
+	}
+	s.expect(nil)
+
+	// Jitter the PCR by 1us
+	s.write(pcrPacket(101, pcr+27))
+	s.writeES(102)
+	pcr += 2 * 10 * 27000
+	s.expect(map[Check]uint64{
+		PCRAccuracyError: 1,
+	})
+
+	// PCR repetition is measured on the PCR, not on the arrival time
+	s.writeTables()
+	s.write(pcrPacket(101, pcr+200*27000))
+	s.expect(map[Check]uint64{
+		PCRAccuracyError:   2,
+		PCRRepetitionError: 1,
+	})
+}
+
+func TestAnalyzerPTS(t *testing.T) {
+	// Arrival times are irrelevant, PTS intervals are measured on the PCR
+	// of the program, which is carried on PID 101.
+	s := newTestStream(t, time.Millisecond)
+	pts := func() packet.Packet {
+		pkt := packet.Create(101, packet.WithPUSI)
+		packet.WithPES(pkt, 0)
+		return *pkt
+	}
+	for i := 0; i < 24; i++ {
+		s.writeTables()
+		s.writeAdaptationOnly(pcrPacket(101, uint64(i)*90*27000))
+		if i%8 == 0 {
+			s.write(pts())
+		} else {
+			s.writeES(101)
+		}
+	}
+	s.expect(map[Check]uint64{
+		PTSError: 2,
+	})
+}
+
+func TestAnalyzerScrambledWithoutCAT(t *testing.T) {
+	s := newTestStream(t, time.Millisecond)
+	s.writeTables()
+	// Reported once per PID however many scrambled packets follow
+	for i := 0; i < 100; i++ {
+		for _, pid := range []int{101, 102} {
+			pkt := *packet.Create(pid, packet.WithHasPayloadFlag)
+			pkt.SetTransportScramblingControl(packet.ScrambleEvenKeyFlag)
+			s.write(pkt)
+		}
+	}
+	s.expect(map[Check]uint64{
+		CATError: 2,
+	})
+}
+
+// siPacket returns a packet carrying a DVB SI section with the long syntax.
+func siPacket(pid int, tableID uint8, extension uint16, number uint8) packet.Packet {
+	section := []byte{tableID, 0xF0, 0x0A, byte(extension >> 8), byte(extension), 0xC1, number, number, 0x00}
+	crc := gots.CRC32(section)
+	section = append(section, byte(crc>>24), byte(crc>>16), byte(crc>>8), byte(crc))
+	pkt := packet.Create(pid, packet.WithHasPayloadFlag, packet.WithPUSI)
+	payload := append([]byte{0x00}, section...)
+	for len(payload) < packet.PacketSize-4 {
+		payload = append(payload, 0xFF)
+	}
+	copy(pkt[4:], payload)
+	return *pkt
+}
+
+func TestAnalyzerSITableIDs(t *testing.T) {
+	s := newTestStream(t, time.Millisecond)
+	s.writeTables()
+	for _, pid := range []int{0x10, 0x11, 0x12, 0x13, 0x14} {
+		s.write(siPacket(pid, 0x02, 1, 0))
+	}
+	// The stuffing table is allowed on every SI PID
+	s.write(siPacket(0x11, 0x72, 1, 0))
+	s.expect(map[Check]uint64{
+		NITActualError: 1,
+		SDTActualError: 1,
+		EITActualError: 1,
+		RSTError:       1,
+		TDTError:       1,
+	})
+}
+
+func TestAnalyzerSIRepetition(t *testing.T) {
+	s := newTestStream(t, 10*time.Millisecond)
+	s.writeTables()
+	s.write(siPacket(0x11, 0x42, 1, 0))
+	s.write(siPacket(0x11, 0x42, 1, 0))
+	// Other sections of the same table may follow immediately
+	s.write(siPacket(0x11, 0x42, 1, 1))
+	s.write(siPacket(0x11, 0x42, 2, 0))
+	s.now = s.now.Add(SIMinInterval)
+	s.write(siPacket(0x11, 0x42, 1, 0))
+	s.expect(map[Check]uint64{
+		SIRepetitionError: 1,
+	})
+}
+
+func TestAnalyzerSITimeouts(t *testing.T) {
+	s := newTestStream(t, 10*time.Millisecond)
+	for i := 0; i < 70; i++ {
+		s.writeTables()
+		s.writeES(101, 102, 110)
+		if i < 10 {
+			s.write(siPacket(0x11, 0x42, 1, 0))
+			s.write(siPacket(0x12, 0x4E, 1, 0))
+			s.write(siPacket(0x12, 0x4E, 1, 1))
+		} else if i < 20 {
+			// Only section 0 of the present/following table
+			s.write(siPacket(0x12, 0x4E, 1, 0))
+		}
+	}
+	s.expect(map[Check]uint64{
+		SDTActualError: 1,
+		EITActualError: 2,
+	})
+}
+
+func TestAnalyzerUnreferencedPID(t *testing.T) {
+	s := newTestStream(t, 10*time.Millisecond)
+	for i := 0; i < 10; i++ {
+		s.writeTables()
+		s.writeES(101, 102, 110, 200, 0x15, packet.NullPacketPid)
+	}
+	s.expect(map[Check]uint64{
+		UnreferencedPID: 1,
+	})
+}
+
+func TestCheckPriority(t *testing.T) {
+	if TSSyncLoss.Priority() != 1 || PIDError.Priority() != 1 {
+		t.Error("expected first priority")
+	}
+	if TransportError.Priority() != 2 || CATError.Priority() != 2 {
+		t.Error("expected second priority")
+	}
+	if SDTActualError.Priority() != 3 || UnreferencedPID.Priority() != 3 {
+		t.Error("expected third priority")
+	}
+	if CCError.String() != "Continuity_count_error" {
+		t.Errorf("unexpected name %q", CCError.String())
+	}
+}
@@ -0,0 +1,174 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package tr101290
+
+import "time"
+
+// Check identifies a single TR 101 290 measurement.
+type Check int
+
+// First priority checks are necessary for decodability,
+// second priority checks are recommended for continuous monitoring and
+// third priority checks verify the DVB service information. Of the third
+// priority, Buffer_error, Empty_buffer_error and Data_delay_error are not
+// performed since they need a model of the decoder buffers, and neither are
+// the optional NIT_other_error, SDT_other_error and EIT_other_error. The SI
+// table timeouts start once a table has been seen, so that streams without
+// DVB SI do not raise them.
+const (
+	// TSSyncLoss is raised when two or more consecutive packets have a corrupted sync byte.
+	TSSyncLoss Check = iota
+	// SyncByteError is raised when a packet's sync byte is not 0x47.
+	SyncByteError
+	// PATError is raised when no PAT is seen for 0.5 seconds, when a section other
+	// than a PAT is found on PID 0 or when PID 0 is scrambled.
+	PATError
+	// CCError is raised for packets that are lost, out of order or repeated
+	// more than once.
+	CCError
+	// PMTError is raised when no PMT is seen on a PMT PID for 0.5 seconds, when a
+	// section other than a PMT is found on a PMT PID or when a PMT PID is scrambled.
+	PMTError
+	// PIDError is raised when a PID referenced by a PMT does not occur for a
+	// configurable period of time, 5 seconds by default.
+	PIDError
+	// TransportError is raised when the transport_error_indicator is set.
+	TransportError
+	// CRCError is raised when the CRC of a PAT, CAT, PMT, NIT, SDT, BAT, EIT or TOT section is wrong.
+	CRCError
+	// PCRRepetitionError is raised when the values of two consecutive PCRs on a
+	// PID are more than 100ms apart.
+	PCRRepetitionError
+	// PCRAccuracyError is raised when a PCR differs from the value predicted by the
+	// transport rate by more than 500ns.
+	PCRAccuracyError
+	// PTSError is raised when two consecutive PTSs on a PID are more than 700ms
+	// apart, measured on the PCR of the program. PTSs are not checked before
+	// the program's first PCR.
+	PTSError
+	// CATError is raised when scrambled packets are found without a CAT or when a
+	// section other than a CAT is found on PID 1.
+	CATError
+	// NITActualError is raised when a section other than a NIT or stuffing is
+	// found on PID 0x10, or when no NIT actual section is seen for 10 seconds.
+	NITActualError
+	// SIRepetitionError is raised when a section of a DVB SI table is repeated
+	// within 25ms.
+	SIRepetitionError
+	// UnreferencedPID is raised when a PID is not referenced by the PAT, a PMT
+	// or the CAT within 0.5 seconds of its first occurrence.
+	UnreferencedPID
+	// SDTActualError is raised when a section other than a SDT, BAT or stuffing
+	// is found on PID 0x11, or when no SDT actual section is seen for 2 seconds.
+	SDTActualError
+	// EITActualError is raised when a section other than an EIT or stuffing is
+	// found on PID 0x12, or when section 0 or 1 of the EIT present/following
+	// actual is not seen for 2 seconds.
+	EITActualError
+	// RSTError is raised when a section other than a RST or stuffing is found
+	// on PID 0x13.
+	RSTError
+	// TDTError is raised when a section other than a TDT, TOT or stuffing is
+	// found on PID 0x14, or when no TDT is seen for 30 seconds.
+	TDTError
+)
+
+// Checks lists all checks performed by the Analyzer.
+var Checks = []Check{
+	TSSyncLoss,
+	SyncByteError,
+	PATError,
+	CCError,
+	PMTError,
+	PIDError,
+	TransportError,
+	CRCError,
+	PCRRepetitionError,
+	PCRAccuracyError,
+	PTSError,
+	CATError,
+	NITActualError,
+	SIRepetitionError,
+	UnreferencedPID,
+	SDTActualError,
+	EITActualError,
+	RSTError,
+	TDTError,
+}
+
+// CheckNames maps checks to the indicator names used in TR 101 290.
+var CheckNames = map[Check]string{
+	TSSyncLoss:         "TS_sync_loss",
+	SyncByteError:      "Sync_byte_error",
+	PATError:           "PAT_error",
+	CCError:            "Continuity_count_error",
+	PMTError:           "PMT_error",
+	PIDError:           "PID_error",
+	TransportError:     "Transport_error",
+	CRCError:           "CRC_error",
+	PCRRepetitionError: "PCR_repetition_error",
+	PCRAccuracyError:   "PCR_accuracy_error",
+	PTSError:           "PTS_error",
+	CATError:           "CAT_error",
+	NITActualError:     "NIT_actual_error",
+	SIRepetitionError:  "SI_repetition_error",
+	UnreferencedPID:    "Unreferenced_PID",
+	SDTActualError:     "SDT_actual_error",
+	EITActualError:     "EIT_actual_error",
+	RSTError:           "RST_error",
+	TDTError:           "TDT_error",
+}
+
+// String returns the TR 101 290 name of the check.
+func (c Check) String() string {
+	if name, ok := CheckNames[c]; ok {
+		return name
+	}
+	return "unknown check"
+}
+
+// Priority returns the TR 101 290 priority of the check, 1 through 3.
+func (c Check) Priority() int {
+	switch c {
+	case TSSyncLoss, SyncByteError, PATError, CCError, PMTError, PIDError:
+		return 1
+	case TransportError, CRCError, PCRRepetitionError, PCRAccuracyError, PTSError, CATError:
+		return 2
+	}
+	return 3
+}
+
+// Event describes a single failed check.
+type Event struct {
+	Check Check
+	// PID is the PID the error was found on.
+	PID int
+	// Time is the time the error was detected.
+	Time time.Time
+	// Packet is the index of the packet, counting from zero, that triggered the error.
+	Packet uint64
+	// Description gives details about the error.
+	Description string
+}
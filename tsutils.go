@@ -28,32 +28,23 @@ import "encoding/binary"
 
 // ComputeCRC computes the CRC hash for the provided byte slice
 func ComputeCRC(input []byte) []byte {
-	var mask uint32 = 0xffffffff
-	var msb uint32 = 0x80000000
-	var poly uint32 = 0x04c11db7
-	var crc uint32 = 0x46af6449
-
-	for i := 0; i < len(input); i++ {
-		item := uint32(input[i])
-
-		for j := 0; j < 8; j++ {
-			top := crc & msb
-			crc = ((crc << 1) & mask) | ((item >> uint32(7-j)) & 0x1)
-			if top != 0 {
-				crc ^= poly
-			}
-		}
-	}
+	crcBytes := make([]byte, CRC32Size)
+	binary.BigEndian.PutUint32(crcBytes, CRC32(input))
+	return crcBytes
+}
 
-	for i := 0; i < 32; i++ {
-		top := crc & msb
-		crc = ((crc << 1) & mask)
-		if top != 0 {
-			crc ^= poly
-		}
+// CheckCRC verifies the CRC in the last 4 bytes of a section against the
+// CRC computed over the rest of the section. A *CRCMismatchError is returned
+// if they differ.
+func CheckCRC(section []byte) error {
+	if len(section) < 4 {
+		return ErrShortPayload
 	}
-
-	crcBytes := make([]byte, 4)
-	binary.BigEndian.PutUint32(crcBytes, crc)
-	return crcBytes
+	end := len(section) - 4
+	expected := binary.BigEndian.Uint32(section[end:])
+	actual := CRC32(section[:end])
+	if expected != actual {
+		return &CRCMismatchError{Expected: expected, Actual: actual}
+	}
+	return nil
 }
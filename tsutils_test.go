@@ -0,0 +1,136 @@
+/*
+MIT License
+
+Copyright 2016 Comcast Cable Communications Management, LLC
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package gots
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand"
+	"testing"
+)
+
+// bitwiseCRC is the original bit at a time implementation of ComputeCRC,
+// kept as a reference.
+func bitwiseCRC(input []byte) uint32 {
+	var crc uint32 = 0x46af6449
+	for _, item := range input {
+		for j := 0; j < 8; j++ {
+			top := crc & 0x80000000
+			crc = crc<<1 | uint32(item>>uint(7-j))&0x1
+			if top != 0 {
+				crc ^= 0x04c11db7
+			}
+		}
+	}
+	for i := 0; i < 32; i++ {
+		top := crc & 0x80000000
+		crc <<= 1
+		if top != 0 {
+			crc ^= 0x04c11db7
+		}
+	}
+	return crc
+}
+
+func TestCRC32Check(t *testing.T) {
+	// The standard check value of CRC-32/MPEG-2
+	if crc := CRC32([]byte("123456789")); crc != 0x0376e6e7 {
+		t.Errorf("CRC32 returned 0x%08X, expected 0x0376E6E7", crc)
+	}
+}
+
+func TestCRC32MatchesBitwise(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 300; n++ {
+		data := make([]byte, n)
+		r.Read(data)
+		want := bitwiseCRC(data)
+		if got := CRC32(data); got != want {
+			t.Errorf("CRC32 of %d bytes returned 0x%08X, expected 0x%08X", n, got, want)
+		}
+		if got := binary.BigEndian.Uint32(ComputeCRC(data)); got != want {
+			t.Errorf("ComputeCRC of %d bytes returned 0x%08X, expected 0x%08X", n, got, want)
+		}
+	}
+}
+
+func TestCRC32Incremental(t *testing.T) {
+	data := []byte("\x00\xb0\x0d\x00\x01\xc1\x00\x00\x00\x01\xe0\x64")
+	want := CRC32(data)
+	for i := range data {
+		if got := UpdateCRC32(CRC32(data[:i]), data[i:]); got != want {
+			t.Errorf("UpdateCRC32 split at %d returned 0x%08X, expected 0x%08X", i, got, want)
+		}
+	}
+
+	h := NewCRC32()
+	h.Write(data[:5])
+	h.Write(data[5:])
+	if h.Sum32() != want {
+		t.Errorf("Sum32 returned 0x%08X, expected 0x%08X", h.Sum32(), want)
+	}
+	if !bytes.Equal(h.Sum([]byte{0xff}), append([]byte{0xff}, ComputeCRC(data)...)) {
+		t.Errorf("Sum returned %X", h.Sum([]byte{0xff}))
+	}
+	h.Reset()
+	if h.Sum32() != CRC32(nil) {
+		t.Errorf("Reset did not restore the initial value")
+	}
+}
+
+func TestCheckCRC(t *testing.T) {
+	section := []byte{0x00, 0xb0, 0x0d, 0x00, 0x01, 0xcb, 0x00, 0x00, 0x00, 0x01, 0xe0, 0x64}
+	section = append(section, ComputeCRC(section)...)
+	if err := CheckCRC(section); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	section[3]++
+	if err, ok := CheckCRC(section).(*CRCMismatchError); !ok || err.Expected != 0x68d6842e {
+		t.Errorf("Expected a CRC mismatch, got %v", err)
+	}
+}
+
+var benchmarkSection = bytes.Repeat([]byte{0x02, 0xb0, 0x2d, 0x00, 0x01, 0xcb, 0x00, 0x00}, 128)
+
+func BenchmarkComputeCRCBitwise(b *testing.B) {
+	b.SetBytes(int64(len(benchmarkSection)))
+	for i := 0; i < b.N; i++ {
+		bitwiseCRC(benchmarkSection)
+	}
+}
+
+func BenchmarkComputeCRC(b *testing.B) {
+	b.SetBytes(int64(len(benchmarkSection)))
+	for i := 0; i < b.N; i++ {
+		ComputeCRC(benchmarkSection)
+	}
+}
+
+func BenchmarkCRC32(b *testing.B) {
+	b.SetBytes(int64(len(benchmarkSection)))
+	for i := 0; i < b.N; i++ {
+		CRC32(benchmarkSection)
+	}
+}